@@ -0,0 +1,389 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
+	"github.com/nikit34/multiplayer_rpg/pkg/tlsconfig"
+	"github.com/nikit34/multiplayer_rpg/proto"
+)
+
+// admin calls the Admin service of a running server, e.g.
+//
+//	admin -token secret players
+//	admin -token secret kick -reason spam Alice
+
+const callTimeout = 10 * time.Second
+
+type command struct {
+	usage       string
+	description string
+	run         func(ctx context.Context, client proto.AdminClient, args []string) error
+}
+
+var commands map[string]command
+
+// The commands are set up in init, they refer to commands for their usage.
+func init() {
+	commands = map[string]command{
+		"players": {
+			usage:       "players",
+			description: "List the connected players and bots with their latency",
+			run:         listPlayers,
+		},
+		"kick": {
+			usage:       "kick [-reason text] <player>",
+			description: "Disconnect a player by name or ID",
+			run:         kick,
+		},
+		"ban": {
+			usage:       "ban [-account name] [-ip address|range] [-duration d] [-reason text]",
+			description: "Ban an account, IP address or CIDR range and disconnect the matching players",
+			run:         ban,
+		},
+		"unban": {
+			usage:       "unban [-account name] [-ip address|range]",
+			description: "Lift the bans of an account, IP address or CIDR range",
+			run:         unban,
+		},
+		"bans": {
+			usage:       "bans",
+			description: "List the bans",
+			run:         listBans,
+		},
+		"mute": {
+			usage:       "mute <player>",
+			description: "Stop a player from chatting",
+			run:         mute(true),
+		},
+		"unmute": {
+			usage:       "unmute <player>",
+			description: "Let a muted player chat again",
+			run:         mute(false),
+		},
+		"map": {
+			usage:       "map [-room room] [-mode mode] [map]",
+			description: "Change the map or mode of a room and start a new round",
+			run:         changeMap,
+		},
+		"end-round": {
+			usage:       "end-round [-room room] [-restart]",
+			description: "End the round of a room, or restart it right away",
+			run:         endRound,
+		},
+		"add-bot": {
+			usage:       "add-bot [-room room] [name]",
+			description: "Add a bot to a room",
+			run:         addBot,
+		},
+		"remove-bot": {
+			usage:       "remove-bot [-room room] <name>",
+			description: "Remove a bot from a room",
+			run:         removeBot,
+		},
+		"say": {
+			usage:       "say [-room room] <text>",
+			description: "Send a server message to a room, or to all rooms",
+			run:         say,
+		},
+	}
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <command> [command flags] [args]\n\nCommands:\n", os.Args[0])
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %-50s %s\n", commands[name].usage, commands[name].description)
+	}
+	fmt.Fprintf(flag.CommandLine.Output(), "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+// parse parses the flags of a command and checks the number of arguments
+// left.
+func parse(flagSet *flag.FlagSet, args []string, minArgs int, maxArgs int) ([]string, error) {
+	if err := flagSet.Parse(args); err != nil {
+		return nil, err
+	}
+	rest := flagSet.Args()
+	if len(rest) < minArgs || (maxArgs >= 0 && len(rest) > maxArgs) {
+		return nil, fmt.Errorf("usage: %s", commands[flagSet.Name()].usage)
+	}
+	return rest, nil
+}
+
+func listPlayers(ctx context.Context, client proto.AdminClient, args []string) error {
+	if _, err := parse(flag.NewFlagSet("players", flag.ExitOnError), args, 0, 0); err != nil {
+		return err
+	}
+
+	resp, err := client.ListPlayers(ctx, &proto.ListPlayersRequest{})
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "NAME\tID\tROOM\tADDRESS\tLATENCY\tTEAM\tSCORE\tFLAGS")
+	for _, player := range resp.Players {
+		flags := []string{}
+		if player.Bot {
+			flags = append(flags, "bot")
+		}
+		if player.Spectator {
+			flags = append(flags, "spectator")
+		}
+		if player.Muted {
+			flags = append(flags, "muted")
+		}
+		latency := "-"
+		if !player.Bot && player.LatencyMillis > 0 {
+			latency = fmt.Sprintf("%dms", player.LatencyMillis)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\n", player.Name, player.Id, player.RoomName, player.Address, latency, player.Team, player.Score, strings.Join(flags, ","))
+	}
+	return writer.Flush()
+}
+
+func kick(ctx context.Context, client proto.AdminClient, args []string) error {
+	flagSet := flag.NewFlagSet("kick", flag.ExitOnError)
+	reason := flagSet.String("reason", "", "Reason shown to the player")
+	rest, err := parse(flagSet, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Kick(ctx, &proto.KickRequest{Player: rest[0], Reason: *reason})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("kicked %s\n", rest[0])
+	return nil
+}
+
+func ban(ctx context.Context, client proto.AdminClient, args []string) error {
+	flagSet := flag.NewFlagSet("ban", flag.ExitOnError)
+	account := flagSet.String("account", "", "Account name to ban, the player name on servers without accounts")
+	ip := flagSet.String("ip", "", "IP address or CIDR range to ban")
+	duration := flagSet.Duration("duration", 0, "How long the ban lasts, permanent if 0")
+	reason := flagSet.String("reason", "", "Reason shown to the player")
+	if _, err := parse(flagSet, args, 0, 0); err != nil {
+		return err
+	}
+
+	req := &proto.BanRequest{Account: *account, Ip: *ip, Reason: *reason}
+	if *duration != 0 {
+		req.Duration = durationpb.New(*duration)
+	}
+	resp, err := client.Ban(ctx, req)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("banned, %d players disconnected\n", resp.Kicked)
+	return nil
+}
+
+func unban(ctx context.Context, client proto.AdminClient, args []string) error {
+	flagSet := flag.NewFlagSet("unban", flag.ExitOnError)
+	account := flagSet.String("account", "", "Account name to unban")
+	ip := flagSet.String("ip", "", "IP address or CIDR range to unban")
+	if _, err := parse(flagSet, args, 0, 0); err != nil {
+		return err
+	}
+
+	resp, err := client.Unban(ctx, &proto.UnbanRequest{Account: *account, Ip: *ip})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("lifted %d bans\n", resp.Removed)
+	return nil
+}
+
+func listBans(ctx context.Context, client proto.AdminClient, args []string) error {
+	if _, err := parse(flag.NewFlagSet("bans", flag.ExitOnError), args, 0, 0); err != nil {
+		return err
+	}
+
+	resp, err := client.ListBans(ctx, &proto.ListBansRequest{})
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "ACCOUNT\tIP\tCREATED\tEXPIRES\tREASON")
+	for _, ban := range resp.Bans {
+		created := ban.Created.AsTime().Local().Format("2006-01-02 15:04")
+		expires := "never"
+		if ban.Expires != nil {
+			expires = ban.Expires.AsTime().Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", ban.Account, ban.Ip, created, expires, ban.Reason)
+	}
+	return writer.Flush()
+}
+
+func mute(muted bool) func(ctx context.Context, client proto.AdminClient, args []string) error {
+	name := "mute"
+	if !muted {
+		name = "unmute"
+	}
+
+	return func(ctx context.Context, client proto.AdminClient, args []string) error {
+		rest, err := parse(flag.NewFlagSet(name, flag.ExitOnError), args, 1, 1)
+		if err != nil {
+			return err
+		}
+
+		_, err = client.Mute(ctx, &proto.MuteRequest{Player: rest[0], Muted: muted})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%sd %s\n", name, rest[0])
+		return nil
+	}
+}
+
+func changeMap(ctx context.Context, client proto.AdminClient, args []string) error {
+	flagSet := flag.NewFlagSet("map", flag.ExitOnError)
+	room := flagSet.String("room", "", "Room name or ID, the default room if empty")
+	mode := flagSet.String("mode", "", "Game mode, deathmatch or tdm, unchanged if empty")
+	rest, err := parse(flagSet, args, 0, 1)
+	if err != nil {
+		return err
+	}
+	mapName := ""
+	if len(rest) == 1 {
+		mapName = rest[0]
+	}
+	if mapName == "" && *mode == "" {
+		return fmt.Errorf("usage: %s", commands["map"].usage)
+	}
+
+	resp, err := client.ChangeMap(ctx, &proto.ChangeMapRequest{Room: *room, Map: mapName, Mode: *mode})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s now plays %s on %s\n", resp.Room.Name, resp.Room.Mode, resp.Room.Map)
+	return nil
+}
+
+func endRound(ctx context.Context, client proto.AdminClient, args []string) error {
+	flagSet := flag.NewFlagSet("end-round", flag.ExitOnError)
+	room := flagSet.String("room", "", "Room name or ID, the default room if empty")
+	restart := flagSet.Bool("restart", false, "Start the next round right away")
+	if _, err := parse(flagSet, args, 0, 0); err != nil {
+		return err
+	}
+
+	_, err := client.EndRound(ctx, &proto.EndRoundRequest{Room: *room, Restart: *restart})
+	return err
+}
+
+func addBot(ctx context.Context, client proto.AdminClient, args []string) error {
+	flagSet := flag.NewFlagSet("add-bot", flag.ExitOnError)
+	room := flagSet.String("room", "", "Room name or ID, the default room if empty")
+	rest, err := parse(flagSet, args, 0, -1)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.AddBot(ctx, &proto.AddBotRequest{Room: *room, Name: strings.Join(rest, " ")})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("added %s to %s\n", resp.Bot.Name, resp.Bot.RoomName)
+	return nil
+}
+
+func removeBot(ctx context.Context, client proto.AdminClient, args []string) error {
+	flagSet := flag.NewFlagSet("remove-bot", flag.ExitOnError)
+	room := flagSet.String("room", "", "Room name or ID, the default room if empty")
+	rest, err := parse(flagSet, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.RemoveBot(ctx, &proto.RemoveBotRequest{Room: *room, Name: strings.Join(rest, " ")})
+	return err
+}
+
+func say(ctx context.Context, client proto.AdminClient, args []string) error {
+	flagSet := flag.NewFlagSet("say", flag.ExitOnError)
+	room := flagSet.String("room", "", "Room name or ID, all rooms if empty")
+	rest, err := parse(flagSet, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Broadcast(ctx, &proto.BroadcastRequest{Room: *room, Text: strings.Join(rest, " ")})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("sent to %d rooms\n", resp.Rooms)
+	return nil
+}
+
+func main() {
+	address := flag.String("address", ":8888", "Server address")
+	token := flag.String("token", os.Getenv("TSHOOTER_ADMIN_TOKEN"), "Admin token of the server, defaults to $TSHOOTER_ADMIN_TOKEN")
+	tlsOptions := tlsconfig.ClientOptions{}
+	flag.StringVar(&tlsOptions.CAFile, "ca", "", "CA certificate used to verify the server, enables TLS")
+	flag.StringVar(&tlsOptions.Pin, "pin", "", "SHA-256 fingerprint the server certificate key must match, enables TLS")
+	flag.StringVar(&tlsOptions.CertFile, "cert", "", "Client certificate for trusted hosts")
+	flag.StringVar(&tlsOptions.KeyFile, "key", "", "Client certificate private key")
+	logOptions := logging.RegisterFlags(flag.CommandLine, "")
+	flag.Usage = usage
+	flag.Parse()
+
+	logCloser, err := logOptions.Setup()
+	if err != nil {
+		log.Fatalf("can not set up logging %v", err)
+	}
+	defer logCloser.Close()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	command, ok := commands[flag.Arg(0)]
+	if !ok {
+		log.Fatalf("unknown command %q, run %s -h for the list", flag.Arg(0), os.Args[0])
+	}
+	if *token == "" {
+		log.Fatalf("no admin token provided, use -token or $TSHOOTER_ADMIN_TOKEN")
+	}
+
+	dialOption, err := tlsconfig.DialOption(tlsOptions)
+	if err != nil {
+		log.Fatalf("can not load TLS credentials %v", err)
+	}
+	conn, err := grpc.Dial(*address, dialOption)
+	if err != nil {
+		log.Fatalf("can not connect with server %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+	ctx = metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{"authorization": *token}))
+
+	logging.New("address", *address).Debug("calling the admin service", "command", flag.Arg(0))
+	if err := command.run(ctx, proto.NewAdminClient(conn), flag.Args()[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
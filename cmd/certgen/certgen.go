@@ -0,0 +1,145 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
+	"github.com/nikit34/multiplayer_rpg/pkg/tlsconfig"
+)
+
+// certgen creates a throwaway CA plus server and client certificates signed by
+// it. They are meant for local testing only and must not be used in production.
+
+type keyPair struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newSerial() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		log.Fatalf("failed to generate serial number: %v", err)
+	}
+	return serial
+}
+
+func writePEM(path string, blockType string, data []byte, mode os.FileMode) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		log.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	if err := pem.Encode(file, &pem.Block{Type: blockType, Bytes: data}); err != nil {
+		log.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func createKeyPair(template *x509.Certificate, parent *keyPair, dir string, name string) *keyPair {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		log.Fatalf("failed to generate key: %v", err)
+	}
+
+	parentCert := template
+	parentKey := key
+	if parent != nil {
+		parentCert = parent.cert
+		parentKey = parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		log.Fatalf("failed to create %s certificate: %v", name, err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		log.Fatalf("failed to parse %s certificate: %v", name, err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		log.Fatalf("failed to marshal %s key: %v", name, err)
+	}
+
+	writePEM(filepath.Join(dir, name+".pem"), "CERTIFICATE", der, 0644)
+	writePEM(filepath.Join(dir, name+"-key.pem"), "EC PRIVATE KEY", keyDer, 0600)
+	logging.New().Debug("wrote key pair", "name", name, "subject", template.Subject.CommonName, "expires", template.NotAfter)
+
+	return &keyPair{cert: cert, key: key}
+}
+
+func main() {
+	out := flag.String("out", "certs", "Directory to write the certificates to")
+	hosts := flag.String("hosts", "localhost,127.0.0.1,::1", "Comma separated host names and IPs for the server certificate")
+	validFor := flag.Duration("valid-for", 365*24*time.Hour, "How long the certificates are valid")
+	logOptions := logging.RegisterFlags(flag.CommandLine, "")
+	flag.Parse()
+
+	logCloser, err := logOptions.Setup()
+	if err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+	defer logCloser.Close()
+
+	if err := os.MkdirAll(*out, 0755); err != nil {
+		log.Fatalf("failed to create %s: %v", *out, err)
+	}
+
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := notBefore.Add(*validFor)
+
+	ca := createKeyPair(&x509.Certificate{
+		SerialNumber:          newSerial(),
+		Subject:               pkix.Name{CommonName: "multiplayer-rpg dev CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil, *out, "ca")
+
+	serverTemplate := &x509.Certificate{
+		SerialNumber: newSerial(),
+		Subject:      pkix.Name{CommonName: "multiplayer-rpg server"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range strings.Split(*hosts, ",") {
+		host = strings.TrimSpace(host)
+		if ip := net.ParseIP(host); ip != nil {
+			serverTemplate.IPAddresses = append(serverTemplate.IPAddresses, ip)
+		} else if host != "" {
+			serverTemplate.DNSNames = append(serverTemplate.DNSNames, host)
+		}
+	}
+	server := createKeyPair(serverTemplate, ca, *out, "server")
+
+	createKeyPair(&x509.Certificate{
+		SerialNumber: newSerial(),
+		Subject:      pkix.Name{CommonName: "multiplayer-rpg bot"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, *out, "client")
+
+	fmt.Printf("wrote ca, server and client certificates to %s\n", *out)
+	fmt.Printf("server pin: %s\n", tlsconfig.Fingerprint(server.cert))
+}
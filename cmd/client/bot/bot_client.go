@@ -3,44 +3,71 @@ package main
 import (
 	"flag"
 	"log"
+	"os"
+	"path/filepath"
 
 	"github.com/nikit34/multiplayer_rpg/pkg/backend"
 	"github.com/nikit34/multiplayer_rpg/pkg/bot"
 	"github.com/nikit34/multiplayer_rpg/pkg/client"
 	"github.com/nikit34/multiplayer_rpg/pkg/frontend"
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
+	"github.com/nikit34/multiplayer_rpg/pkg/tlsconfig"
 	"github.com/nikit34/multiplayer_rpg/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 
 func main() {
 	address := flag.String("address", ":8888", "Server address")
+	password := flag.String("password", "", "Server password")
+	roomID := flag.String("room", "", "ID of the room to join, the default room if empty")
+	tlsOptions := tlsconfig.ClientOptions{}
+	flag.StringVar(&tlsOptions.CAFile, "ca", "", "CA certificate used to verify the server, enables TLS")
+	flag.StringVar(&tlsOptions.Pin, "pin", "", "SHA-256 fingerprint the server certificate key must match, enables TLS")
+	flag.StringVar(&tlsOptions.CertFile, "cert", "", "Client certificate for trusted bot hosts")
+	flag.StringVar(&tlsOptions.KeyFile, "key", "", "Client certificate private key")
+	logOptions := logging.RegisterFlags(flag.CommandLine, filepath.Join(os.TempDir(), "tshooter-bot-client.log"))
 	flag.Parse()
 
+	logCloser, err := logOptions.Setup()
+	if err != nil {
+		log.Fatalf("can not set up logging %v", err)
+	}
+	defer logCloser.Close()
+
 	game := backend.NewGame()
 	game.IsAuthoritative = false
 
 	view := frontend.NewView(game)
 	game.Start()
 
-	conn, err := grpc.Dial(*address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	dialOption, err := tlsconfig.DialOption(tlsOptions)
+	if err != nil {
+		log.Fatalf("can not load TLS credentials %v", err)
+	}
+
+	conn, err := grpc.Dial(*address, dialOption)
 	if err != nil {
 		log.Fatalf("can not connect with server %v", err)
 	}
 
 	grpcClient := proto.NewGameClient(conn)
-	client := client.NewGameClient(game, view)
+	gameClient := client.NewGameClient(game, view)
 
 	bots := bot.NewBots(game)
 	player := bots.AddBot("Bob")
 
-	err = client.Connect(grpcClient, player.ID(), player.Name, "")
+	err = gameClient.Connect(grpcClient, client.ConnectOptions{
+		PlayerID:   player.ID(),
+		PlayerName: player.Name,
+		Password:   *password,
+		RoomID:     *roomID,
+	})
 	if err != nil {
 		log.Fatalf("connect request failed %v", err)
 	}
 
-	client.Start()
+	gameClient.Start()
 	view.Start()
 	bots.Start()
 
@@ -48,4 +75,7 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	if reason := gameClient.ExitReason(); reason != "" {
+		log.Fatal(reason)
+	}
 }
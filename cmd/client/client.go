@@ -1,13 +1,20 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"regexp"
 
+	"github.com/nikit34/multiplayer_rpg/pkg/auth"
 	"github.com/nikit34/multiplayer_rpg/pkg/backend"
 	"github.com/nikit34/multiplayer_rpg/pkg/client"
+	"github.com/nikit34/multiplayer_rpg/pkg/discovery"
 	"github.com/nikit34/multiplayer_rpg/pkg/frontend"
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
+	"github.com/nikit34/multiplayer_rpg/pkg/tlsconfig"
 	"github.com/nikit34/multiplayer_rpg/proto"
 
 	termutil "github.com/andrew-d/go-termutil"
@@ -15,6 +22,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/rivo/tview"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 
@@ -24,13 +33,62 @@ const (
 	fieldColor = tcell.Color24
 )
 
+const (
+	modeGuest = iota
+	modeLogin
+	modeRegister
+)
+
+// modeNames are the names of the modes in the connect form.
+var modeNames = []string{"Guest", "Login", "Register"}
+
 type connectInfo struct {
-	PlayerName string
-	Address string
-	Password string
+	PlayerName      string
+	Address         string
+	Password        string
+	Mode            int
+	AccountPassword string
+	RoomID          string
+	RoomPassword    string
+	Spectator       bool
+	Quit            bool
+	// AccountServers are the addresses of the servers that admit accounts
+	// only, the form does not offer to join them as a guest.
+	AccountServers map[string]bool
+}
+
+// modes are the modes the connect form offers for the server at address.
+func (info *connectInfo) modes(address string) []int {
+	if info.AccountServers[address] {
+		return []int{modeLogin, modeRegister}
+	}
+	return []int{modeGuest, modeLogin, modeRegister}
+}
+
+// isAccountRequired tells if the server turned a guest away.
+func isAccountRequired(err error) bool {
+	return status.Code(err) == codes.Unauthenticated && status.Convert(err).Message() == auth.ErrAccountRequired.Error()
+}
+
+// describeConnectError explains why the server turned the player away.
+func describeConnectError(err error) string {
+	message := status.Convert(err).Message()
+	switch status.Code(err) {
+	case codes.PermissionDenied:
+		return fmt.Sprintf(" Access denied: %s", message)
+	case codes.ResourceExhausted:
+		return fmt.Sprintf(" Connection refused: %s", message)
+	case codes.Unavailable:
+		return fmt.Sprintf(" Server unavailable: %s", message)
+	}
+	return fmt.Sprintf(" Connect failed: %s", message)
 }
 
-func connectApp(info *connectInfo) *tview.Application {
+// connectApp asks for the server and player details, prefilled from info.
+// message is shown above the form, like why the last attempt failed. The
+// servers listener finds on the local network are listed above the form if it
+// is not nil, the returned function stops updating them.
+func connectApp(info *connectInfo, message string, listener *discovery.Listener) (*tview.Application, func()) {
 	app := tview.NewApplication()
 	flex := tview.NewFlex().
 		SetDirection(tview.FlexRow)
@@ -38,30 +96,65 @@ func connectApp(info *connectInfo) *tview.Application {
 		SetTitle("Connect to tshooter server").
 		SetBackgroundColor(backgroundColor)
 	errors := tview.NewTextView().
-		SetText(" Use the tab key to change fields, and enter to submit")
+		SetText(message)
 	errors.SetBackgroundColor(backgroundColor)
 	form := tview.NewForm()
+	modeDropDown := tview.NewDropDown().
+		SetLabel("Mode")
+	var modes []int
+	// showModes offers the modes of the server at address, the chosen mode
+	// is kept if the server has it.
+	showModes := func(address string) {
+		mode := info.Mode
+		if current, _ := modeDropDown.GetCurrentOption(); current >= 0 && current < len(modes) {
+			mode = modes[current]
+		}
+		modes = info.modes(address)
+		names := make([]string, len(modes))
+		selected := 0
+		for i, option := range modes {
+			names[i] = modeNames[option]
+			if option == mode {
+				selected = i
+			}
+		}
+		modeDropDown.SetOptions(names, nil).
+			SetCurrentOption(selected)
+	}
+	showModes(info.Address)
 	re := regexp.MustCompile("^[a-zA-Z0-9]+$")
-	form.AddInputField("Player name", "", 16, func(textToCheck string, lastChar rune) bool {
+	form.AddInputField("Player name", info.PlayerName, 16, func(textToCheck string, lastChar rune) bool {
 		result := re.MatchString(textToCheck)
 		if !result {
 			errors.SetText("Only alphanumeric characters are allowed")
 		}
 		return result
 	}, nil).
-		AddInputField("Server address", ":8888", 32, nil, nil).
-		AddPasswordField("Server password", "", 32, '*', nil).
+		AddInputField("Server address", info.Address, 32, nil, showModes).
+		AddPasswordField("Server password", info.Password, 32, '*', nil).
+		AddFormItem(modeDropDown).
+		AddPasswordField("Account password", info.AccountPassword, 32, '*', nil).
+		AddCheckbox("Spectate", info.Spectator, nil).
 		AddButton("Connect", func() {
 			info.PlayerName = form.GetFormItem(0).(*tview.InputField).GetText()
 			info.Address = form.GetFormItem(1).(*tview.InputField).GetText()
 			info.Password = form.GetFormItem(2).(*tview.InputField).GetText()
+			current, _ := modeDropDown.GetCurrentOption()
+			info.Mode = modes[current]
+			info.AccountPassword = form.GetFormItem(4).(*tview.InputField).GetText()
+			info.Spectator = form.GetFormItem(5).(*tview.Checkbox).IsChecked()
 			if info.PlayerName == "" || info.Address == "" {
 				errors.SetText(" All fields are required.")
 				return
 			}
+			if info.Mode != modeGuest && info.AccountPassword == "" {
+				errors.SetText(" An account password is required to login or register.")
+				return
+			}
 			app.Stop()
 		}).
 		AddButton("Quit", func() {
+			info.Quit = true
 			app.Stop()
 		})
 	form.SetLabelColor(textColor).
@@ -69,9 +162,46 @@ func connectApp(info *connectInfo) *tview.Application {
 		SetFieldBackgroundColor(fieldColor).
 		SetBackgroundColor(backgroundColor)
 	flex.AddItem(errors, 1, 1, false)
+	var focus tview.Primitive = form
+	stopDiscovery := func() {}
+	if listener != nil {
+		var list *tview.List
+		list, stopDiscovery = lanServerList(app, listener, func(server *discovery.Server) {
+			// Focus what is still missing to connect.
+			item := form.GetFormItemCount()
+			if server != nil {
+				if server.HasAccounts {
+					info.AccountServers[server.Address] = true
+				}
+				form.GetFormItem(1).(*tview.InputField).SetText(server.Address)
+				if server.HasPassword {
+					item = 2
+				}
+			} else {
+				item = 1
+			}
+			if form.GetFormItem(0).(*tview.InputField).GetText() == "" {
+				item = 0
+			}
+			form.SetFocus(item)
+			app.SetFocus(form)
+		})
+		list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event.Key() == tcell.KeyTab {
+				app.SetFocus(form)
+				return nil
+			}
+			return event
+		})
+		form.SetCancelFunc(func() {
+			app.SetFocus(list)
+		})
+		flex.AddItem(list, 8, 1, false)
+		focus = list
+	}
 	flex.AddItem(form, 0, 1, false)
-	app.SetRoot(flex, true).SetFocus(form)
-	return app
+	app.SetRoot(flex, true).SetFocus(focus)
+	return app, stopDiscovery
 }
 
 func main() {
@@ -79,35 +209,108 @@ func main() {
 		panic("this program must be run in a terminal")
 	}
 
+	tlsOptions := tlsconfig.ClientOptions{}
+	flag.StringVar(&tlsOptions.CAFile, "ca", "", "CA certificate used to verify the server, enables TLS")
+	flag.StringVar(&tlsOptions.Pin, "pin", "", "SHA-256 fingerprint the server certificate key must match, enables TLS")
+	logOptions := logging.RegisterFlags(flag.CommandLine, filepath.Join(os.TempDir(), "tshooter-client.log"))
+	discoveryAddress := flag.String("discovery-addr", fmt.Sprintf(":%d", discovery.DefaultPort), "UDP address to listen for servers on the local network on, off if empty")
+	flag.Parse()
+
+	logCloser, err := logOptions.Setup()
+	if err != nil {
+		log.Fatalf("can not set up logging %v", err)
+	}
+	defer logCloser.Close()
+
+	dialOption, err := tlsconfig.DialOption(tlsOptions)
+	if err != nil {
+		log.Fatalf("can not load TLS credentials %v", err)
+	}
+
 	game := backend.NewGame()
 	game.IsAuthoritative = false
 	view := frontend.NewView(game)
 
 	game.Start()
 
-	info := connectInfo{}
-	connectApp := connectApp(&info)
-	connectApp.Run()
-
-	conn, err := grpc.Dial(info.Address, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("can not connect with server %v", err)
+	info := connectInfo{Address: ":8888", AccountServers: make(map[string]bool)}
+	message := " Use the tab key to change fields, and enter to submit"
+	var listener *discovery.Listener
+	if *discoveryAddress != "" {
+		listener, err = discovery.Listen(*discoveryAddress)
+		if err != nil {
+			log.Printf("can not listen for servers on the local network %v", err)
+		} else {
+			defer listener.Close()
+			message = " Pick a server with the arrow keys and enter, tab goes to the form and escape back"
+		}
 	}
+	var gameClient *client.GameClient
+	// Failed attempts go back to the connect form with the reason.
+	for {
+		connectApp, stopDiscovery := connectApp(&info, message, listener)
+		connectApp.Run()
+		stopDiscovery()
+		if info.Quit {
+			return
+		}
 
-	grpcClient := proto.NewGameClient(conn)
-	client := client.NewGameClient(game, view)
-	playerID := uuid.New()
+		conn, err := grpc.Dial(info.Address, dialOption)
+		if err != nil {
+			message = describeConnectError(err)
+			continue
+		}
 
-	err = client.Connect(grpcClient, playerID, info.PlayerName, info.Password)
-	if err != nil {
-		log.Fatalf("connect request failed %v", err)
+		grpcClient := proto.NewGameClient(conn)
+		gameClient = client.NewGameClient(game, view)
+		playerID := uuid.New()
+
+		if info.Mode == modeRegister {
+			err = gameClient.Register(grpcClient, info.PlayerName, info.AccountPassword, info.Password)
+			if err != nil {
+				conn.Close()
+				message = describeConnectError(err)
+				continue
+			}
+			// The account exists now, so another attempt logs in.
+			info.Mode = modeLogin
+		}
+
+		roomApp := roomApp(gameClient, grpcClient, playerID, &info)
+		roomApp.Run()
+		if info.RoomID == "" {
+			return
+		}
+
+		err = gameClient.Connect(grpcClient, client.ConnectOptions{
+			PlayerID:        playerID,
+			PlayerName:      info.PlayerName,
+			Password:        info.Password,
+			AccountPassword: info.AccountPassword,
+			RoomID:          info.RoomID,
+			RoomPassword:    info.RoomPassword,
+			Spectator:       info.Spectator,
+		})
+		if err != nil {
+			conn.Close()
+			message = describeConnectError(err)
+			if isAccountRequired(err) {
+				info.AccountServers[info.Address] = true
+			}
+			info.RoomID = ""
+			continue
+		}
+		break
 	}
 
-	client.Start()
+	gameClient.Start()
 	view.Start()
 
 	err = <-view.Done
 	if err != nil {
 		log.Fatal(err)
 	}
+	if reason := gameClient.ExitReason(); reason != "" {
+		log.Fatal(reason)
+	}
 }
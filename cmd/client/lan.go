@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/discovery"
+
+	"github.com/rivo/tview"
+)
+
+// lanRefreshInterval is how often the connect screen updates the servers
+// found on the local network.
+const lanRefreshInterval = time.Second
+
+func describeServer(server discovery.Server) (string, string) {
+	name := server.Name
+	if server.HasPassword {
+		name += " (locked)"
+	}
+	if server.HasAccounts {
+		name += " (accounts)"
+	}
+	details := fmt.Sprintf(
+		"%s on %s, %d/%d players, %s",
+		server.Mode,
+		server.Map,
+		server.Players,
+		server.MaxPlayers,
+		server.Address,
+	)
+	return name, details
+}
+
+// lanServerList lists the servers listener hears from and keeps the list up
+// to date until the returned function is called. pick is called with the
+// server the player chose, or nil if they chose to enter the address
+// themselves.
+func lanServerList(app *tview.Application, listener *discovery.Listener, pick func(*discovery.Server)) (*tview.List, func()) {
+	list := tview.NewList().
+		SetMainTextColor(textColor).
+		SetSelectedBackgroundColor(fieldColor)
+	list.SetBorder(true).
+		SetTitle("Servers on the local network").
+		SetBackgroundColor(backgroundColor)
+
+	var servers []discovery.Server
+	refresh := func() {
+		// The selection stays on its server while the list changes, or on
+		// the last entry if the player moved there.
+		selected := ""
+		other := false
+		if current := list.GetCurrentItem(); current < len(servers) {
+			selected = servers[current].Address
+		} else {
+			other = len(servers) > 0
+		}
+
+		servers = listener.Servers()
+		list.Clear()
+		current := 0
+		for i, server := range servers {
+			server := server
+			name, details := describeServer(server)
+			list.AddItem(name, details, 0, func() {
+				pick(&server)
+			})
+			if server.Address == selected {
+				current = i
+			}
+		}
+		secondary := "Type the address of the server in the form"
+		if len(servers) == 0 {
+			secondary = "Searching for servers, or type an address in the form"
+		}
+		list.AddItem("Other server", secondary, 0, func() {
+			pick(nil)
+		})
+		if other {
+			current = len(servers)
+		}
+		list.SetCurrentItem(current)
+	}
+	refresh()
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(lanRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				app.QueueUpdateDraw(refresh)
+			}
+		}
+	}()
+	return list, func() {
+		close(done)
+	}
+}
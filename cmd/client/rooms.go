@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	"github.com/nikit34/multiplayer_rpg/pkg/client"
+	"github.com/nikit34/multiplayer_rpg/proto"
+
+	tcell "github.com/gdamore/tcell/v2"
+	"github.com/google/uuid"
+	"github.com/rivo/tview"
+)
+
+var gameModeNames = []string{"Deathmatch", "Team deathmatch"}
+
+func describeRoom(room *proto.RoomInfo) (string, string) {
+	name := room.Name
+	if room.HasPassword {
+		name += " (locked)"
+	}
+	details := fmt.Sprintf(
+		"%s on %s, %d/%d players, %d bots, %d/%d spectators",
+		gameModeNames[room.Mode],
+		room.Map,
+		room.Players,
+		room.MaxPlayers,
+		room.Bots,
+		room.Spectators,
+		room.MaxSpectators,
+	)
+	return name, details
+}
+
+func describeQueueStatus(status *proto.QueueStatus) string {
+	return fmt.Sprintf(
+		" Position %d of %d in the queue\n Your rating: %d, accepting opponents within %d\n Waited %ds, estimated wait %ds\n\n Press escape to leave the queue",
+		status.Position,
+		status.PlayersInQueue,
+		status.Rating,
+		status.RatingRange,
+		status.WaitedSeconds,
+		status.EstimatedWaitSeconds,
+	)
+}
+
+// roomApp lets the player pick, create or get matched into a room. The chosen
+// room is stored in info, and info.RoomID stays empty if the player quits.
+func roomApp(gameClient *client.GameClient, grpcClient proto.GameClient, playerID uuid.UUID, info *connectInfo) *tview.Application {
+	app := tview.NewApplication()
+	pages := tview.NewPages()
+
+	errors := tview.NewTextView().
+		SetText(" Pick a room with the arrow keys and enter")
+	errors.SetBackgroundColor(backgroundColor)
+
+	list := tview.NewList().
+		SetMainTextColor(textColor).
+		SetSelectedBackgroundColor(fieldColor)
+	list.SetBackgroundColor(backgroundColor)
+
+	passwordForm := tview.NewForm()
+	createForm := tview.NewForm()
+
+	queueView := tview.NewTextView()
+	queueView.SetBorder(true).
+		SetTitle("Matchmaking").
+		SetBackgroundColor(backgroundColor)
+	var cancelQueue context.CancelFunc
+	queueView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc && cancelQueue != nil {
+			cancelQueue()
+		}
+		return event
+	})
+
+	join := func(roomID string, roomPassword string) {
+		info.RoomID = roomID
+		info.RoomPassword = roomPassword
+		app.Stop()
+	}
+
+	matchmake := func(mode backend.GameMode) {
+		if info.Spectator {
+			errors.SetText(" Spectators can not join the matchmaking queue")
+			return
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelQueue = cancel
+		queueView.SetText(" Joining the queue...")
+		pages.SwitchToPage("queue")
+
+		options := client.ConnectOptions{
+			PlayerID:        playerID,
+			PlayerName:      info.PlayerName,
+			Password:        info.Password,
+			AccountPassword: info.AccountPassword,
+		}
+		go func() {
+			roomID, err := gameClient.Matchmake(ctx, grpcClient, options, mode, func(status *proto.QueueStatus) {
+				app.QueueUpdateDraw(func() {
+					queueView.SetText(describeQueueStatus(status))
+				})
+			})
+			left := ctx.Err() != nil
+			cancel()
+			app.QueueUpdateDraw(func() {
+				if left {
+					errors.SetText(" Left the queue")
+					pages.SwitchToPage("list")
+					return
+				}
+				if err != nil {
+					if isAccountRequired(err) {
+						info.AccountServers[info.Address] = true
+					}
+					errors.SetText(describeConnectError(err))
+					pages.SwitchToPage("list")
+					return
+				}
+				join(roomID, "")
+			})
+		}()
+	}
+
+	var refresh func()
+	refresh = func() {
+		resp, err := gameClient.ListRooms(grpcClient)
+		if err != nil {
+			errors.SetText(fmt.Sprintf(" Can not list rooms: %v", err))
+			return
+		}
+
+		list.Clear()
+		for _, room := range resp.Rooms {
+			room := room
+			name, details := describeRoom(room)
+			list.AddItem(name, details, 0, func() {
+				if !room.HasPassword {
+					join(room.Id, "")
+					return
+				}
+				passwordForm.Clear(true).
+					AddPasswordField("Room password", "", 32, '*', nil).
+					AddButton("Join", func() {
+						join(room.Id, passwordForm.GetFormItem(0).(*tview.InputField).GetText())
+					}).
+					AddButton("Back", func() {
+						pages.SwitchToPage("list")
+					})
+				pages.SwitchToPage("password")
+			})
+		}
+		list.AddItem("Quick match", "Find a deathmatch against players of your skill", 'm', func() {
+			matchmake(backend.ModeDeathmatch)
+		})
+		list.AddItem("Quick team match", "Find a team deathmatch against players of your skill", 't', func() {
+			matchmake(backend.ModeTeamDeathmatch)
+		})
+		list.AddItem("Create room", "Start a new room on this server", 'c', func() {
+			createForm.Clear(true).
+				AddInputField("Room name", "", 24, nil, nil).
+				AddDropDown("Mode", gameModeNames, 0, nil).
+				AddDropDown("Map", resp.Maps, 0, nil).
+				AddInputField("Bots", "0", 2, tview.InputFieldInteger, nil).
+				AddPasswordField("Room password", "", 32, '*', nil).
+				AddButton("Create", func() {
+					mode, _ := createForm.GetFormItem(1).(*tview.DropDown).GetCurrentOption()
+					_, mapName := createForm.GetFormItem(2).(*tview.DropDown).GetCurrentOption()
+					bots, _ := strconv.Atoi(createForm.GetFormItem(3).(*tview.InputField).GetText())
+					roomPassword := createForm.GetFormItem(4).(*tview.InputField).GetText()
+
+					room, err := gameClient.CreateRoom(grpcClient, &proto.CreateRoomRequest{
+						Name:           createForm.GetFormItem(0).(*tview.InputField).GetText(),
+						Mode:           proto.GameMode(mode),
+						Map:            mapName,
+						Password:       roomPassword,
+						Bots:           int32(bots),
+						ServerPassword: info.Password,
+					})
+					if err != nil {
+						errors.SetText(fmt.Sprintf(" Can not create room: %v", err))
+						return
+					}
+					join(room.Id, roomPassword)
+				}).
+				AddButton("Back", func() {
+					pages.SwitchToPage("list")
+				})
+			pages.SwitchToPage("create")
+		})
+		list.AddItem("Refresh", "Reload the room list", 'r', refresh)
+		list.AddItem("Quit", "", 'q', func() {
+			app.Stop()
+		})
+	}
+
+	for _, form := range []*tview.Form{passwordForm, createForm} {
+		form.SetLabelColor(textColor).
+			SetButtonBackgroundColor(fieldColor).
+			SetFieldBackgroundColor(fieldColor).
+			SetBackgroundColor(backgroundColor)
+	}
+
+	pages.AddPage("list", list, true, true).
+		AddPage("password", passwordForm, true, false).
+		AddPage("create", createForm, true, false).
+		AddPage("queue", queueView, true, false)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(errors, 1, 1, false).
+		AddItem(pages, 0, 1, true)
+	flex.SetBorder(true).
+		SetTitle("Rooms").
+		SetBackgroundColor(backgroundColor)
+
+	refresh()
+	app.SetRoot(flex, true)
+	return app
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 
 	termutil "github.com/andrew-d/go-termutil"
 	"github.com/google/uuid"
@@ -12,16 +13,58 @@ import (
 	"github.com/nikit34/multiplayer_rpg/pkg/backend"
 	"github.com/nikit34/multiplayer_rpg/pkg/bot"
 	"github.com/nikit34/multiplayer_rpg/pkg/frontend"
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
+	"github.com/nikit34/multiplayer_rpg/pkg/replay"
 )
 
+// record saves the changes of game to a replay file until the returned
+// function is called.
+func record(path string, game *backend.Game) (func(), error) {
+	recorder, err := replay.NewRecorder(path, game)
+	if err != nil {
+		return nil, err
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case change := <-game.ChangeChannel:
+				recorder.Record(change)
+			case <-stop:
+				if err := recorder.Close(); err != nil {
+					logging.New().Error("can not save replay", "error", err)
+				}
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}, nil
+}
+
 func main() {
 	if !termutil.Isatty(os.Stdin.Fd()) {
 		panic("this program must be run in a terminal")
 	}
 
 	numBots := flag.Int("bots", 1, "Number of bots to play against")
+	recordPath := flag.String("record", "", "Path to save a replay of the match to")
+	seed := flag.Int64("seed", 0, "Seed for the random numbers of the game, random if 0")
+	logOptions := logging.RegisterFlags(flag.CommandLine, filepath.Join(os.TempDir(), "tshooter-client-local.log"))
 	flag.Parse()
 
+	logCloser, err := logOptions.Setup()
+	if err != nil {
+		log.Fatalf("can not set up logging %v", err)
+	}
+	defer logCloser.Close()
+
 	currentPlayers := []backend.Player{{
 			Name:            "Alice",
 			Icon:            'A',
@@ -36,6 +79,9 @@ func main() {
 	}
 
 	game := backend.NewGame()
+	if *seed != 0 {
+		game.SetRNG(backend.NewRNG(*seed))
+	}
 
 	game.AddEntity(&currentPlayers[0])
 	game.AddEntity(&currentPlayers[1])
@@ -47,11 +93,21 @@ func main() {
 		bots.AddBot(fmt.Sprintf("Bob %d", i))
 	}
 
+	stopRecording := func() {}
+	if *recordPath != "" {
+		var err error
+		stopRecording, err = record(*recordPath, game)
+		if err != nil {
+			log.Fatalf("can not record replay %v", err)
+		}
+	}
+
 	game.Start()
 	view.Start()
 	bots.Start()
 
-	err := <-view.Done
+	err = <-view.Done
+	stopRecording()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -1,15 +1,29 @@
 package main
 
 import (
+	"flag"
+	"log"
 	"os/exec"
 	"runtime"
 	"fmt"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
 )
 
 
 var Command string
 
 func main() {
+	logOptions := logging.RegisterFlags(flag.CommandLine, "")
+	flag.Parse()
+
+	logCloser, err := logOptions.Setup()
+	if err != nil {
+		log.Fatalf("can not set up logging %v", err)
+	}
+	defer logCloser.Close()
+	logger := logging.New("command", Command)
+
 	var cmd *exec.Cmd
 	switch runtime.GOOS {
 	case "windows":
@@ -20,8 +34,10 @@ func main() {
 		panic("unknown runtime")
 	}
 
-	err := cmd.Start()
+	err = cmd.Start()
 	if err != nil {
+		logger.Error("can not start the client in a terminal", "error", err)
 		panic(fmt.Sprintf("%+v", err))
 	}
+	logger.Info("started the client in a terminal", "os", runtime.GOOS)
 }
\ No newline at end of file
@@ -0,0 +1,503 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	"github.com/nikit34/multiplayer_rpg/pkg/bot"
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
+	"github.com/nikit34/multiplayer_rpg/pkg/tlsconfig"
+	"github.com/nikit34/multiplayer_rpg/proto"
+)
+
+const (
+	policyBot    = "bot"
+	policyRandom = "random"
+	// thinkFrequency matches the bots of pkg/bot.
+	thinkFrequency = 200 * time.Millisecond
+	// moveAckTimeout is how long a move may go without the server echoing the
+	// new position before it counts as lost. Rejected moves, e.g. into a wall
+	// or another player, are never echoed either.
+	moveAckTimeout = 2 * time.Second
+)
+
+// stats are shared by all clients.
+type stats struct {
+	connected      int64
+	dropped        int64
+	received       int64
+	sent           int64
+	unacknowledged int64
+
+	mu        sync.Mutex
+	latencies []time.Duration
+	errors    map[string]int
+}
+
+func newStats() *stats {
+	return &stats{errors: make(map[string]int)}
+}
+
+func (s *stats) addLatency(latency time.Duration) {
+	s.mu.Lock()
+	s.latencies = append(s.latencies, latency)
+	s.mu.Unlock()
+}
+
+func (s *stats) addError(err error) {
+	s.mu.Lock()
+	s.errors[status.Convert(err).Message()]++
+	s.mu.Unlock()
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted)-1) * p)
+	return sorted[index]
+}
+
+func (s *stats) report(elapsed time.Duration) {
+	s.mu.Lock()
+	latencies := make([]time.Duration, len(s.latencies))
+	copy(latencies, s.latencies)
+	errors := make(map[string]int, len(s.errors))
+	for message, count := range s.errors {
+		errors[message] = count
+	}
+	s.mu.Unlock()
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	seconds := elapsed.Seconds()
+	fmt.Printf("after %s\n", elapsed.Round(time.Second))
+	fmt.Printf("  clients connected   %d\n", atomic.LoadInt64(&s.connected))
+	fmt.Printf("  streams dropped     %d\n", atomic.LoadInt64(&s.dropped))
+	fmt.Printf("  messages received   %d (%.0f/s)\n", atomic.LoadInt64(&s.received), float64(atomic.LoadInt64(&s.received))/seconds)
+	fmt.Printf("  messages sent       %d (%.0f/s)\n", atomic.LoadInt64(&s.sent), float64(atomic.LoadInt64(&s.sent))/seconds)
+	fmt.Printf("  moves acknowledged  %d, unacknowledged %d\n", len(latencies), atomic.LoadInt64(&s.unacknowledged))
+	if len(latencies) > 0 {
+		fmt.Printf(
+			"  move latency        p50 %s  p90 %s  p99 %s  max %s\n",
+			percentile(latencies, 0.5),
+			percentile(latencies, 0.9),
+			percentile(latencies, 0.99),
+			latencies[len(latencies)-1],
+		)
+	}
+
+	messages := make([]string, 0, len(errors))
+	for message := range errors {
+		messages = append(messages, message)
+	}
+	sort.Strings(messages)
+	for _, message := range messages {
+		fmt.Printf("  error %4dx %s\n", errors[message], message)
+	}
+}
+
+// loadClient is one simulated player. It keeps a copy of the players of its
+// room that is just good enough for the bot to pick targets.
+type loadClient struct {
+	name     string
+	policy   string
+	password string
+	game     *backend.Game
+	bots     *bot.Bots
+	rng      *backend.RNG
+	playerID uuid.UUID
+	stats    *stats
+
+	mu           sync.Mutex
+	pendingMoves []pendingMove
+}
+
+type pendingMove struct {
+	sent     time.Time
+	position backend.Coordinate
+}
+
+func newLoadClient(index int, seed int64, policy string, password string, stats *stats) *loadClient {
+	game := backend.NewGame()
+	game.IsAuthoritative = false
+	game.SetRNG(backend.NewRNG(seed + int64(index)))
+
+	loadClient := &loadClient{
+		name:     fmt.Sprintf("Load%d", index),
+		policy:   policy,
+		password: password,
+		game:     game,
+		bots:     bot.NewBots(game),
+		rng:      game.RNG(),
+		stats:    stats,
+	}
+	loadClient.playerID = loadClient.bots.AddBot(loadClient.name).ID()
+	return loadClient
+}
+
+func (c *loadClient) run(ctx context.Context, grpcClient proto.GameClient, roomID string) {
+	resp, err := grpcClient.Connect(ctx, &proto.ConnectRequest{
+		Id:       c.playerID.String(),
+		Name:     c.name,
+		Password: c.password,
+		RoomId:   roomID,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			c.stats.addError(err)
+		}
+		return
+	}
+
+	c.game.Mu.Lock()
+	c.game.SetMap(backend.ParseMap(resp.Map))
+	c.game.Mode = proto.GetBackendGameMode(resp.Mode)
+	c.game.SetRules(proto.GetBackendRules(resp.Rules))
+	for _, entity := range resp.Entities {
+		if player, ok := proto.GetBackendEntity(entity).(*backend.Player); ok {
+			c.game.AddEntity(player)
+		}
+	}
+	c.game.Mu.Unlock()
+
+	header := metadata.New(map[string]string{"authorization": resp.Token})
+	streamCtx, cancel := context.WithCancel(metadata.NewOutgoingContext(ctx, header))
+	defer cancel()
+	stream, err := grpcClient.Stream(streamCtx)
+	if err != nil {
+		c.stats.addError(err)
+		return
+	}
+	atomic.AddInt64(&c.stats.connected, 1)
+	defer atomic.AddInt64(&c.stats.connected, -1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.receive(ctx, stream)
+	}()
+
+	ticker := time.NewTicker(thinkFrequency)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			stream.CloseSend()
+			return
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		for _, req := range c.decide() {
+			if move := req.GetMove(); move != nil {
+				c.sentMove(proto.GetBackendDirection(move.Direction))
+			}
+			if err := stream.Send(req); err != nil {
+				return
+			}
+			atomic.AddInt64(&c.stats.sent, 1)
+		}
+	}
+}
+
+func (c *loadClient) decide() []*proto.Request {
+	if c.policy == policyRandom {
+		direction := backend.Direction(c.rng.Intn(4))
+		if c.rng.Intn(4) == 0 {
+			return []*proto.Request{laserRequest(uuid.New(), direction)}
+		}
+		return []*proto.Request{moveRequest(direction)}
+	}
+
+	requests := []*proto.Request{}
+	for _, action := range c.bots.Decide() {
+		switch action := action.(type) {
+		case backend.MoveAction:
+			requests = append(requests, moveRequest(action.Direction))
+		case backend.LaserAction:
+			requests = append(requests, laserRequest(action.ID, action.Direction))
+		}
+	}
+	return requests
+}
+
+func moveRequest(direction backend.Direction) *proto.Request {
+	return &proto.Request{
+		Action: &proto.Request_Move{
+			Move: &proto.Move{Direction: proto.GetProtoDirection(direction)},
+		},
+	}
+}
+
+func laserRequest(id uuid.UUID, direction backend.Direction) *proto.Request {
+	return &proto.Request{
+		Action: &proto.Request_Laser{
+			Laser: &proto.Laser{
+				Id:        id.String(),
+				Direction: proto.GetProtoDirection(direction),
+			},
+		},
+	}
+}
+
+// sentMove starts timing a move, it is acknowledged once the server sends the
+// player at the position the move leads to.
+func (c *loadClient) sentMove(direction backend.Direction) {
+	c.game.Mu.RLock()
+	player, ok := c.game.GetEntity(c.playerID).(*backend.Player)
+	var position backend.Coordinate
+	if ok {
+		position = player.Position()
+	}
+	c.game.Mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	switch direction {
+	case backend.DirectionUp:
+		position.Y--
+	case backend.DirectionDown:
+		position.Y++
+	case backend.DirectionLeft:
+		position.X--
+	case backend.DirectionRight:
+		position.X++
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expireMoves()
+	c.pendingMoves = append(c.pendingMoves, pendingMove{sent: time.Now(), position: position})
+}
+
+// expireMoves must be called with c.mu held.
+func (c *loadClient) expireMoves() {
+	for len(c.pendingMoves) > 0 && time.Since(c.pendingMoves[0].sent) > moveAckTimeout {
+		c.pendingMoves = c.pendingMoves[1:]
+		atomic.AddInt64(&c.stats.unacknowledged, 1)
+	}
+}
+
+func (c *loadClient) acknowledgeMove(position backend.Coordinate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expireMoves()
+	for i, move := range c.pendingMoves {
+		if move.position != position {
+			continue
+		}
+		c.stats.addLatency(time.Since(move.sent))
+		// Earlier moves that were not echoed have been rejected.
+		atomic.AddInt64(&c.stats.unacknowledged, int64(i))
+		c.pendingMoves = c.pendingMoves[i+1:]
+		return
+	}
+}
+
+func (c *loadClient) receive(ctx context.Context, stream proto.Game_StreamClient) {
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() == nil {
+				atomic.AddInt64(&c.stats.dropped, 1)
+				c.stats.addError(err)
+			}
+			return
+		}
+		atomic.AddInt64(&c.stats.received, 1)
+
+		if player := resp.GetUpdateEntity().GetEntity().GetPlayer(); player != nil && player.Id == c.playerID.String() {
+			c.acknowledgeMove(proto.GetBackendCoordinate(player.Position))
+		}
+		c.apply(resp)
+	}
+}
+
+// apply only keeps track of players, the bots do not look at lasers.
+func (c *loadClient) apply(resp *proto.Response) {
+	c.game.Mu.Lock()
+	defer c.game.Mu.Unlock()
+
+	updatePlayer := func(protoPlayer *proto.Player) {
+		if protoPlayer == nil {
+			return
+		}
+		if player := proto.GetBackendPlayer(protoPlayer); player != nil {
+			c.game.AddEntity(player)
+		}
+	}
+
+	switch action := resp.GetAction().(type) {
+	case *proto.Response_AddEntity:
+		updatePlayer(action.AddEntity.Entity.GetPlayer())
+	case *proto.Response_UpdateEntity:
+		updatePlayer(action.UpdateEntity.Entity.GetPlayer())
+	case *proto.Response_PlayerRespawn:
+		updatePlayer(action.PlayerRespawn.Player)
+	case *proto.Response_PlayerJoined:
+		updatePlayer(action.PlayerJoined.Player)
+	case *proto.Response_RoundStart:
+		for _, player := range action.RoundStart.Players {
+			updatePlayer(player)
+		}
+	case *proto.Response_PlayerLeft:
+		if id, err := uuid.Parse(action.PlayerLeft.Id); err == nil {
+			c.game.RemoveEntity(id)
+		}
+	}
+}
+
+// rooms hands out a room ID for every client, a new room is created whenever
+// the current one has perRoom clients.
+type rooms struct {
+	grpcClient proto.GameClient
+	password   string
+	roomID     string
+	perRoom    int
+	bots       int
+	created    int
+	joined     int
+}
+
+func (r *rooms) next(ctx context.Context) (string, error) {
+	if r.perRoom == 0 {
+		return r.roomID, nil
+	}
+	if r.created > 0 && r.joined < r.perRoom {
+		r.joined++
+		return r.roomID, nil
+	}
+
+	r.created++
+	resp, err := r.grpcClient.CreateRoom(ctx, &proto.CreateRoomRequest{
+		Name:           fmt.Sprintf("Load %d", r.created),
+		ServerPassword: r.password,
+		Bots:           int32(r.bots),
+	})
+	if err != nil {
+		return "", err
+	}
+	r.roomID = resp.Room.Id
+	r.joined = 1
+	return r.roomID, nil
+}
+
+func main() {
+	address := flag.String("address", ":8888", "Server address")
+	password := flag.String("password", "", "Server password")
+	roomID := flag.String("room", "", "ID of the room to join, the default room if empty")
+	numClients := flag.Int("clients", 100, "Number of clients to connect")
+	perRoom := flag.Int("per-room", 8, "Clients per room, rooms are created as needed, 0 puts every client in -room")
+	roomBots := flag.Int("room-bots", 0, "Server side bots in every created room")
+	ramp := flag.Duration("ramp", 10*time.Second, "Time over which the clients connect")
+	duration := flag.Duration("duration", time.Minute, "How long to run after the ramp")
+	reportEvery := flag.Duration("report", 5*time.Second, "How often to print intermediate results")
+	policy := flag.String("policy", policyBot, "How clients play, bot or random")
+	seed := flag.Int64("seed", 0, "Seed for the clients, random if 0")
+	tlsOptions := tlsconfig.ClientOptions{}
+	flag.StringVar(&tlsOptions.CAFile, "ca", "", "CA certificate used to verify the server, enables TLS")
+	flag.StringVar(&tlsOptions.Pin, "pin", "", "SHA-256 fingerprint the server certificate key must match, enables TLS")
+	flag.StringVar(&tlsOptions.CertFile, "cert", "", "Client certificate for trusted bot hosts")
+	flag.StringVar(&tlsOptions.KeyFile, "key", "", "Client certificate private key")
+	logOptions := logging.RegisterFlags(flag.CommandLine, "")
+	flag.Parse()
+
+	logCloser, err := logOptions.Setup()
+	if err != nil {
+		log.Fatalf("can not set up logging %v", err)
+	}
+	defer logCloser.Close()
+
+	if *policy != policyBot && *policy != policyRandom {
+		log.Fatalf("unknown policy %q", *policy)
+	}
+	if *roomID != "" {
+		*perRoom = 0
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	dialOption, err := tlsconfig.DialOption(tlsOptions)
+	if err != nil {
+		log.Fatalf("can not load TLS credentials %v", err)
+	}
+	conn, err := grpc.Dial(*address, dialOption)
+	if err != nil {
+		log.Fatalf("can not connect with server %v", err)
+	}
+	defer conn.Close()
+	grpcClient := proto.NewGameClient(conn)
+
+	logging.New().Info("connecting", "clients", *numClients, "policy", *policy, "ramp", *ramp, "seed", *seed)
+
+	stats := newStats()
+	ctx, cancel := context.WithCancel(context.Background())
+	started := time.Now()
+
+	go func() {
+		ticker := time.NewTicker(*reportEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				stats.report(time.Since(started))
+			}
+		}
+	}()
+
+	roomList := &rooms{
+		grpcClient: grpcClient,
+		password:   *password,
+		roomID:     *roomID,
+		perRoom:    *perRoom,
+		bots:       *roomBots,
+	}
+	wg := sync.WaitGroup{}
+	interval := time.Duration(0)
+	if *numClients > 1 {
+		interval = *ramp / time.Duration(*numClients-1)
+	}
+	for i := 0; i < *numClients; i++ {
+		if i > 0 {
+			time.Sleep(interval)
+		}
+
+		clientRoomID, err := roomList.next(ctx)
+		if err != nil {
+			stats.addError(err)
+			continue
+		}
+
+		loadClient := newLoadClient(i, *seed, *policy, *password, stats)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loadClient.run(ctx, grpcClient, clientRoomID)
+		}()
+	}
+
+	time.Sleep(*duration)
+	elapsed := time.Since(started)
+	cancel()
+	wg.Wait()
+
+	stats.report(elapsed)
+}
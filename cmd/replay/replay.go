@@ -0,0 +1,539 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	termutil "github.com/andrew-d/go-termutil"
+	tcell "github.com/gdamore/tcell/v2"
+	"github.com/google/uuid"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	"github.com/nikit34/multiplayer_rpg/pkg/frontend"
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
+	"github.com/nikit34/multiplayer_rpg/pkg/replay"
+	"github.com/nikit34/multiplayer_rpg/proto"
+)
+
+const (
+	tickFrequency    = 17 * time.Millisecond
+	statusFrequency  = 250 * time.Millisecond
+	keyframeInterval = 5 * time.Second
+	seekStep         = 5 * time.Second
+	helpText         = "space pause - + - speed - , . seek - 0 restart - tab/n b follow - f free camera - ctrl+q quit"
+)
+
+var speeds = []float64{0.25, 0.5, 1, 2, 4, 8}
+
+// keyframe is the state of the match at offset, with frames[frame:] left to
+// play. Seeking restores the last keyframe before the target and plays the
+// few frames in between. The map, mode and rules are part of it as a round
+// start can change them.
+type keyframe struct {
+	offset       time.Duration
+	frame        int
+	gameMap      [][]rune
+	mode         backend.GameMode
+	rules        backend.Rules
+	entities     []*proto.Entity
+	score        map[uuid.UUID]int
+	roundWinner  uuid.UUID
+	newRoundAt   time.Time
+	waitForRound bool
+}
+
+type player struct {
+	header    *proto.ReplayHeader
+	frames    []*proto.ReplayFrame
+	keyframes []keyframe
+	game      *backend.Game
+	view      *frontend.View
+	started   time.Time
+	duration  time.Duration
+	// The fields below are guarded by game.Mu.
+	position time.Duration
+	next     int
+	speed    int
+	paused   bool
+	// laserStarts and newRoundAt keep the recorded times, the game gets them
+	// moved to the replay clock on every tick.
+	laserStarts map[uuid.UUID]time.Time
+	newRoundAt  time.Time
+}
+
+func load(path string) (*proto.ReplayHeader, []*proto.ReplayFrame, error) {
+	reader, err := replay.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer reader.Close()
+
+	frames := make([]*proto.ReplayFrame, 0)
+	for {
+		frame, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		frames = append(frames, frame)
+	}
+	return reader.Header, frames, nil
+}
+
+func frameOffset(frame *proto.ReplayFrame) time.Duration {
+	return time.Duration(frame.OffsetMs) * time.Millisecond
+}
+
+func setEntities(game *backend.Game, entities []*proto.Entity) error {
+	game.Entities = make(map[uuid.UUID]backend.Identifier)
+	for _, protoEntity := range entities {
+		entity := proto.GetBackendEntity(protoEntity)
+		if entity == nil {
+			return fmt.Errorf("can not get backend entity from %+v", protoEntity)
+		}
+		game.AddEntity(entity)
+	}
+	return nil
+}
+
+func newPlayer(header *proto.ReplayHeader, frames []*proto.ReplayFrame, game *backend.Game, view *frontend.View) (*player, error) {
+	p := &player{
+		header:      header,
+		frames:      frames,
+		game:        game,
+		view:        view,
+		started:     header.Started.AsTime(),
+		duration:    time.Duration(header.DurationMs) * time.Millisecond,
+		speed:       2,
+		laserStarts: make(map[uuid.UUID]time.Time),
+	}
+	if err := p.buildKeyframes(); err != nil {
+		return nil, err
+	}
+	if err := p.seek(0); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// buildKeyframes plays the whole replay once on a scratch game.
+func (p *player) buildKeyframes() error {
+	scratch := backend.NewGame()
+	scratch.IsAuthoritative = false
+	scratch.SetMap(backend.ParseMap(p.header.Map))
+	scratch.Mode = proto.GetBackendGameMode(p.header.Mode)
+	scratch.SetRules(proto.GetBackendRules(p.header.Rules))
+	if err := setEntities(scratch, p.header.Entities); err != nil {
+		return err
+	}
+
+	next := 0
+	for offset := time.Duration(0); ; offset += keyframeInterval {
+		for next < len(p.frames) && frameOffset(p.frames[next]) <= offset {
+			if err := applyResponse(scratch, p.frames[next].Response); err != nil {
+				return err
+			}
+			next++
+		}
+
+		entities := make([]*proto.Entity, 0, len(scratch.Entities))
+		for _, entity := range scratch.Entities {
+			entities = append(entities, proto.GetProtoEntity(entity))
+		}
+		score := make(map[uuid.UUID]int, len(scratch.Score))
+		for id, kills := range scratch.Score {
+			score[id] = kills
+		}
+		p.keyframes = append(p.keyframes, keyframe{
+			offset:       offset,
+			frame:        next,
+			gameMap:      scratch.GetMap(),
+			mode:         scratch.Mode,
+			rules:        scratch.Rules(),
+			entities:     entities,
+			score:        score,
+			roundWinner:  scratch.RoundWinner,
+			newRoundAt:   scratch.NewRoundAt,
+			waitForRound: scratch.WaitForRound,
+		})
+
+		if offset >= p.duration {
+			return nil
+		}
+	}
+}
+
+// applyResponse changes game the way a client would on receiving resp.
+func applyResponse(game *backend.Game, resp *proto.Response) error {
+	switch resp.Action.(type) {
+	case *proto.Response_AddEntity:
+		entity := proto.GetBackendEntity(resp.GetAddEntity().Entity)
+		if entity == nil {
+			return errors.New("can not get backend entity")
+		}
+		game.AddEntity(entity)
+	case *proto.Response_UpdateEntity:
+		entity := proto.GetBackendEntity(resp.GetUpdateEntity().Entity)
+		if entity == nil {
+			return errors.New("can not get backend entity")
+		}
+		game.UpdateEntity(entity)
+	case *proto.Response_RemoveEntity:
+		id, err := uuid.Parse(resp.GetRemoveEntity().Id)
+		if err != nil {
+			return err
+		}
+		game.RemoveEntity(id)
+	case *proto.Response_PlayerRespawn:
+		respawn := resp.GetPlayerRespawn()
+		killedByID, err := uuid.Parse(respawn.KilledById)
+		if err != nil {
+			return err
+		}
+		player := proto.GetBackendPlayer(respawn.Player)
+		if player == nil {
+			return errors.New("can not get backend player")
+		}
+		game.AddScore(killedByID)
+		game.UpdateEntity(player)
+	case *proto.Response_PlayerJoined:
+		player := proto.GetBackendPlayer(resp.GetPlayerJoined().Player)
+		if player == nil {
+			return errors.New("can not get backend player")
+		}
+		game.AddEntity(player)
+	case *proto.Response_PlayerLeft:
+		id, err := uuid.Parse(resp.GetPlayerLeft().Id)
+		if err != nil {
+			return err
+		}
+		game.RemoveEntity(id)
+	case *proto.Response_RoundOver:
+		roundOver := resp.GetRoundOver()
+		roundWinner, err := uuid.Parse(roundOver.RoundWinnerId)
+		if err != nil {
+			return err
+		}
+		game.RoundWinner = roundWinner
+		game.NewRoundAt = roundOver.NewRoundAt.AsTime()
+		game.WaitForRound = true
+		game.Score = make(map[uuid.UUID]int)
+	case *proto.Response_RoundStart:
+		game.WaitForRound = false
+		if roundStart := resp.GetRoundStart(); len(roundStart.Map) > 0 {
+			game.SetMap(backend.ParseMap(roundStart.Map))
+			game.Mode = proto.GetBackendGameMode(roundStart.Mode)
+		}
+		if roundStart := resp.GetRoundStart(); roundStart.Rules != nil {
+			game.SetRules(proto.GetBackendRules(roundStart.Rules))
+		}
+		for _, protoPlayer := range resp.GetRoundStart().Players {
+			player := proto.GetBackendPlayer(protoPlayer)
+			if player == nil {
+				return errors.New("can not get backend player")
+			}
+			game.AddEntity(player)
+		}
+	}
+	return nil
+}
+
+// play applies a frame to the shown game, with the notifications a player
+// would have seen when live is set.
+func (p *player) play(resp *proto.Response, live bool) error {
+	var victim *backend.Player
+	if respawn := resp.GetPlayerRespawn(); respawn != nil {
+		victim = proto.GetBackendPlayer(respawn.Player)
+	}
+
+	if err := applyResponse(p.game, resp); err != nil {
+		return err
+	}
+
+	switch resp.Action.(type) {
+	case *proto.Response_AddEntity:
+		if laser := resp.GetAddEntity().Entity.GetLaser(); laser != nil {
+			id, _ := uuid.Parse(laser.Id)
+			p.laserStarts[id] = laser.StartTime.AsTime()
+		}
+	case *proto.Response_RemoveEntity:
+		id, _ := uuid.Parse(resp.GetRemoveEntity().Id)
+		delete(p.laserStarts, id)
+	case *proto.Response_RoundOver:
+		p.newRoundAt = p.game.NewRoundAt
+	}
+
+	if !live {
+		return nil
+	}
+	switch resp.Action.(type) {
+	case *proto.Response_PlayerRespawn:
+		killedByID, _ := uuid.Parse(resp.GetPlayerRespawn().KilledById)
+		killer, ok := p.game.GetEntity(killedByID).(*backend.Player)
+		if ok && victim != nil {
+			p.view.AddKill(killer, victim)
+		}
+	case *proto.Response_PlayerJoined:
+		p.view.AddNotification(fmt.Sprintf("%s joined the game", resp.GetPlayerJoined().Player.Name))
+	case *proto.Response_PlayerLeft:
+		p.view.AddNotification(fmt.Sprintf("%s left the game", resp.GetPlayerLeft().Name))
+	case *proto.Response_RoundOver:
+		winner, ok := p.game.GetEntity(p.game.RoundWinner).(*backend.Player)
+		if ok {
+			p.view.AddNotification(fmt.Sprintf("%s won the round", winner.Name))
+		}
+		p.view.ResetStreaks()
+	case *proto.Response_RoundStart:
+		p.view.AddNotification("A new round has started")
+	}
+	return nil
+}
+
+// seek must be called with game.Mu held, except from newPlayer.
+func (p *player) seek(target time.Duration) error {
+	if target < 0 {
+		target = 0
+	}
+	if target > p.duration {
+		target = p.duration
+	}
+
+	index := int(target / keyframeInterval)
+	if index >= len(p.keyframes) {
+		index = len(p.keyframes) - 1
+	}
+	kf := p.keyframes[index]
+
+	p.game.SetMap(kf.gameMap)
+	p.game.Mode = kf.mode
+	p.game.SetRules(kf.rules)
+	if err := setEntities(p.game, kf.entities); err != nil {
+		return err
+	}
+	p.game.Score = make(map[uuid.UUID]int, len(kf.score))
+	for id, kills := range kf.score {
+		p.game.Score[id] = kills
+	}
+	p.game.RoundWinner = kf.roundWinner
+	p.game.WaitForRound = kf.waitForRound
+	p.newRoundAt = kf.newRoundAt
+	p.laserStarts = make(map[uuid.UUID]time.Time)
+	for _, entity := range p.game.Entities {
+		if laser, ok := entity.(*backend.Laser); ok {
+			p.laserStarts[laser.ID()] = laser.StartTime
+		}
+	}
+	p.view.ResetStreaks()
+
+	p.next = kf.frame
+	p.position = target
+	return p.advance(false)
+}
+
+// advance plays every frame up to the current position.
+func (p *player) advance(live bool) error {
+	for p.next < len(p.frames) && frameOffset(p.frames[p.next]) <= p.position {
+		if err := p.play(p.frames[p.next].Response, live); err != nil {
+			return err
+		}
+		p.next++
+	}
+	p.retime(time.Now())
+	return nil
+}
+
+// retime moves the recorded times of lasers and the next round to the replay
+// clock, lasers are drawn where they are after time.Since(StartTime).
+func (p *player) retime(now time.Time) {
+	recorded := p.started.Add(p.position)
+	for id, start := range p.laserStarts {
+		laser, ok := p.game.GetEntity(id).(*backend.Laser)
+		if ok {
+			laser.StartTime = now.Add(start.Sub(recorded))
+		}
+	}
+	p.game.NewRoundAt = now.Add(p.newRoundAt.Sub(recorded))
+}
+
+func (p *player) run() {
+	ticker := time.NewTicker(tickFrequency)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for now := range ticker.C {
+		p.game.Mu.Lock()
+		if !p.paused {
+			p.position += time.Duration(float64(now.Sub(last)) * speeds[p.speed])
+			if p.position >= p.duration {
+				p.position = p.duration
+				p.paused = true
+			}
+		}
+		last = now
+		err := p.advance(true)
+		p.game.Mu.Unlock()
+
+		if err != nil {
+			p.view.App.Stop()
+			logging.New().Error("can not play replay", "error", err)
+			return
+		}
+	}
+}
+
+func (p *player) status() string {
+	p.game.Mu.RLock()
+	defer p.game.Mu.RUnlock()
+
+	state := "playing"
+	if p.paused {
+		state = "paused"
+	}
+	return fmt.Sprintf(
+		"%s / %s %s at %gx - %s",
+		formatDuration(p.position),
+		formatDuration(p.duration),
+		state,
+		speeds[p.speed],
+		helpText,
+	)
+}
+
+func formatDuration(duration time.Duration) string {
+	seconds := int(duration.Seconds())
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+func (p *player) handleKey(e *tcell.EventKey) bool {
+	p.game.Mu.Lock()
+	defer p.game.Mu.Unlock()
+
+	var err error
+	switch e.Rune() {
+	case ' ':
+		if p.paused && p.position >= p.duration {
+			err = p.seek(0)
+		}
+		p.paused = !p.paused
+	case '+', '=':
+		if p.speed < len(speeds)-1 {
+			p.speed++
+		}
+	case '-':
+		if p.speed > 0 {
+			p.speed--
+		}
+	case ',':
+		err = p.seek(p.position - seekStep)
+	case '.':
+		err = p.seek(p.position + seekStep)
+	case '0':
+		err = p.seek(0)
+	default:
+		return false
+	}
+
+	if err != nil {
+		logging.New().Warn("can not seek", "error", err)
+	}
+	return true
+}
+
+func printInfo(header *proto.ReplayHeader, frames []*proto.ReplayFrame) {
+	names := make([]string, 0, len(header.Players))
+	for _, player := range header.Players {
+		names = append(names, player.Name)
+	}
+	fmt.Printf("version:  %d\n", header.Version)
+	fmt.Printf("recorded: %s\n", header.Started.AsTime().Local().Format(time.RFC1123))
+	fmt.Printf("duration: %s\n", formatDuration(time.Duration(header.DurationMs)*time.Millisecond))
+	fmt.Printf("mode:     %s\n", header.Mode)
+	fmt.Printf("map:      %s\n", header.MapHash)
+	fmt.Printf("players:  %s\n", strings.Join(names, ", "))
+	fmt.Printf("frames:   %d\n", len(frames))
+}
+
+func main() {
+	info := flag.Bool("info", false, "Print the replay header and exit")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] file.replay\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	logOptions := logging.RegisterFlags(flag.CommandLine, filepath.Join(os.TempDir(), "tshooter-replay.log"))
+	flag.Parse()
+
+	logCloser, err := logOptions.Setup()
+	if err != nil {
+		log.Fatalf("can not set up logging %v", err)
+	}
+	defer logCloser.Close()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	header, frames, err := load(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("can not read replay %v", err)
+	}
+
+	if *info {
+		printInfo(header, frames)
+		return
+	}
+	if !termutil.Isatty(os.Stdin.Fd()) {
+		panic("this program must be run in a terminal")
+	}
+
+	game := backend.NewGame()
+	game.IsAuthoritative = false
+	game.SetMap(backend.ParseMap(header.Map))
+	game.Mode = proto.GetBackendGameMode(header.Mode)
+	game.SetRules(proto.GetBackendRules(header.Rules))
+
+	view := frontend.NewView(game)
+	view.SetSpectator(true)
+
+	p, err := newPlayer(header, frames, game, view)
+	if err != nil {
+		log.Fatalf("can not read replay %v", err)
+	}
+
+	capture := view.App.GetInputCapture()
+	view.App.SetInputCapture(func(e *tcell.EventKey) *tcell.EventKey {
+		// There is nobody to chat with in a replay.
+		if e.Key() == tcell.KeyEnter {
+			return nil
+		}
+		if p.handleKey(e) {
+			return nil
+		}
+		return capture(e)
+	})
+
+	go p.run()
+	go func() {
+		for {
+			status := p.status()
+			view.App.QueueUpdateDraw(func() {
+				view.SetHelpText(status)
+			})
+			time.Sleep(statusFrequency)
+		}
+	}()
+	view.Start()
+
+	err = <-view.Done
+	if err != nil {
+		log.Fatal(err)
+	}
+}
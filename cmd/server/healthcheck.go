@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/tlsconfig"
+)
+
+// healthcheck asks the server at address whether service is serving, for
+// container orchestrators. It returns the exit status, 0 if it is serving.
+func healthcheck(address string, service string, timeout time.Duration, tlsOptions tlsconfig.ClientOptions) int {
+	dialOption, err := tlsconfig.DialOption(tlsOptions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unhealthy: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, address, dialOption, grpc.WithBlock())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unhealthy: can not connect to %s: %v\n", address, err)
+		return 1
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unhealthy: %v\n", err)
+		return 1
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		fmt.Fprintf(os.Stderr, "unhealthy: %s\n", resp.Status)
+		return 1
+	}
+	fmt.Println("healthy")
+	return 0
+}
@@ -5,44 +5,345 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
+	"github.com/nikit34/multiplayer_rpg/pkg/auth"
 	"github.com/nikit34/multiplayer_rpg/pkg/backend"
-	"github.com/nikit34/multiplayer_rpg/pkg/bot"
+	"github.com/nikit34/multiplayer_rpg/pkg/config"
+	"github.com/nikit34/multiplayer_rpg/pkg/discovery"
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
+	"github.com/nikit34/multiplayer_rpg/pkg/metrics"
+	"github.com/nikit34/multiplayer_rpg/pkg/rating"
 	"github.com/nikit34/multiplayer_rpg/pkg/server"
+	"github.com/nikit34/multiplayer_rpg/pkg/tlsconfig"
 	proto "github.com/nikit34/multiplayer_rpg/proto"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
+// reflectionMethod is the call of the gRPC reflection service.
+const reflectionMethod = "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"
 
 func main() {
-	port := flag.Int("port", 8888, "Port to listen on")
-	password := flag.String("password", "", "Server password")
-	numBots := flag.Int("bots", 0, "Number of bots to add to server")
+	config.RegisterFlags(flag.CommandLine, config.Default())
+	configPath := flag.String("config", "", "Path to a TOML config file, flags given on the command line override it, reloaded on SIGHUP")
+	logOptions := logging.RegisterFlags(flag.CommandLine, "")
+	healthcheckAddress := flag.String("healthcheck", "", "Check the health of the server at this address, e.g. localhost:8888, and exit with 0 if it is serving or 1 otherwise")
+	healthcheckService := flag.String("healthcheck-service", "", "Service -healthcheck checks, empty for the whole server, proto.Game or room/<id>")
+	healthcheckTimeout := flag.Duration("healthcheck-timeout", 5*time.Second, "How long -healthcheck waits for an answer")
+	healthcheckTLS := tlsconfig.ClientOptions{}
+	flag.StringVar(&healthcheckTLS.CAFile, "healthcheck-ca", "", "CA certificate -healthcheck verifies the server with, enables TLS")
+	flag.StringVar(&healthcheckTLS.Pin, "healthcheck-pin", "", "SHA-256 fingerprint the server certificate key must match for -healthcheck, enables TLS")
 	flag.Parse()
 
-	log.Printf("listening on port %d", *port)
+	if *healthcheckAddress != "" {
+		os.Exit(healthcheck(*healthcheckAddress, *healthcheckService, *healthcheckTimeout, healthcheckTLS))
+	}
+
+	cfg, err := config.Load(*configPath, flag.CommandLine)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
+	logCloser, err := logOptions.Setup()
+	if err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+	defer logCloser.Close()
+	logger := logging.New()
+
+	logger.Info("listening", "port", cfg.Network.Port)
+
+	if cfg.Network.MetricsAddr != "" {
+		metricsListener, err := net.Listen("tcp", cfg.Network.MetricsAddr)
+		if err != nil {
+			log.Fatalf("failed to listen for metrics: %v", err)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Default)
+		go func() {
+			logger.Info("serving metrics", "address", cfg.Network.MetricsAddr, "path", "/metrics")
+			if err := http.Serve(metricsListener, mux); err != nil {
+				logger.Error("metrics server stopped", "error", err)
+			}
+		}()
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Network.Port))
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	game := backend.NewGame()
+	var accounts *auth.Accounts
+	if cfg.Auth.Accounts != "" {
+		accounts, err = auth.NewAccounts(cfg.Auth.Accounts)
+		if err != nil {
+			log.Fatalf("failed to load accounts: %v", err)
+		}
+	}
+
+	secret := []byte(cfg.Auth.TokenSecret)
+	if len(secret) == 0 {
+		logger.Warn("no token secret provided, sessions will not survive a restart")
+		secret = auth.NewSecret()
+	}
+	serverID := cfg.Auth.ServerID
+	if serverID == "" {
+		serverID, err = os.Hostname()
+		if err != nil {
+			log.Fatalf("failed to get hostname: %v", err)
+		}
+	}
+	if cfg.Auth.TokenSecret != "" && cfg.Auth.Revocations == "" {
+		logger.Warn("no revocations file provided, revoked sessions will be accepted again after a restart")
+	}
+	revocations, err := auth.NewRevocations(cfg.Auth.Revocations)
+	if err != nil {
+		log.Fatalf("failed to load revocations: %v", err)
+	}
+	tokens := auth.NewTokens(secret, serverID, cfg.Auth.TokenLifetime, revocations)
+
+	mode, err := backend.ParseGameMode(cfg.Maps.Mode)
+	if err != nil {
+		log.Fatalf("invalid game mode: %v", err)
+	}
+
+	// Admin calls carry the admin token instead of a player token, probes
+	// and debugging tools none.
+	publicMethods := append(append([]string{}, server.PublicMethods...), server.AdminMethods...)
+	publicMethods = append(publicMethods, server.HealthMethods...)
+	if cfg.Network.Reflection {
+		publicMethods = append(publicMethods, reflectionMethod)
+	}
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			server.UnaryMetricsInterceptor(),
+			tokens.UnaryServerInterceptor(publicMethods...),
+			server.UnaryAdminInterceptor(cfg.Auth.AdminToken),
+		),
+		grpc.ChainStreamInterceptor(
+			server.StreamMetricsInterceptor(),
+			tokens.StreamServerInterceptor(publicMethods...),
+		),
+	}
+	if cfg.Network.TLSCert != "" {
+		creds, err := tlsconfig.ServerCredentials(cfg.Network.TLSCert, cfg.Network.TLSKey, cfg.Network.TLSClientCA)
+		if err != nil {
+			log.Fatalf("failed to load TLS credentials: %v", err)
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+
+	s := grpc.NewServer(options...)
+	gameServer := server.NewGameServer(cfg.Auth.Password, accounts, tokens, cfg.Game.MaxRooms)
+	seed := cfg.Game.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	logger.Info("using random seed", "seed", seed)
+	gameServer.SetRNG(backend.NewRNG(seed))
+	if cfg.Game.ReplayDir != "" {
+		if err := os.MkdirAll(cfg.Game.ReplayDir, 0755); err != nil {
+			log.Fatalf("failed to create replay directory: %v", err)
+		}
+		gameServer.SetReplayDir(cfg.Game.ReplayDir)
+	}
+	gameServer.SetRules(cfg.Game.Rules())
+	gameServer.SetMaxPlayers(cfg.Game.MaxPlayers)
+	gameServer.SetClientTimeout(cfg.Game.ClientTimeout)
+	_, err = gameServer.AddRoom(server.RoomSettings{
+		Name:       "Main",
+		Mode:       mode,
+		Map:        cfg.Maps.Map,
+		Bots:       cfg.Bots.Count,
+		Persistent: true,
+		Rotation:   cfg.Maps.Rotation,
+	})
+	if err != nil {
+		log.Fatalf("failed to create the default room: %v", err)
+	}
+	if cfg.Game.ChatFilter != "" {
+		chatFilter, err := server.LoadChatFilter(cfg.Game.ChatFilter)
+		if err != nil {
+			log.Fatalf("failed to load chat filter: %v", err)
+		}
+		gameServer.SetChatFilter(chatFilter)
+	}
+	if cfg.Game.Ratings != "" {
+		if accounts == nil {
+			logger.Warn("ratings are only kept on servers with accounts, set auth.accounts to rate players")
+		}
+		ratings, err := rating.NewRatings(cfg.Game.Ratings)
+		if err != nil {
+			log.Fatalf("failed to load ratings: %v", err)
+		}
+		gameServer.SetRatings(ratings)
+	}
+	if cfg.Auth.Bans != "" {
+		bans, err := server.NewBans(cfg.Auth.Bans)
+		if err != nil {
+			log.Fatalf("failed to load bans: %v", err)
+		}
+		gameServer.SetBans(bans)
+	}
+	gameServer.SetConnectLimits(cfg.Network.MaxConnectionsPerIP, cfg.Network.MaxConnectsPerMinute)
+	proto.RegisterGameServer(s, gameServer)
+	if cfg.Auth.AdminToken != "" {
+		proto.RegisterAdminServer(s, server.NewAdminServer(gameServer))
+	}
+	healthServer := health.NewServer()
+	gameServer.SetHealth(healthServer)
+	healthpb.RegisterHealthServer(s, healthServer)
+	if cfg.Network.Reflection {
+		reflection.Register(s)
+		logger.Info("serving gRPC reflection")
+	}
 
-	bots := bot.NewBots(game)
-	for i := 0; i < *numBots; i++ {
-		bots.AddBot(fmt.Sprintf("Bob %d", i))
+	// The announcer must not read cfg, it is replaced on reload.
+	port := cfg.Network.Port
+	var serverName atomic.Value
+	serverName.Store(announcedName(cfg.Discovery.Name))
+	var announcer *discovery.Announcer
+	if cfg.Discovery.Enabled {
+		announcer, err = discovery.NewAnnouncer(cfg.Discovery.Address, cfg.Discovery.Interval, func() discovery.Announcement {
+			return announcement(gameServer, serverName.Load().(string), port)
+		})
+		if err != nil {
+			log.Fatalf("failed to announce the server: %v", err)
+		}
+		// Without a network the announcements fail every time, log only
+		// when the reason changes.
+		lastError := ""
+		announcer.Start(func(err error) {
+			if err.Error() != lastError {
+				lastError = err.Error()
+				logger.Warn("failed to announce the server", "error", err)
+			}
+		})
+		logger.Info("announcing the server on the local network", "address", cfg.Discovery.Address)
 	}
 
-	game.Start()
-	bots.Start()
+	// SIGHUP reloads the config file, cfg is only used by this goroutine
+	// from here on.
+	shutdownGrace := int64(cfg.Network.ShutdownGrace)
+	reloads := make(chan os.Signal, 1)
+	signal.Notify(reloads, syscall.SIGHUP)
+	go func() {
+		for range reloads {
+			next, err := config.Load(*configPath, flag.CommandLine)
+			if err != nil {
+				logger.Error("failed to reload the config, keeping the old one", "error", err)
+				continue
+			}
+			applyConfig(gameServer, cfg, next, logger)
+			serverName.Store(announcedName(next.Discovery.Name))
+			atomic.StoreInt64(&shutdownGrace, int64(next.Network.ShutdownGrace))
+			cfg = next
+		}
+	}()
+
+	// The first signal drains the server, a second one stops it right away.
+	// The status is decided once, a forced stop decides it before stopping so
+	// the interrupted drain can not report success.
+	exitStatus := make(chan int, 1)
+	var exitOnce sync.Once
+	exit := func(status int) {
+		exitOnce.Do(func() {
+			exitStatus <- status
+		})
+	}
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		grace := time.Duration(atomic.LoadInt64(&shutdownGrace))
+		logger.Info("received signal, shutting down", "signal", sig, "grace", grace)
+		// A draining server takes no new players.
+		if announcer != nil {
+			announcer.Stop()
+		}
+		go func() {
+			sig := <-signals
+			logger.Warn("received second signal, stopping now", "signal", sig)
+			exit(1)
+			s.Stop()
+		}()
 
-	s := grpc.NewServer()
-	server := server.NewGameServer(game, *password)
-	proto.RegisterGameServer(s, server)
+		gameServer.Shutdown(grace)
+		s.GracefulStop()
+		exit(0)
+	}()
 
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
+	status := <-exitStatus
+	logger.Info("server stopped")
+	logCloser.Close()
+	os.Exit(status)
+}
+
+// announcedName is the configured server name or the hostname.
+func announcedName(name string) string {
+	if name != "" {
+		return name
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "tshooter"
+	}
+	return hostname
+}
+
+// announcement describes the default room to the clients on the local
+// network.
+func announcement(gameServer *server.GameServer, name string, port int) discovery.Announcement {
+	announcement := discovery.Announcement{
+		Name:        name,
+		HasPassword: gameServer.HasPassword(),
+		HasAccounts: gameServer.HasAccounts(),
+		Port:        port,
+	}
+	if info, ok := gameServer.DefaultRoomInfo(); ok {
+		announcement.Map = info.Map
+		announcement.Mode = proto.GetBackendGameMode(info.Mode).String()
+		announcement.Players = int(info.Players)
+		announcement.MaxPlayers = int(info.MaxPlayers)
+	}
+	return announcement
+}
+
+// applyConfig applies the settings that can change while the server runs and
+// logs the ones that need a restart. Rules take effect when the next round of
+// a room starts.
+func applyConfig(gameServer *server.GameServer, old *config.Config, next *config.Config, logger *logging.Logger) {
+	gameServer.SetPassword(next.Auth.Password)
+	gameServer.SetConnectLimits(next.Network.MaxConnectionsPerIP, next.Network.MaxConnectsPerMinute)
+	gameServer.SetMaxPlayers(next.Game.MaxPlayers)
+	gameServer.SetClientTimeout(next.Game.ClientTimeout)
+	if next.Game.Rules() != old.Game.Rules() {
+		gameServer.SetRules(next.Game.Rules())
+		logger.Info("the rules change when the next round starts")
+	}
+	if err := gameServer.SetDefaultRoomBots(next.Bots.Count); err != nil {
+		logger.Warn("unable to change the bots of the default room", "error", err)
+	}
+	if err := gameServer.SetRotation(next.Maps.Rotation); err != nil {
+		logger.Warn("unable to change the map rotation", "error", err)
+	}
+
+	if keys := config.RestartRequired(old, next); len(keys) > 0 {
+		logger.Warn("some settings only change after a restart", "settings", strings.Join(keys, ", "))
+	}
+	logger.Info("reloaded the config")
 }
@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	minPasswordLength = 6
+	maxPasswordLength = 72
+)
+
+var (
+	ErrAccountExists      = errors.New("account already exists")
+	ErrInvalidCredentials = errors.New("invalid account name or password")
+	ErrPasswordLength     = errors.New("password must be between 6 and 72 characters")
+	// ErrAccountRequired turns guests away from servers with accounts.
+	ErrAccountRequired = errors.New("this server admits accounts only, log in or register")
+)
+
+type Account struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	PasswordHash []byte    `json:"passwordHash"`
+	Created      time.Time `json:"created"`
+}
+
+type Accounts struct {
+	path   string
+	mu     sync.RWMutex
+	byName map[string]*Account
+}
+
+func accountKey(name string) string {
+	return strings.ToLower(name)
+}
+
+func NewAccounts(path string) (*Accounts, error) {
+	accounts := &Accounts{
+		path:   path,
+		byName: make(map[string]*Account),
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return accounts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*Account, 0)
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	for _, account := range list {
+		accounts.byName[accountKey(account.Name)] = account
+	}
+	return accounts, nil
+}
+
+func (accounts *Accounts) save() error {
+	list := make([]*Account, 0, len(accounts.byName))
+	for _, account := range accounts.byName {
+		list = append(list, account)
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(accounts.path), ".accounts-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), accounts.path)
+}
+
+func (accounts *Accounts) Register(name string, password string) (*Account, error) {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return nil, ErrPasswordLength
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts.mu.Lock()
+	defer accounts.mu.Unlock()
+
+	key := accountKey(name)
+	if _, ok := accounts.byName[key]; ok {
+		return nil, ErrAccountExists
+	}
+
+	account := &Account{
+		ID:           uuid.New(),
+		Name:         name,
+		PasswordHash: hash,
+		Created:      time.Now(),
+	}
+	accounts.byName[key] = account
+
+	if err := accounts.save(); err != nil {
+		delete(accounts.byName, key)
+		return nil, err
+	}
+	return account, nil
+}
+
+func (accounts *Accounts) Login(name string, password string) (*Account, error) {
+	accounts.mu.RLock()
+	account, ok := accounts.byName[accountKey(name)]
+	accounts.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword(account.PasswordHash, []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return account, nil
+}
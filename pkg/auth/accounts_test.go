@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAccounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.json")
+	accounts, err := NewAccounts(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alice, err := accounts.Register("Alice", "alice password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(alice.PasswordHash, []byte("alice password")) {
+		t.Fatal("the password is stored in plain text")
+	}
+	if err := bcrypt.CompareHashAndPassword(alice.PasswordHash, []byte("alice password")); err != nil {
+		t.Fatalf("the hash does not match the password: %v", err)
+	}
+
+	if _, err := accounts.Register("alice", "other password"); !errors.Is(err, ErrAccountExists) {
+		t.Errorf("got error %v registering a taken name, want %v", err, ErrAccountExists)
+	}
+	for _, password := range []string{"short", strings.Repeat("x", maxPasswordLength+1)} {
+		if _, err := accounts.Register("Bob", password); !errors.Is(err, ErrPasswordLength) {
+			t.Errorf("got error %v for a password of %d characters, want %v", err, len(password), ErrPasswordLength)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		err      error
+	}{
+		{"Alice", "alice password", nil},
+		{"ALICE", "alice password", nil},
+		{"Alice", "wrong password", ErrInvalidCredentials},
+		{"Alice", "", ErrInvalidCredentials},
+		{"Bob", "alice password", ErrInvalidCredentials},
+	}
+	check := func(accounts *Accounts) {
+		t.Helper()
+
+		for _, test := range tests {
+			account, err := accounts.Login(test.name, test.password)
+			if !errors.Is(err, test.err) {
+				t.Errorf("got error %v logging in as %s with %q, want %v", err, test.name, test.password, test.err)
+				continue
+			}
+			if err == nil && (account.ID != alice.ID || account.Name != "Alice") {
+				t.Errorf("got account %s %s, want %s Alice", account.ID, account.Name, alice.ID)
+			}
+		}
+	}
+	check(accounts)
+
+	reloaded, err := NewAccounts(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(reloaded)
+}
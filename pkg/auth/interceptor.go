@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type claimsKey struct{}
+
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+
+func (tokens *Tokens) authenticate(ctx context.Context) (context.Context, error) {
+	headers, _ := metadata.FromIncomingContext(ctx)
+
+	tokenRaw := headers["authorization"]
+	if len(tokenRaw) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "no token provided")
+	}
+
+	claims, err := tokens.Verify(tokenRaw[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return NewContext(ctx, claims), nil
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authenticatedStream) Context() context.Context {
+	return stream.ctx
+}
+
+// UnaryServerInterceptor authenticates every unary call except the methods
+// listed as public, which are the calls used to obtain a token.
+func (tokens *Tokens) UnaryServerInterceptor(publicMethods ...string) grpc.UnaryServerInterceptor {
+	public := make(map[string]bool)
+	for _, method := range publicMethods {
+		public[method] = true
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if public[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, err := tokens.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (tokens *Tokens) StreamServerInterceptor(publicMethods ...string) grpc.StreamServerInterceptor {
+	public := make(map[string]bool)
+	for _, method := range publicMethods {
+		public[method] = true
+	}
+
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if public[info.FullMethod] {
+			return handler(srv, stream)
+		}
+
+		ctx, err := tokens.authenticate(stream.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
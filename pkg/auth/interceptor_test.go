@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *testStream) Context() context.Context {
+	return stream.ctx
+}
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", token))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	tokens := newTestTokens(t, NewSecret(), "main", time.Hour)
+	token, claims := issue(t, tokens)
+	interceptor := tokens.UnaryServerInterceptor("/test/Public")
+
+	var got *Claims
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got, _ = FromContext(ctx)
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		code   codes.Code
+		claims *Claims
+	}{
+		{"no token", context.Background(), "/test/Private", codes.Unauthenticated, nil},
+		{"invalid token", withToken("invalid"), "/test/Private", codes.Unauthenticated, nil},
+		{"valid token", withToken(token), "/test/Private", codes.OK, claims},
+		{"public method", context.Background(), "/test/Public", codes.OK, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got = nil
+			_, err := interceptor(test.ctx, nil, &grpc.UnaryServerInfo{FullMethod: test.method}, handler)
+			if code := status.Code(err); code != test.code {
+				t.Fatalf("got code %v (%v), want %v", code, err, test.code)
+			}
+			if test.claims != nil && (got == nil || got.TokenID != test.claims.TokenID) {
+				t.Errorf("got claims %+v in the handler, want %+v", got, test.claims)
+			}
+		})
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	tokens := newTestTokens(t, NewSecret(), "main", time.Hour)
+	token, claims := issue(t, tokens)
+	interceptor := tokens.StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/test/Stream"}
+
+	called := false
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		called = true
+		if got, ok := FromContext(stream.Context()); !ok || got.TokenID != claims.TokenID {
+			t.Errorf("got claims %+v in the handler, want %+v", got, claims)
+		}
+		return nil
+	}
+
+	err := interceptor(nil, &testStream{ctx: context.Background()}, info, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("got error %v without a token, want code %v", err, codes.Unauthenticated)
+	}
+	if called {
+		t.Error("handler was called without a token")
+	}
+
+	if err := interceptor(nil, &testStream{ctx: withToken(token)}, info, handler); err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Error("handler was not called with a valid token")
+	}
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type revocation struct {
+	TokenID uuid.UUID `json:"jti"`
+	Expires time.Time `json:"exp"`
+}
+
+// Revocations only needs to remember a token until it would have expired
+// anyway, so entries are pruned once their expiry has passed. Tokens signed
+// with a configured secret outlive the server, so the revocations are saved
+// to path to outlive it as well. An empty path keeps them in memory only.
+type Revocations struct {
+	path    string
+	mu      sync.Mutex
+	revoked map[uuid.UUID]time.Time
+}
+
+func NewRevocations(path string) (*Revocations, error) {
+	revocations := &Revocations{
+		path:    path,
+		revoked: make(map[uuid.UUID]time.Time),
+	}
+	if path == "" {
+		return revocations, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return revocations, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]revocation, 0)
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	for _, entry := range list {
+		revocations.revoked[entry.TokenID] = entry.Expires
+	}
+	revocations.prune(time.Now())
+	return revocations, nil
+}
+
+func (revocations *Revocations) save() error {
+	if revocations.path == "" {
+		return nil
+	}
+
+	list := make([]revocation, 0, len(revocations.revoked))
+	for id, expires := range revocations.revoked {
+		list = append(list, revocation{TokenID: id, Expires: expires})
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(revocations.path), ".revocations-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), revocations.path)
+}
+
+func (revocations *Revocations) prune(now time.Time) {
+	for id, expires := range revocations.revoked {
+		if now.After(expires) {
+			delete(revocations.revoked, id)
+		}
+	}
+}
+
+// Revoke rejects the token from now on. The revocation holds in memory even
+// if it could not be saved.
+func (revocations *Revocations) Revoke(tokenID uuid.UUID, expires time.Time) error {
+	revocations.mu.Lock()
+	defer revocations.mu.Unlock()
+
+	revocations.prune(time.Now())
+	revocations.revoked[tokenID] = expires
+	return revocations.save()
+}
+
+func (revocations *Revocations) IsRevoked(tokenID uuid.UUID) bool {
+	revocations.mu.Lock()
+	defer revocations.mu.Unlock()
+
+	_, ok := revocations.revoked[tokenID]
+	return ok
+}
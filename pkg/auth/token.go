@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrMalformedToken = errors.New("cannot parse token")
+	ErrInvalidToken   = errors.New("token signature is not valid")
+	ErrExpiredToken   = errors.New("token has expired")
+	ErrRevokedToken   = errors.New("token has been revoked")
+	ErrWrongServer    = errors.New("token was issued by another server")
+)
+
+type Claims struct {
+	TokenID   uuid.UUID `json:"jti"`
+	PlayerID  uuid.UUID `json:"sub"`
+	Name      string    `json:"name"`
+	RoomID    uuid.UUID `json:"room"`
+	Spectator bool      `json:"spec,omitempty"`
+	ServerID  string    `json:"srv"`
+	IssuedAt  int64     `json:"iat"`
+	ExpiresAt int64     `json:"exp"`
+}
+
+func (claims *Claims) Expires() time.Time {
+	return time.Unix(claims.ExpiresAt, 0)
+}
+
+type Tokens struct {
+	secret   []byte
+	serverID string
+	lifetime time.Duration
+	revoked  *Revocations
+}
+
+func NewSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(fmt.Sprintf("unable to generate token secret: %v", err))
+	}
+	return secret
+}
+
+func NewTokens(secret []byte, serverID string, lifetime time.Duration, revoked *Revocations) *Tokens {
+	return &Tokens{
+		secret:   secret,
+		serverID: serverID,
+		lifetime: lifetime,
+		revoked:  revoked,
+	}
+}
+
+func (tokens *Tokens) sign(payload string) string {
+	mac := hmac.New(sha256.New, tokens.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (tokens *Tokens) Issue(playerID uuid.UUID, name string, roomID uuid.UUID, spectator bool) (string, *Claims, error) {
+	now := time.Now()
+	claims := &Claims{
+		TokenID:   uuid.New(),
+		PlayerID:  playerID,
+		Name:      name,
+		RoomID:    roomID,
+		Spectator: spectator,
+		ServerID:  tokens.serverID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(tokens.lifetime).Unix(),
+	}
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return "", nil, err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + tokens.sign(payload), claims, nil
+}
+
+func (tokens *Tokens) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, ErrMalformedToken
+	}
+
+	if !hmac.Equal([]byte(parts[1]), []byte(tokens.sign(parts[0]))) {
+		return nil, ErrInvalidToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	claims := &Claims{}
+	if err := json.Unmarshal(data, claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if claims.ServerID != tokens.serverID {
+		return nil, ErrWrongServer
+	}
+	if time.Now().After(claims.Expires()) {
+		return nil, ErrExpiredToken
+	}
+	if tokens.revoked.IsRevoked(claims.TokenID) {
+		return nil, ErrRevokedToken
+	}
+	return claims, nil
+}
+
+func (tokens *Tokens) Refresh(claims *Claims) (string, *Claims, error) {
+	token, refreshed, err := tokens.Issue(claims.PlayerID, claims.Name, claims.RoomID, claims.Spectator)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := tokens.Revoke(claims); err != nil {
+		return "", nil, err
+	}
+	return token, refreshed, nil
+}
+
+func (tokens *Tokens) Revoke(claims *Claims) error {
+	return tokens.revoked.Revoke(claims.TokenID, claims.Expires())
+}
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newTestTokens(t *testing.T, secret []byte, serverID string, lifetime time.Duration) *Tokens {
+	t.Helper()
+
+	revocations, err := NewRevocations("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewTokens(secret, serverID, lifetime, revocations)
+}
+
+func issue(t *testing.T, tokens *Tokens) (string, *Claims) {
+	t.Helper()
+
+	token, claims, err := tokens.Issue(uuid.New(), "Alice", uuid.New(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token, claims
+}
+
+func TestVerify(t *testing.T) {
+	secret := NewSecret()
+	tokens := newTestTokens(t, secret, "main", time.Hour)
+
+	valid, _ := issue(t, tokens)
+	expired, _ := issue(t, newTestTokens(t, secret, "main", -time.Minute))
+	otherServer, _ := issue(t, newTestTokens(t, secret, "other", time.Hour))
+	otherSecret, _ := issue(t, newTestTokens(t, NewSecret(), "main", time.Hour))
+	revoked, revokedClaims := issue(t, tokens)
+	if err := tokens.Revoke(revokedClaims); err != nil {
+		t.Fatal(err)
+	}
+
+	// The payload of another token with the signature of a valid one.
+	payload := strings.Split(otherServer, ".")[0]
+	signature := strings.Split(valid, ".")[1]
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"valid", valid, nil},
+		{"expired", expired, ErrExpiredToken},
+		{"wrong server", otherServer, ErrWrongServer},
+		{"other secret", otherSecret, ErrInvalidToken},
+		{"revoked", revoked, ErrRevokedToken},
+		{"tampered payload", payload + "." + signature, ErrInvalidToken},
+		{"tampered signature", valid + "x", ErrInvalidToken},
+		{"no signature", payload, ErrMalformedToken},
+		{"empty", "", ErrMalformedToken},
+		{"signed garbage", "bm90IGpzb24." + tokens.sign("bm90IGpzb24"), ErrMalformedToken},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims, err := tokens.Verify(test.token)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got error %v, want %v", err, test.err)
+			}
+			if err == nil && claims.Name != "Alice" {
+				t.Errorf("got name %q, want Alice", claims.Name)
+			}
+		})
+	}
+}
+
+func TestRefreshRevokesOldToken(t *testing.T) {
+	tokens := newTestTokens(t, NewSecret(), "main", time.Hour)
+	token, claims := issue(t, tokens)
+
+	refreshed, refreshedClaims, err := tokens.Refresh(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshedClaims.PlayerID != claims.PlayerID || refreshedClaims.TokenID == claims.TokenID {
+		t.Errorf("got refreshed claims %+v for %+v", refreshedClaims, claims)
+	}
+	if _, err := tokens.Verify(token); !errors.Is(err, ErrRevokedToken) {
+		t.Errorf("got error %v for the old token, want %v", err, ErrRevokedToken)
+	}
+	if _, err := tokens.Verify(refreshed); err != nil {
+		t.Errorf("got error %v for the refreshed token", err)
+	}
+}
+
+func TestRevocationsSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "revocations.json")
+	secret := NewSecret()
+
+	revocations, err := NewRevocations(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := NewTokens(secret, "main", time.Hour, revocations)
+	revoked, claims := issue(t, tokens)
+	valid, _ := issue(t, tokens)
+	if err := tokens.Revoke(claims); err != nil {
+		t.Fatal(err)
+	}
+	// An expired revocation is dropped with the next one.
+	if err := revocations.Revoke(uuid.New(), time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	revocations, err = NewRevocations(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revocations.revoked) != 1 {
+		t.Errorf("got %d revocations after the restart, want 1", len(revocations.revoked))
+	}
+	tokens = NewTokens(secret, "main", time.Hour, revocations)
+	if _, err := tokens.Verify(revoked); !errors.Is(err, ErrRevokedToken) {
+		t.Errorf("got error %v for the revoked token, want %v", err, ErrRevokedToken)
+	}
+	if _, err := tokens.Verify(valid); err != nil {
+		t.Errorf("got error %v for the valid token", err)
+	}
+}
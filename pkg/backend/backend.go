@@ -3,6 +3,7 @@ package backend
 import (
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -60,6 +61,12 @@ const (
 type Change interface{}
 
 func (game *Game) AddEntity(entity Identifier) {
+	if laser, ok := entity.(*Laser); ok {
+		laser.clock = game.clock
+		if laser.speed == 0 {
+			laser.speed = game.rules.LaserSpeed
+		}
+	}
 	game.Entities[entity.ID()] = entity
 }
 
@@ -76,18 +83,12 @@ func (game *Game) RemoveEntity(id uuid.UUID) {
 }
 
 const (
-	roundOverScore = 10
-	newRoundWaitTime = time.Second * 10
 	collisionCheckFrequency = time.Millisecond * 10
-	moveThrottle = time.Millisecond * 100
-	laserThrottle = time.Millisecond * 500
-	laserSpeed = 50
 )
 
+// checkLastActionTime is called from Perform, which already holds game.Mu.
 func (game *Game) checkLastActionTime(actionKey string, created time.Time, throttle time.Duration) bool {
-	game.Mu.RLock()
 	lastAction, ok := game.lastAction[actionKey]
-	game.Mu.RUnlock()
 
 	if ok && lastAction.After(created.Add(-1 * throttle)) {
 		return false
@@ -154,7 +155,8 @@ func (action MoveAction) Perform(game *Game) {
 	}
 
 	actionKey := fmt.Sprintf("%T:%s", action, entity.ID().String())
-	if !game.checkLastActionTime(actionKey, action.Created, moveThrottle) {
+	if !game.checkLastActionTime(actionKey, action.Created, game.rules.MoveThrottle) {
+		game.observer.ActionThrottled(action)
 		return
 	}
 
@@ -216,6 +218,19 @@ type Game struct {
 	NewRoundAt      time.Time
 	gameMap         [][]rune
 	spawnPointIndex int
+	Mode            GameMode
+	clock           Clock
+	rng             *RNG
+	observer        Observer
+	rules           Rules
+	// nextRules, nextMap and nextMode are applied when the next round starts
+	// if they are set.
+	nextRules *Rules
+	nextMap   [][]rune
+	nextMode  GameMode
+	stop      chan struct{}
+	stopOnce  sync.Once
+	err       error
 }
 
 func NewGame() *Game {
@@ -229,10 +244,52 @@ func NewGame() *Game {
 		Score:           make(map[uuid.UUID]int),
 		gameMap:         MapDefault,
 		spawnPointIndex: 0,
+		Mode:            ModeDeathmatch,
+		clock:           RealClock,
+		rng:             NewRNG(time.Now().UnixNano()),
+		observer:        nopObserver{},
+		rules:           DefaultRules(),
+		stop:            make(chan struct{}),
 	}
 	return &game
 }
 
+// SetClock replaces the real clock, e.g. with a ManualClock. It has to be
+// called before entities are added and the game is started.
+func (game *Game) SetClock(clock Clock) {
+	game.clock = clock
+}
+
+func (game *Game) Clock() Clock {
+	return game.clock
+}
+
+// SetRNG replaces the randomly seeded RNG so the game can be reproduced. It has
+// to be called before the game is started.
+func (game *Game) SetRNG(rng *RNG) {
+	game.rng = rng
+}
+
+func (game *Game) RNG() *RNG {
+	return game.rng
+}
+
+// sortedEntities lists the entities in a stable order, so that resolving
+// them does not depend on the order of a map.
+func (game *Game) sortedEntities() []Identifier {
+	ids := make([]uuid.UUID, 0, len(game.Entities))
+	for id := range game.Entities {
+		ids = append(ids, id)
+	}
+	SortIDs(ids)
+
+	entities := make([]Identifier, 0, len(ids))
+	for _, id := range ids {
+		entities = append(entities, game.Entities[id])
+	}
+	return entities
+}
+
 type LaserRemoveChange struct {
 	Change
 	ID uuid.UUID
@@ -251,11 +308,20 @@ type RoundStartChange struct {
 }
 
 func (game *Game) startNewRound() {
+	if game.nextRules != nil {
+		game.rules = *game.nextRules
+		game.nextRules = nil
+	}
+	if game.nextMap != nil {
+		game.applyMap(game.nextMap, game.nextMode)
+		game.nextMap = nil
+	}
+
 	game.WaitForRound = false
 	game.Score = map[uuid.UUID]int{}
 	i := 0
 	spawnPoints := game.GetMapByType()[MapTypeSpawn]
-	for _, entity := range game.Entities {
+	for _, entity := range game.sortedEntities() {
 		player, ok := entity.(*Player)
 		if !ok {
 			continue
@@ -269,40 +335,126 @@ func (game *Game) startNewRound() {
 
 func (game *Game) queueNewRound(roundWinner uuid.UUID) {
 	game.WaitForRound = true
-	game.NewRoundAt = time.Now().Add(newRoundWaitTime)
+	game.NewRoundAt = game.clock.Now().Add(game.rules.NewRoundWait)
 	game.RoundWinner = roundWinner
 
 	game.sendChange(RoundOverChange{})
+}
 
-	go func() {
-		time.Sleep(newRoundWaitTime)
+// leader is the player with the highest score, or no one before the first
+// kill. Ties go to the lowest ID so the result does not depend on map order.
+func (game *Game) leader() uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(game.Score))
+	for id := range game.Score {
+		ids = append(ids, id)
+	}
+	SortIDs(ids)
 
-		game.Mu.Lock()
-		game.startNewRound()
-		game.Mu.Unlock()
-	}()
+	var leader uuid.UUID
+	for _, id := range ids {
+		if leader == uuid.Nil || game.Score[id] > game.Score[leader] {
+			leader = id
+		}
+	}
+	return leader
+}
+
+// EndRound ends the round early with the current leader as the winner, the
+// next round starts after the usual wait.
+func (game *Game) EndRound() {
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	if game.WaitForRound {
+		return
+	}
+	game.queueNewRound(game.leader())
+}
+
+// RestartRound starts a new round right away.
+func (game *Game) RestartRound() {
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	game.startNewRound()
+}
+
+// ChangeMap switches the map and mode of a running game and starts a new
+// round on it. Lasers are dropped without a change each, clients drop theirs
+// when the round starts on a new map, and the teams are drawn again.
+func (game *Game) ChangeMap(gameMap [][]rune, mode GameMode) {
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	game.applyMap(gameMap, mode)
+	game.nextMap = nil
+	game.startNewRound()
+}
+
+// applyMap must be called with game.Mu held, before a new round starts.
+func (game *Game) applyMap(gameMap [][]rune, mode GameMode) {
+	for _, entity := range game.sortedEntities() {
+		if _, ok := entity.(*Laser); ok {
+			game.RemoveEntity(entity.ID())
+		}
+	}
+
+	game.SetMap(gameMap)
+	game.Mode = mode
+	for _, entity := range game.sortedEntities() {
+		if player, ok := entity.(*Player); ok {
+			player.Team = 0
+		}
+	}
+	for _, entity := range game.sortedEntities() {
+		if player, ok := entity.(*Player); ok {
+			player.Team = game.NextTeam()
+		}
+	}
 }
 
 func (game *Game) AddScore(id uuid.UUID) {
 	game.Score[id]++
 }
 
+// SendAction queues an action unless the game has been stopped, in which case
+// nobody would ever read it.
+func (game *Game) SendAction(action Action) bool {
+	select {
+	case game.ActionChannel <- action:
+		return true
+	case <-game.stop:
+		return false
+	}
+}
+
+// Apply performs an action right away, the way the action loop does. Actions
+// are dropped while waiting for the next round.
+func (game *Game) Apply(action Action) {
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	if game.WaitForRound {
+		return
+	}
+	game.observer.ActionApplied(action)
+	action.Perform(game)
+}
+
 func (game *Game) watchActions() {
 	for {
-		action := <-game.ActionChannel
-		if game.WaitForRound {
-			continue
+		select {
+		case <-game.stop:
+			return
+		case action := <-game.ActionChannel:
+			game.Apply(action)
 		}
-
-		game.Mu.Lock()
-		action.Perform(game)
-		game.Mu.Unlock()
 	}
 }
 
 func (game *Game) getCollisionMap() map[Coordinate][]Identifier {
 	collisionMap := map[Coordinate][]Identifier{}
-	for _, entity := range game.Entities {
+	for _, entity := range game.sortedEntities() {
 		positioner, ok := entity.(Positioner)
 		if !ok {
 			continue
@@ -314,99 +466,172 @@ func (game *Game) getCollisionMap() map[Coordinate][]Identifier {
 	return collisionMap
 }
 
-func (game *Game) watchCollisions() {
-	for {
-		game.Mu.Lock()
-		spawnPoints := game.GetMapByType()[MapTypeSpawn]
-		collisionMap := game.getCollisionMap()
+// Tick runs one step of the game loop, it resolves collisions and starts the
+// next round once it is due.
+func (game *Game) Tick() {
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
 
-		for _, entities := range collisionMap {
-			if len(entities) <= 1 {
-				continue
-			}
+	// The observer gets the real duration, also when the game runs on a
+	// manual clock.
+	started := time.Now()
+	game.checkCollisions()
+	if game.IsAuthoritative && game.WaitForRound && !game.clock.Now().Before(game.NewRoundAt) {
+		game.startNewRound()
+	}
+	game.observer.Ticked(time.Since(started))
+}
 
-			hasLaser := false
-			var laserOwnerID uuid.UUID
-			for _, entity := range entities {
-				laser, ok := entity.(*Laser)
-				if ok {
-					hasLaser = true
-					laserOwnerID = laser.OwnerID
-					break
-				}
-			}
+// checkCollisions must be called with game.Mu held.
+func (game *Game) checkCollisions() {
+	spawnPoints := game.GetMapByType()[MapTypeSpawn]
+	collisionMap := game.getCollisionMap()
+
+	positions := make([]Coordinate, 0, len(collisionMap))
+	for position := range collisionMap {
+		positions = append(positions, position)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Y != positions[j].Y {
+			return positions[i].Y < positions[j].Y
+		}
+		return positions[i].X < positions[j].X
+	})
+
+	for _, position := range positions {
+		entities := collisionMap[position]
+		if len(entities) <= 1 {
+			continue
+		}
 
-			if !hasLaser {
-				continue
+		hasLaser := false
+		var laserOwnerID uuid.UUID
+		for _, entity := range entities {
+			laser, ok := entity.(*Laser)
+			if ok {
+				hasLaser = true
+				laserOwnerID = laser.OwnerID
+				break
 			}
+		}
 
-			for _, entity := range entities {
-				switch type_entity := entity.(type) {
-				case *Player:
-					if !game.IsAuthoritative {
-						continue
-					}
+		if !hasLaser {
+			continue
+		}
 
-					player := type_entity
-					if player.ID() == laserOwnerID {
-						continue
-					}
+		for _, entity := range entities {
+			switch type_entity := entity.(type) {
+			case *Player:
+				if !game.IsAuthoritative {
+					continue
+				}
 
-					spawnPoint := spawnPoints[game.spawnPointIndex % len(spawnPoints)]
-					game.spawnPointIndex++
+				player := type_entity
+				if player.ID() == laserOwnerID || game.isTeammate(laserOwnerID, player) {
+					continue
+				}
 
-					player.Move(spawnPoint)
+				spawnPoint := spawnPoints[game.spawnPointIndex%len(spawnPoints)]
+				game.spawnPointIndex++
 
-					change := PlayerRespawnChange{
-						Player:     player,
-						KilledByID: laserOwnerID,
-					}
+				player.Move(spawnPoint)
 
-					game.sendChange(change)
-					game.AddScore(laserOwnerID)
+				change := PlayerRespawnChange{
+					Player:     player,
+					KilledByID: laserOwnerID,
+				}
 
-					if game.Score[laserOwnerID] >= roundOverScore {
-						game.queueNewRound(laserOwnerID)
-					}
+				game.sendChange(change)
+				game.AddScore(laserOwnerID)
 
-				case *Laser:
-					change := RemoveEntityChange{
-						Entity: entity,
-					}
+				if game.Score[laserOwnerID] >= game.rules.RoundOverScore {
+					game.queueNewRound(laserOwnerID)
+				}
 
-					game.sendChange(change)
-					game.RemoveEntity(entity.ID())
+			case *Laser:
+				change := RemoveEntityChange{
+					Entity: entity,
 				}
-			}
-		}
 
-		for _, wall := range game.GetMapByType()[MapTypeWall] {
-			entities, ok := collisionMap[wall]
-			if !ok {
-				continue
+				game.sendChange(change)
+				game.RemoveEntity(entity.ID())
 			}
+		}
+	}
 
-			for _, entity := range entities {
-				switch entity.(type) {
-				case *Laser:
-					change := RemoveEntityChange{
-						Entity: entity,
-					}
+	for _, wall := range game.GetMapByType()[MapTypeWall] {
+		entities, ok := collisionMap[wall]
+		if !ok {
+			continue
+		}
 
-					game.sendChange(change)
-					game.RemoveEntity(entity.ID())
+		for _, entity := range entities {
+			switch entity.(type) {
+			case *Laser:
+				change := RemoveEntityChange{
+					Entity: entity,
 				}
+
+				game.sendChange(change)
+				game.RemoveEntity(entity.ID())
 			}
 		}
+	}
+}
 
-		game.Mu.Unlock()
-		time.Sleep(collisionCheckFrequency)
+func (game *Game) watchCollisions() {
+	for {
+		game.Tick()
+
+		select {
+		case <-game.stop:
+			return
+		case <-game.clock.After(collisionCheckFrequency):
+		}
 	}
 }
 
+// run recovers a panic in a game loop and stops the game instead of taking
+// down the whole process, so one broken game does not affect the others.
+func (game *Game) run(loop func()) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				game.fail(fmt.Errorf("game loop panicked: %v", r))
+			}
+		}()
+		loop()
+	}()
+}
+
+func (game *Game) fail(err error) {
+	game.stopOnce.Do(func() {
+		game.err = err
+		close(game.stop)
+	})
+}
+
 func (game *Game) Start() {
-	go game.watchActions()
-	go game.watchCollisions()
+	game.run(game.watchActions)
+	game.run(game.watchCollisions)
+}
+
+func (game *Game) Stop() {
+	game.fail(nil)
+}
+
+// Done is closed once the game has been stopped or one of its loops failed.
+func (game *Game) Done() <-chan struct{} {
+	return game.stop
+}
+
+func (game *Game) Err() error {
+	select {
+	case <-game.stop:
+		return game.err
+	default:
+		return nil
+	}
 }
 
 func (c1 Coordinate) Add(c2 Coordinate) Coordinate {
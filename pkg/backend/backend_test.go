@@ -0,0 +1,345 @@
+package backend_test
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	"github.com/nikit34/multiplayer_rpg/pkg/backend/simtest"
+	"github.com/nikit34/multiplayer_rpg/pkg/bot"
+)
+
+func TestMove(t *testing.T) {
+	simtest.Run(t, []simtest.Scenario{
+		{
+			Name:        "move into an empty tile",
+			Map:         []string{"#A..#"},
+			Steps:       []string{"A move right"},
+			Want:        []string{"#.A.#"},
+			WantChanges: []string{"move A right"},
+		},
+		{
+			Name:        "move into wall is rejected",
+			Map:         []string{"#A..#"},
+			Steps:       []string{"A move left"},
+			Want:        []string{"#A..#"},
+			WantChanges: []string{},
+		},
+		{
+			Name:        "move into player is rejected",
+			Map:         []string{"#AB.#"},
+			Steps:       []string{"A move right"},
+			Want:        []string{"#AB.#"},
+			WantChanges: []string{},
+		},
+		{
+			Name: "moves are throttled",
+			Map:  []string{"#A...#"},
+			Steps: []string{
+				"A move right",
+				"wait 90ms",
+				"A move right",
+				"wait 10ms",
+				"A move right",
+			},
+			Want:        []string{"#..A.#"},
+			WantChanges: []string{"move A right", "move A right"},
+		},
+	})
+}
+
+func TestLaser(t *testing.T) {
+	simtest.Run(t, []simtest.Scenario{
+		{
+			Name:        "laser flies one tile every 50ms",
+			Map:         []string{"#A....#"},
+			Steps:       []string{"A shoot right", "wait 100ms"},
+			Want:        []string{"#A..*.#"},
+			WantChanges: []string{"laser A right"},
+		},
+		{
+			Name: "laser hits player behind one tile",
+			Map: []string{
+				"#S...#",
+				"#A.B.#",
+			},
+			Steps: []string{"A shoot right", "wait 60ms"},
+			Want: []string{
+				"#B...#",
+				"#A...#",
+			},
+			WantScore:   map[rune]int{'A': 1, 'B': 0},
+			WantChanges: []string{"laser A right", "remove laser A", "respawn B by A"},
+		},
+		{
+			Name:        "laser is stopped by a wall",
+			Map:         []string{"#A.#B#"},
+			Steps:       []string{"A shoot right", "wait 100ms"},
+			Want:        []string{"#A.#B#"},
+			WantScore:   map[rune]int{'A': 0},
+			WantChanges: []string{"laser A right", "remove laser A"},
+		},
+		{
+			Name: "lasers are throttled",
+			Map:  []string{"#A.#"},
+			Steps: []string{
+				"A shoot right",
+				"wait 490ms",
+				"A shoot right",
+				"wait 10ms",
+				"A shoot right",
+				"wait 60ms",
+			},
+			Want: []string{"#A.#"},
+			WantChanges: []string{
+				"laser A right",
+				"remove laser A",
+				"laser A right",
+				"remove laser A",
+			},
+		},
+		{
+			Name: "teammates are not hit",
+			Map: []string{
+				"#S...#",
+				"#A.B.#",
+			},
+			Mode:  backend.ModeTeamDeathmatch,
+			Teams: map[rune]int{'A': 1, 'B': 1},
+			Steps: []string{"A shoot right", "wait 60ms"},
+			Want: []string{
+				"#S...#",
+				"#A.B.#",
+			},
+			WantScore:   map[rune]int{'A': 0},
+			WantChanges: []string{"laser A right", "remove laser A"},
+		},
+		{
+			Name: "other team is hit",
+			Map: []string{
+				"#S...#",
+				"#A.B.#",
+			},
+			Mode:        backend.ModeTeamDeathmatch,
+			Teams:       map[rune]int{'A': 0, 'B': 1},
+			Steps:       []string{"A shoot right", "wait 60ms"},
+			WantScore:   map[rune]int{'A': 1},
+			WantChanges: []string{"laser A right", "remove laser A", "respawn B by A"},
+		},
+	})
+}
+
+func TestRound(t *testing.T) {
+	simtest.Run(t, []simtest.Scenario{
+		{
+			Name: "round ends at 10 points",
+			Map: []string{
+				"#S..S#",
+				"#A.B.#",
+			},
+			Score:     map[rune]int{'A': 9},
+			Steps:     []string{"A shoot right", "wait 60ms"},
+			WantScore: map[rune]int{'A': 10},
+			WantChanges: []string{
+				"laser A right",
+				"remove laser A",
+				"respawn B by A",
+				"round over",
+			},
+			WantWinner: 'A',
+		},
+		{
+			Name: "actions are dropped until the next round",
+			Map: []string{
+				"#S..S#",
+				"#A.B.#",
+			},
+			Score: map[rune]int{'A': 9},
+			Steps: []string{
+				"A shoot right",
+				"wait 60ms",
+				"A move right",
+				"A shoot right",
+				"wait 9s",
+			},
+			Want: []string{
+				"#B..S#",
+				"#A...#",
+			},
+			WantChanges: []string{
+				"laser A right",
+				"remove laser A",
+				"respawn B by A",
+				"round over",
+			},
+			WantWinner: 'A',
+		},
+		{
+			Name: "next round starts after 10 seconds",
+			Map: []string{
+				"#S..S#",
+				"#A.B.#",
+			},
+			Score: map[rune]int{'A': 9, 'B': 3},
+			Steps: []string{"A shoot right", "wait 60ms", "wait 10s"},
+			Want: []string{
+				"#A..B#",
+				"#....#",
+			},
+			WantScore: map[rune]int{'A': 0, 'B': 0},
+			WantChanges: []string{
+				"laser A right",
+				"remove laser A",
+				"respawn B by A",
+				"round over",
+				"round start",
+			},
+		},
+	})
+}
+
+func TestRules(t *testing.T) {
+	rules := backend.DefaultRules()
+	rules.RoundOverScore = 3
+	rules.LaserSpeed = 100 * time.Millisecond
+	rules.MoveThrottle = 0
+
+	simtest.Run(t, []simtest.Scenario{
+		{
+			Name:        "laser flies at the speed of the rules",
+			Map:         []string{"#A....#"},
+			Rules:       &rules,
+			Steps:       []string{"A shoot right", "wait 100ms"},
+			Want:        []string{"#A.*..#"},
+			WantChanges: []string{"laser A right"},
+		},
+		{
+			Name:        "moves are not throttled without a move throttle",
+			Map:         []string{"#A...#"},
+			Rules:       &rules,
+			Steps:       []string{"A move right", "A move right"},
+			Want:        []string{"#..A.#"},
+			WantChanges: []string{"move A right", "move A right"},
+		},
+		{
+			Name: "round ends at the score of the rules",
+			Map: []string{
+				"#S..S#",
+				"#A.B.#",
+			},
+			Rules:     &rules,
+			Score:     map[rune]int{'A': 2},
+			Steps:     []string{"A shoot right", "wait 110ms"},
+			WantScore: map[rune]int{'A': 3},
+			WantChanges: []string{
+				"laser A right",
+				"remove laser A",
+				"respawn B by A",
+				"round over",
+			},
+			WantWinner: 'A',
+		},
+	})
+}
+
+// outcome is what has to come out the same when a match is played again from
+// its seed.
+type outcome struct {
+	positions map[string]backend.Coordinate
+	scores    map[string]int
+	laserIDs  []uuid.UUID
+}
+
+// playSeeded plays a match of a scripted player against two bots on a manual
+// clock. The bots and the laser IDs draw from the game RNG, the way they do
+// on the server.
+func playSeeded(t *testing.T, seed int64) outcome {
+	t.Helper()
+
+	sim, err := simtest.New(
+		"##########",
+		"#S......S#",
+		"#..#..#..#",
+		"#A.......#",
+		"#S......S#",
+		"##########",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Game.SetRNG(backend.NewRNG(seed))
+	bots := bot.NewBots(sim.Game)
+	bots.AddBot("Bob 0")
+	bots.AddBot("Bob 1")
+
+	directions := []backend.Direction{backend.DirectionRight, backend.DirectionUp, backend.DirectionDown}
+	for step := 0; step < 1000; step++ {
+		if step%20 == 0 {
+			for _, action := range bots.Decide() {
+				sim.Game.Apply(action)
+			}
+		}
+		if step%35 == 0 {
+			sim.Game.Apply(backend.LaserAction{
+				ID:        sim.Game.RNG().UUID(),
+				OwnerID:   sim.Player('A').ID(),
+				Direction: directions[(step/35)%len(directions)],
+				Created:   sim.Clock.Now(),
+			})
+		}
+		sim.Wait(simtest.Tick)
+	}
+
+	result := outcome{
+		positions: make(map[string]backend.Coordinate),
+		scores:    make(map[string]int),
+	}
+	for _, entity := range sim.Game.Entities {
+		if player, ok := entity.(*backend.Player); ok {
+			result.positions[player.Name] = player.Position()
+			result.scores[player.Name] = sim.Game.Score[player.ID()]
+		}
+	}
+	for _, change := range sim.Changes {
+		if add, ok := change.(backend.AddEntityChange); ok {
+			if laser, ok := add.Entity.(*backend.Laser); ok {
+				result.laserIDs = append(result.laserIDs, laser.ID())
+			}
+		}
+	}
+	return result
+}
+
+func (result outcome) String() string {
+	names := make([]string, 0, len(result.positions))
+	for name := range result.positions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	text := ""
+	for _, name := range names {
+		text += fmt.Sprintf("%s at %v with %d, ", name, result.positions[name], result.scores[name])
+	}
+	return fmt.Sprintf("%s%d lasers", text, len(result.laserIDs))
+}
+
+func TestDeterminism(t *testing.T) {
+	first := playSeeded(t, 42)
+	second := playSeeded(t, 42)
+	if len(first.laserIDs) == 0 {
+		t.Fatal("no laser was shot")
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("the same seed played out differently:\n  %v\n  %v", first, second)
+	}
+
+	other := playSeeded(t, 43)
+	if reflect.DeepEqual(first.laserIDs, other.laserIDs) {
+		t.Error("another seed drew the same laser IDs")
+	}
+}
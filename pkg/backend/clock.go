@@ -0,0 +1,137 @@
+package backend
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Clock is where a game gets the time from. Games use the real time unless a
+// simulation injects a ManualClock to advance time step by step.
+type Clock interface {
+	Now() time.Time
+	// After sends the time on the returned channel once d has passed.
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// RealClock is the wall clock.
+var RealClock Clock = realClock{}
+
+type manualTimer struct {
+	at      time.Time
+	channel chan time.Time
+}
+
+// ManualClock only moves when Advance is called.
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []manualTimer
+}
+
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+func (clock *ManualClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	return clock.now
+}
+
+func (clock *ManualClock) After(d time.Duration) <-chan time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	channel := make(chan time.Time, 1)
+	if d <= 0 {
+		channel <- clock.now
+		return channel
+	}
+	clock.timers = append(clock.timers, manualTimer{at: clock.now.Add(d), channel: channel})
+	return channel
+}
+
+// Advance moves the clock forward and fires the timers that are due, earliest
+// first.
+func (clock *ManualClock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	clock.now = clock.now.Add(d)
+	sort.SliceStable(clock.timers, func(i, j int) bool {
+		return clock.timers[i].at.Before(clock.timers[j].at)
+	})
+
+	pending := clock.timers[:0]
+	for _, timer := range clock.timers {
+		if timer.at.After(clock.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.channel <- timer.at
+	}
+	clock.timers = pending
+}
+
+// RNG is a seeded source of randomness that is safe for concurrent use. A
+// game, its bots and the server draw from one so that a match can be played
+// again from its seed.
+type RNG struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func NewRNG(seed int64) *RNG {
+	return &RNG{rand: rand.New(rand.NewSource(seed))}
+}
+
+func (rng *RNG) Intn(n int) int {
+	rng.mu.Lock()
+	defer rng.mu.Unlock()
+
+	return rng.rand.Intn(n)
+}
+
+func (rng *RNG) Int63() int64 {
+	rng.mu.Lock()
+	defer rng.mu.Unlock()
+
+	return rng.rand.Int63()
+}
+
+// UUID returns a random version 4 UUID drawn from the seed.
+func (rng *RNG) UUID() uuid.UUID {
+	rng.mu.Lock()
+	defer rng.mu.Unlock()
+
+	id, err := uuid.NewRandomFromReader(rng.rand)
+	if err != nil {
+		// A math/rand source never fails to read.
+		panic(err)
+	}
+	return id
+}
+
+// SortIDs orders ids so that walking over a map gives the same result on
+// every run.
+func SortIDs(ids []uuid.UUID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+}
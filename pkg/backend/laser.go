@@ -15,11 +15,23 @@ type Laser struct {
 	Direction       Direction
 	OwnerID         uuid.UUID
 	StartTime       time.Time
+	// clock is set by the game the laser is added to.
+	clock Clock
+	// speed is the laser speed of the game's rules when the laser was added.
+	speed time.Duration
 }
 
 func (laser *Laser) Position() Coordinate {
-	difference := time.Since(laser.StartTime)
-	moves := int(math.Floor(float64(difference.Milliseconds()) / float64(laserSpeed)))
+	clock := laser.clock
+	if clock == nil {
+		clock = RealClock
+	}
+	speed := laser.speed
+	if speed == 0 {
+		speed = DefaultRules().LaserSpeed
+	}
+	difference := clock.Now().Sub(laser.StartTime)
+	moves := int(math.Floor(float64(difference) / float64(speed)))
 	position := laser.InitialPosition
 
 	switch laser.Direction {
@@ -49,7 +61,8 @@ func (action LaserAction) Perform(game *Game) {
 	}
 
 	actionKey := fmt.Sprintf("%T:%s", action, entity.ID().String())
-	if !game.checkLastActionTime(actionKey, action.Created, laserThrottle) {
+	if !game.checkLastActionTime(actionKey, action.Created, game.rules.LaserThrottle) {
+		game.observer.ActionThrottled(action)
 		return
 	}
 
@@ -1,5 +1,10 @@
 package backend
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 type MapType int
 
@@ -9,6 +14,72 @@ const (
 	MapTypeSpawn
 )
 
+func ParseMap(rows []string) [][]rune {
+	gameMap := make([][]rune, 0, len(rows))
+	for _, row := range rows {
+		gameMap = append(gameMap, []rune(row))
+	}
+	return gameMap
+}
+
+func MapRows(gameMap [][]rune) []string {
+	rows := make([]string, 0, len(gameMap))
+	for _, row := range gameMap {
+		rows = append(rows, string(row))
+	}
+	return rows
+}
+
+func GetMap(name string) ([][]rune, error) {
+	gameMap, ok := Maps[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown map %q", name)
+	}
+	return gameMap, nil
+}
+
+// MapChoice is a map and the mode to play on it, e.g. an entry of a map
+// rotation.
+type MapChoice struct {
+	Map  string
+	Mode GameMode
+}
+
+// ParseMapChoice reads "map" or "map:mode", mode is used if the text has none.
+func ParseMapChoice(text string, mode GameMode) (MapChoice, error) {
+	name := text
+	if i := strings.Index(text, ":"); i >= 0 {
+		var err error
+		name = text[:i]
+		mode, err = ParseGameMode(text[i+1:])
+		if err != nil {
+			return MapChoice{}, err
+		}
+	}
+	if _, err := GetMap(name); err != nil {
+		return MapChoice{}, err
+	}
+	return MapChoice{Map: name, Mode: mode}, nil
+}
+
+func MapNames() []string {
+	names := make([]string, 0, len(Maps))
+	for name := range Maps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (game *Game) SetMap(gameMap [][]rune) {
+	game.gameMap = gameMap
+	game.spawnPointIndex = 0
+}
+
+func (game *Game) GetMap() [][]rune {
+	return game.gameMap
+}
+
 func (game *Game) GetMapDimensions() (int, int) {
 	return len(game.gameMap[0]), len(game.gameMap)
 }
@@ -79,3 +150,26 @@ var MapDefault = [][]rune{
 	{'█', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', '█'},
 	{'█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█', '█'},
 }
+
+var MapArena = ParseMap([]string{
+	"██████████████████████████████",
+	"█S                          S█",
+	"█                            █",
+	"█   ████              ████   █",
+	"█   █                    █   █",
+	"█   █       S    S       █   █",
+	"█                            █",
+	"█          ████████          █",
+	"█                            █",
+	"█   █       S    S       █   █",
+	"█   █                    █   █",
+	"█   ████              ████   █",
+	"█                            █",
+	"█S                          S█",
+	"██████████████████████████████",
+})
+
+var Maps = map[string][][]rune{
+	"default": MapDefault,
+	"arena":   MapArena,
+}
@@ -0,0 +1,67 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type GameMode int
+
+const (
+	ModeDeathmatch GameMode = iota
+	ModeTeamDeathmatch
+)
+
+const teamCount = 2
+
+func (mode GameMode) String() string {
+	switch mode {
+	case ModeTeamDeathmatch:
+		return "team deathmatch"
+	}
+	return "deathmatch"
+}
+
+func ParseGameMode(name string) (GameMode, error) {
+	switch strings.ToLower(name) {
+	case "deathmatch", "dm":
+		return ModeDeathmatch, nil
+	case "team deathmatch", "team", "tdm":
+		return ModeTeamDeathmatch, nil
+	}
+	return ModeDeathmatch, fmt.Errorf("unknown game mode %q", name)
+}
+
+// NextTeam returns the team a joining player should be placed on, which is
+// the smallest team in team modes and no team otherwise.
+func (game *Game) NextTeam() int {
+	if game.Mode != ModeTeamDeathmatch {
+		return 0
+	}
+
+	sizes := make([]int, teamCount+1)
+	for _, entity := range game.Entities {
+		player, ok := entity.(*Player)
+		if ok && player.Team > 0 && player.Team <= teamCount {
+			sizes[player.Team]++
+		}
+	}
+
+	team := 1
+	for i := 2; i <= teamCount; i++ {
+		if sizes[i] < sizes[team] {
+			team = i
+		}
+	}
+	return team
+}
+
+func (game *Game) isTeammate(playerID uuid.UUID, other *Player) bool {
+	if other.Team == 0 {
+		return false
+	}
+	player, ok := game.GetEntity(playerID).(*Player)
+	return ok && player.Team == other.Team
+}
@@ -0,0 +1,27 @@
+package backend
+
+import "time"
+
+// Observer is told what a game does, e.g. to export metrics. It is called
+// with the game lock held, so it must not block or touch the game.
+type Observer interface {
+	// ActionApplied is called for every action the game performs, including
+	// the ones that are then rejected.
+	ActionApplied(action Action)
+	// ActionThrottled is called when an action came too soon after the last
+	// one of its kind.
+	ActionThrottled(action Action)
+	// Ticked reports how long a step of the game loop took.
+	Ticked(duration time.Duration)
+}
+
+type nopObserver struct{}
+
+func (nopObserver) ActionApplied(action Action)   {}
+func (nopObserver) ActionThrottled(action Action) {}
+func (nopObserver) Ticked(duration time.Duration) {}
+
+// SetObserver has to be called before the game is started.
+func (game *Game) SetObserver(observer Observer) {
+	game.observer = observer
+}
@@ -7,6 +7,8 @@ type Player struct {
 	CurrentPosition Coordinate
 	Name            string
 	Icon            rune
+	// Team is zero for players that are not on a team.
+	Team int
 }
 
 func (p *Player) Position() Coordinate {
@@ -0,0 +1,78 @@
+package backend
+
+import (
+	"errors"
+	"time"
+)
+
+// Rules are the tuning of a game. Clients have to play by the rules of the
+// server to predict moves and lasers, so servers send them along.
+type Rules struct {
+	// RoundOverScore is the score that wins a round.
+	RoundOverScore int
+	// NewRoundWait is the break between two rounds.
+	NewRoundWait  time.Duration
+	MoveThrottle  time.Duration
+	LaserThrottle time.Duration
+	// LaserSpeed is the time a laser takes to move one tile.
+	LaserSpeed time.Duration
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		RoundOverScore: 10,
+		NewRoundWait:   10 * time.Second,
+		MoveThrottle:   100 * time.Millisecond,
+		LaserThrottle:  500 * time.Millisecond,
+		LaserSpeed:     50 * time.Millisecond,
+	}
+}
+
+func (rules Rules) Validate() error {
+	switch {
+	case rules.RoundOverScore < 1:
+		return errors.New("the round over score must be at least 1")
+	case rules.NewRoundWait < 0:
+		return errors.New("the new round wait can not be negative")
+	case rules.MoveThrottle < 0 || rules.LaserThrottle < 0:
+		return errors.New("throttles can not be negative")
+	case rules.LaserSpeed < time.Millisecond:
+		return errors.New("the laser speed must be at least 1ms")
+	}
+	return nil
+}
+
+// SetRules changes the rules right away, game.Mu must be held once the game
+// runs. Lasers that are already flying keep their speed.
+func (game *Game) SetRules(rules Rules) {
+	game.rules = rules
+}
+
+// Rules must be called with game.Mu held once the game runs.
+func (game *Game) Rules() Rules {
+	return game.rules
+}
+
+// QueueRules changes the rules when the next round starts, so a round is
+// played by the same rules from start to end.
+func (game *Game) QueueRules(rules Rules) {
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	game.nextRules = &rules
+}
+
+// QueueMap switches the map and mode when the next round starts. Maps can
+// only be queued while the game waits for the next round, it returns false
+// otherwise.
+func (game *Game) QueueMap(gameMap [][]rune, mode GameMode) bool {
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	if !game.WaitForRound {
+		return false
+	}
+	game.nextMap = gameMap
+	game.nextMode = mode
+	return true
+}
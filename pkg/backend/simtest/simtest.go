@@ -0,0 +1,408 @@
+// Package simtest runs games headless on a manual clock for tests.
+//
+// Maps are written as ASCII: '#' is a wall, 'S' a spawn point, '.' or ' '
+// an empty tile and any other letter a player named after it. A scenario
+// plays steps like "A shoot right" or "wait 60ms" and compares the game with
+// the expected map, scores and changes.
+package simtest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+)
+
+const (
+	// Tick is how far Wait advances the clock between two game loop steps,
+	// the same as the game loop does.
+	Tick = 10 * time.Millisecond
+	// changeBuffer has to hold every change of a step, the game drops
+	// changes nobody is waiting for.
+	changeBuffer = 1024
+)
+
+var directions = map[string]backend.Direction{
+	"up":    backend.DirectionUp,
+	"down":  backend.DirectionDown,
+	"left":  backend.DirectionLeft,
+	"right": backend.DirectionRight,
+}
+
+func directionName(direction backend.Direction) string {
+	for name, value := range directions {
+		if value == direction {
+			return name
+		}
+	}
+	return "stop"
+}
+
+// Sim is a game that only moves when told to.
+type Sim struct {
+	Game  *backend.Game
+	Clock *backend.ManualClock
+	// Changes are all changes the game sent so far.
+	Changes []backend.Change
+	rng     *backend.RNG
+	players map[rune]*backend.Player
+	names   map[uuid.UUID]rune
+}
+
+// New builds a game from an ASCII map. It fails if the map is not
+// rectangular or names a player twice.
+func New(rows ...string) (*Sim, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the map is empty")
+	}
+
+	sim := &Sim{
+		Clock:   backend.NewManualClock(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)),
+		rng:     backend.NewRNG(1),
+		players: make(map[rune]*backend.Player),
+		names:   make(map[uuid.UUID]rune),
+	}
+	sim.Game = backend.NewGame()
+	sim.Game.SetClock(sim.Clock)
+	sim.Game.SetRNG(backend.NewRNG(1))
+	sim.Game.ChangeChannel = make(chan backend.Change, changeBuffer)
+
+	gameMap := make([][]rune, 0, len(rows))
+	placed := make(map[rune][2]int)
+	for y, row := range rows {
+		tiles := []rune(row)
+		if len(tiles) != len([]rune(rows[0])) {
+			return nil, fmt.Errorf("row %d is %d tiles wide instead of %d", y, len(tiles), len([]rune(rows[0])))
+		}
+		for x, tile := range tiles {
+			switch {
+			case tile == '#' || tile == '█':
+				tiles[x] = '█'
+			case tile == 'S':
+			case tile == '.' || tile == ' ':
+				tiles[x] = ' '
+			case unicode.IsLetter(tile):
+				if _, ok := placed[tile]; ok {
+					return nil, fmt.Errorf("player %c is on the map twice", tile)
+				}
+				placed[tile] = [2]int{x, y}
+				tiles[x] = ' '
+			default:
+				return nil, fmt.Errorf("unknown tile %q at %d,%d", tile, x, y)
+			}
+		}
+		gameMap = append(gameMap, tiles)
+	}
+	sim.Game.SetMap(gameMap)
+
+	icons := make([]rune, 0, len(placed))
+	for icon := range placed {
+		icons = append(icons, icon)
+	}
+	sort.Slice(icons, func(i, j int) bool { return icons[i] < icons[j] })
+	for _, icon := range icons {
+		player := &backend.Player{
+			Name:            string(icon),
+			Icon:            icon,
+			IdentifierBase:  backend.IdentifierBase{UUID: sim.rng.UUID()},
+			CurrentPosition: sim.Tile(placed[icon][0], placed[icon][1]),
+		}
+		sim.Game.AddEntity(player)
+		sim.players[icon] = player
+		sim.names[player.ID()] = icon
+	}
+	return sim, nil
+}
+
+// Tile converts a column and row of the ASCII map to game coordinates.
+func (sim *Sim) Tile(x int, y int) backend.Coordinate {
+	width, height := sim.Game.GetMapDimensions()
+	return backend.Coordinate{X: x - width/2, Y: y - height/2}
+}
+
+func (sim *Sim) Player(icon rune) *backend.Player {
+	return sim.players[icon]
+}
+
+func (sim *Sim) name(id uuid.UUID) string {
+	icon, ok := sim.names[id]
+	if !ok {
+		return id.String()
+	}
+	return string(icon)
+}
+
+func (sim *Sim) player(icon rune) (*backend.Player, error) {
+	player, ok := sim.players[icon]
+	if !ok {
+		return nil, fmt.Errorf("there is no player %c", icon)
+	}
+	return player, nil
+}
+
+func (sim *Sim) Move(icon rune, direction backend.Direction) error {
+	player, err := sim.player(icon)
+	if err != nil {
+		return err
+	}
+	sim.Game.Apply(backend.MoveAction{
+		ID:        player.ID(),
+		Direction: direction,
+		Created:   sim.Clock.Now(),
+	})
+	sim.collect()
+	return nil
+}
+
+func (sim *Sim) Shoot(icon rune, direction backend.Direction) error {
+	player, err := sim.player(icon)
+	if err != nil {
+		return err
+	}
+	sim.Game.Apply(backend.LaserAction{
+		ID:        sim.rng.UUID(),
+		OwnerID:   player.ID(),
+		Direction: direction,
+		Created:   sim.Clock.Now(),
+	})
+	sim.collect()
+	return nil
+}
+
+// Wait advances the clock by d and runs the game loop every Tick.
+func (sim *Sim) Wait(d time.Duration) {
+	for waited := time.Duration(0); waited < d; waited += Tick {
+		step := Tick
+		if d-waited < step {
+			step = d - waited
+		}
+		sim.Clock.Advance(step)
+		sim.Game.Tick()
+		sim.collect()
+	}
+}
+
+func (sim *Sim) collect() {
+	for {
+		select {
+		case change := <-sim.Game.ChangeChannel:
+			sim.Changes = append(sim.Changes, change)
+		default:
+			return
+		}
+	}
+}
+
+// Do runs one step, one of "<player> move <direction>",
+// "<player> shoot <direction>" or "wait <duration>".
+func (sim *Sim) Do(step string) error {
+	fields := strings.Fields(step)
+	if len(fields) == 2 && fields[0] == "wait" {
+		d, err := time.ParseDuration(fields[1])
+		if err != nil {
+			return err
+		}
+		sim.Wait(d)
+		return nil
+	}
+
+	if len(fields) != 3 || len([]rune(fields[0])) != 1 {
+		return fmt.Errorf("can not parse step %q", step)
+	}
+	icon := []rune(fields[0])[0]
+	direction, ok := directions[fields[2]]
+	if !ok {
+		return fmt.Errorf("unknown direction in step %q", step)
+	}
+	switch fields[1] {
+	case "move":
+		return sim.Move(icon, direction)
+	case "shoot":
+		return sim.Shoot(icon, direction)
+	}
+	return fmt.Errorf("unknown action in step %q", step)
+}
+
+// Render draws the game in the format of New, with '.' for empty tiles and
+// '*' for lasers.
+func (sim *Sim) Render() []string {
+	sim.Game.Mu.RLock()
+	defer sim.Game.Mu.RUnlock()
+
+	gameMap := sim.Game.GetMap()
+	grid := make([][]rune, len(gameMap))
+	for y, row := range gameMap {
+		grid[y] = make([]rune, len(row))
+		for x, tile := range row {
+			switch tile {
+			case '█':
+				grid[y][x] = '#'
+			case 'S':
+				grid[y][x] = 'S'
+			default:
+				grid[y][x] = '.'
+			}
+		}
+	}
+
+	width, height := sim.Game.GetMapDimensions()
+	draw := func(position backend.Coordinate, icon rune) {
+		x := position.X + width/2
+		y := position.Y + height/2
+		if y >= 0 && y < len(grid) && x >= 0 && x < len(grid[y]) {
+			grid[y][x] = icon
+		}
+	}
+	for _, entity := range sim.Game.Entities {
+		if laser, ok := entity.(*backend.Laser); ok {
+			draw(laser.Position(), '*')
+		}
+	}
+	for _, entity := range sim.Game.Entities {
+		if player, ok := entity.(*backend.Player); ok {
+			draw(player.Position(), player.Icon)
+		}
+	}
+
+	rows := make([]string, 0, len(grid))
+	for _, row := range grid {
+		rows = append(rows, string(row))
+	}
+	return rows
+}
+
+// Describe writes a change the way scenarios expect it, e.g. "move A right",
+// "laser A right", "remove laser A", "respawn B by A", "round over" or
+// "round start".
+func (sim *Sim) Describe(change backend.Change) string {
+	switch change := change.(type) {
+	case backend.MoveChange:
+		return fmt.Sprintf("move %s %s", sim.name(change.Entity.ID()), directionName(change.Direction))
+	case backend.AddEntityChange:
+		if laser, ok := change.Entity.(*backend.Laser); ok {
+			return fmt.Sprintf("laser %s %s", sim.name(laser.OwnerID), directionName(laser.Direction))
+		}
+		return fmt.Sprintf("add %s", sim.name(change.Entity.ID()))
+	case backend.RemoveEntityChange:
+		if laser, ok := change.Entity.(*backend.Laser); ok {
+			return fmt.Sprintf("remove laser %s", sim.name(laser.OwnerID))
+		}
+		return fmt.Sprintf("remove %s", sim.name(change.Entity.ID()))
+	case backend.PlayerRespawnChange:
+		return fmt.Sprintf("respawn %s by %s", sim.name(change.Player.ID()), sim.name(change.KilledByID))
+	case backend.RoundOverChange:
+		return "round over"
+	case backend.RoundStartChange:
+		return "round start"
+	}
+	return fmt.Sprintf("%T", change)
+}
+
+// Scenario is one row of a table driven test.
+type Scenario struct {
+	Name  string
+	Map   []string
+	Mode  backend.GameMode
+	Teams map[rune]int
+	Score map[rune]int
+	// Rules replace the default rules if they are set.
+	Rules *backend.Rules
+	Steps []string
+	// Want is the map after the steps, nil skips the check.
+	Want []string
+	// WantScore lists the scores that have to match, other players are not
+	// checked.
+	WantScore map[rune]int
+	// WantChanges are all changes of the scenario in order, nil skips the
+	// check.
+	WantChanges []string
+	// WantWinner is the player that won the round, 0 if the round is not
+	// over.
+	WantWinner rune
+}
+
+// Run plays every scenario as a subtest of t.
+func Run(t *testing.T, scenarios []Scenario) {
+	t.Helper()
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.Name, func(t *testing.T) {
+			scenario.run(t)
+		})
+	}
+}
+
+func (scenario Scenario) run(t *testing.T) {
+	t.Helper()
+
+	sim, err := New(scenario.Map...)
+	if err != nil {
+		t.Fatalf("can not build the map: %v", err)
+	}
+	sim.Game.Mode = scenario.Mode
+	if scenario.Rules != nil {
+		sim.Game.SetRules(*scenario.Rules)
+	}
+	for icon, team := range scenario.Teams {
+		player, err := sim.player(icon)
+		if err != nil {
+			t.Fatal(err)
+		}
+		player.Team = team
+	}
+	for icon, score := range scenario.Score {
+		player, err := sim.player(icon)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sim.Game.Score[player.ID()] = score
+	}
+
+	for _, step := range scenario.Steps {
+		if err := sim.Do(step); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if scenario.Want != nil {
+		got := sim.Render()
+		if strings.Join(got, "\n") != strings.Join(scenario.Want, "\n") {
+			t.Errorf("map after the steps\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(scenario.Want, "\n"))
+		}
+	}
+
+	for icon, want := range scenario.WantScore {
+		player, err := sim.player(icon)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sim.Game.Score[player.ID()]; got != want {
+			t.Errorf("score of %c is %d, want %d", icon, got, want)
+		}
+	}
+
+	if scenario.WantChanges != nil {
+		got := make([]string, 0, len(sim.Changes))
+		for _, change := range sim.Changes {
+			got = append(got, sim.Describe(change))
+		}
+		if strings.Join(got, "\n") != strings.Join(scenario.WantChanges, "\n") {
+			t.Errorf("changes\n  %s\nwant\n  %s", strings.Join(got, "\n  "), strings.Join(scenario.WantChanges, "\n  "))
+		}
+	}
+
+	gotWinner := rune(0)
+	if sim.Game.WaitForRound {
+		gotWinner = []rune(sim.name(sim.Game.RoundWinner))[0]
+	}
+	if gotWinner != scenario.WantWinner {
+		t.Errorf("round winner is %q, want %q", gotWinner, scenario.WantWinner)
+	}
+}
@@ -0,0 +1,46 @@
+package simtest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		rows []string
+		err  string
+	}{
+		{name: "empty", err: "empty"},
+		{name: "ragged", rows: []string{"###", "#A"}, err: "wide"},
+		{name: "twice", rows: []string{"#AA#"}, err: "twice"},
+		{name: "unknown tile", rows: []string{"#A?#"}, err: "unknown tile"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(test.rows...)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("New() error = %v, want %q", err, test.err)
+			}
+		})
+	}
+
+	sim, err := New("#S.A#")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(sim.Render(), "\n"), "#S.A#"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestDo(t *testing.T) {
+	sim, err := New("#A.#")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, step := range []string{"A jump right", "A move sideways", "B move right", "wait soon", "A"} {
+		if err := sim.Do(step); err == nil {
+			t.Errorf("Do(%q) succeeded", step)
+		}
+	}
+}
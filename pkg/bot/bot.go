@@ -1,12 +1,13 @@
 package bot
 
 import (
-	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/beefsack/go-astar"
 	"github.com/google/uuid"
 	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
 )
 
 
@@ -53,32 +54,51 @@ func (t *tile) PathEstimatedCost(to astar.Pather) float64 {
 	return float64(t.position.Distance(toT.position))
 }
 
+const thinkFrequency = 200 * time.Millisecond
+
 type bot struct {
 	playerID uuid.UUID
 }
 
+// Bots play with the clock and RNG of their game, so a seeded game with bots
+// plays out the same every time. Bots can be added and removed while they
+// play, mu guards the bots and their world.
 type Bots struct {
-	bots []*bot
-	game *backend.Game
+	mu       sync.Mutex
+	bots     []*bot
+	game     *backend.Game
+	world    *world
+	clock    backend.Clock
+	rng      *backend.RNG
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 func NewBots(game *backend.Game) *Bots {
 	return &Bots{
-		game: game,
-		bots: make([]*bot, 0),
+		game:  game,
+		bots:  make([]*bot, 0),
+		clock: game.Clock(),
+		rng:   game.RNG(),
+		stop:  make(chan struct{}),
 	}
 }
 
 func (bots *Bots) AddBot(name string) *backend.Player {
-	playerID := uuid.New()
+	playerID := bots.rng.UUID()
+
+	bots.mu.Lock()
+	defer bots.mu.Unlock()
+
+	bots.game.Mu.Lock()
+	spawnPoints := bots.game.GetMapByType()[backend.MapTypeSpawn]
 	player := &backend.Player{
 		Name:            name,
 		Icon:            'b',
-		IdentifierBase:  backend.IdentifierBase{playerID},
-		CurrentPosition: backend.Coordinate{X: -1, Y: 9},
+		IdentifierBase:  backend.IdentifierBase{UUID: playerID},
+		CurrentPosition: spawnPoints[len(bots.bots)%len(spawnPoints)],
+		Team:            bots.game.NextTeam(),
 	}
-
-	bots.game.Mu.Lock()
 	bots.game.AddEntity(player)
 	bots.game.Mu.Unlock()
 
@@ -87,6 +107,64 @@ func (bots *Bots) AddBot(name string) *backend.Player {
 	return player
 }
 
+// RemoveBot stops the bot from playing and removes its player from the game.
+// It returns false if playerID is not one of the bots.
+func (bots *Bots) RemoveBot(playerID uuid.UUID) bool {
+	bots.mu.Lock()
+	defer bots.mu.Unlock()
+
+	for i, bot := range bots.bots {
+		if bot.playerID != playerID {
+			continue
+		}
+		bots.bots = append(bots.bots[:i], bots.bots[i+1:]...)
+
+		bots.game.Mu.Lock()
+		bots.game.RemoveEntity(playerID)
+		bots.game.Mu.Unlock()
+		return true
+	}
+	return false
+}
+
+func (bots *Bots) IsBot(playerID uuid.UUID) bool {
+	bots.mu.Lock()
+	defer bots.mu.Unlock()
+
+	for _, bot := range bots.bots {
+		if bot.playerID == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+func (bots *Bots) PlayerIDs() []uuid.UUID {
+	bots.mu.Lock()
+	defer bots.mu.Unlock()
+
+	ids := make([]uuid.UUID, 0, len(bots.bots))
+	for _, bot := range bots.bots {
+		ids = append(ids, bot.playerID)
+	}
+	return ids
+}
+
+func (bots *Bots) Count() int {
+	bots.mu.Lock()
+	defer bots.mu.Unlock()
+
+	return len(bots.bots)
+}
+
+// MapChanged makes the bots find their way on the new map of the game.
+func (bots *Bots) MapChanged() {
+	bots.mu.Lock()
+	defer bots.mu.Unlock()
+
+	bots.world = nil
+}
+
 func getShootDirection(world *world, c1 backend.Coordinate, c2 backend.Coordinate) backend.Direction {
 	direction := backend.DirectionStop
 	diffCoordinate := backend.Coordinate{
@@ -131,146 +209,194 @@ func getShootDirection(world *world, c1 backend.Coordinate, c2 backend.Coordinat
 	return direction
 }
 
+func (bots *Bots) Stop() {
+	bots.stopOnce.Do(func() {
+		close(bots.stop)
+	})
+}
+
 func (bots *Bots) Start() {
 	go func() {
-		world := &world{
-			tiles: make(map[backend.Coordinate]*tile),
+		defer func() {
+			if r := recover(); r != nil {
+				logging.New().Error("bots stopped after panic", "panic", r)
+			}
+		}()
+
+		for {
+			for _, action := range bots.Decide() {
+				bots.game.SendAction(action)
+			}
+
+			select {
+			case <-bots.stop:
+				return
+			case <-bots.clock.After(thinkFrequency):
+			}
 		}
+	}()
+}
 
-		for symbol, positions := range bots.game.GetMapByType() {
-			for _, position := range positions {
-				if symbol == backend.MapTypeWall {
-					world.tiles[position] = &tile{
-						position: position,
-						world:    world,
-						kind:     tileWall,
-					}
-				} else {
-					world.tiles[position] = &tile{
-						position: position,
-						world:    world,
-						kind:     tileNone,
-					}
+func (bots *Bots) buildWorld() {
+	world := &world{
+		tiles: make(map[backend.Coordinate]*tile),
+	}
+
+	bots.game.Mu.RLock()
+	mapByType := bots.game.GetMapByType()
+	bots.game.Mu.RUnlock()
+
+	for symbol, positions := range mapByType {
+		for _, position := range positions {
+			if symbol == backend.MapTypeWall {
+				world.tiles[position] = &tile{
+					position: position,
+					world:    world,
+					kind:     tileWall,
+				}
+			} else {
+				world.tiles[position] = &tile{
+					position: position,
+					world:    world,
+					kind:     tileNone,
 				}
 			}
 		}
-		for {
-			bots.game.Mu.RLock()
-			playerPositions := make(map[uuid.UUID]backend.Coordinate, 0)
-			for _, entity := range bots.game.Entities {
-				switch entity.(type) {
-				case *backend.Player:
-					player := entity.(*backend.Player)
-					playerPositions[entity.ID()] = player.Position()
-				}
+	}
+	bots.world = world
+}
+
+// Decide returns what every bot does next. Start sends the actions to the
+// game, a simulation can apply them itself.
+func (bots *Bots) Decide() []backend.Action {
+	bots.mu.Lock()
+	defer bots.mu.Unlock()
+
+	if bots.world == nil {
+		bots.buildWorld()
+	}
+	world := bots.world
+	actions := make([]backend.Action, 0, len(bots.bots))
+
+	bots.game.Mu.RLock()
+	playerPositions := make(map[uuid.UUID]backend.Coordinate, 0)
+	playerIDs := make([]uuid.UUID, 0)
+	for _, entity := range bots.game.Entities {
+		switch entity.(type) {
+		case *backend.Player:
+			player := entity.(*backend.Player)
+			playerPositions[entity.ID()] = player.Position()
+			playerIDs = append(playerIDs, entity.ID())
+		}
+	}
+	bots.game.Mu.RUnlock()
+	backend.SortIDs(playerIDs)
+
+	for _, bot := range bots.bots {
+		bots.game.Mu.RLock()
+		player, ok := bots.game.GetEntity(bot.playerID).(*backend.Player)
+		bots.game.Mu.RUnlock()
+		if !ok {
+			continue
+		}
+
+		playerPosition := playerPositions[player.ID()]
+		closestPosition := backend.Coordinate{}
+		shootDirection := backend.DirectionStop
+
+		shoot := false
+		move := false
+
+		for _, id := range playerIDs {
+			position := playerPositions[id]
+			if id == player.ID() {
+				continue
 			}
 
-			bots.game.Mu.RUnlock()
-			for _, bot := range bots.bots {
-				bots.game.Mu.RLock()
-				player := bots.game.GetEntity(bot.playerID).(*backend.Player)
-				bots.game.Mu.RUnlock()
-
-				playerPosition := player.Position()
-				closestPosition := backend.Coordinate{}
-				shootDirection := backend.DirectionStop
-
-				shoot := false
-				move := false
-
-				for id, position := range playerPositions {
-					if id == player.ID() {
-						continue
-					}
-
-					if position == playerPosition {
-						closestPosition = position.Add(backend.Coordinate{
-							X: 1,
-							Y: 1,
-						})
-						break
-					}
-
-					shootDirection = getShootDirection(world, playerPosition, position)
-					if shootDirection != backend.DirectionStop {
-						shoot = true
-						break
-					}
-
-					if !move || (position.Distance(playerPosition) < closestPosition.Distance(playerPosition)) {
-						closestPosition = position
-						move = true
-					}
-				}
+			if position == playerPosition {
+				closestPosition = position.Add(backend.Coordinate{
+					X: 1,
+					Y: 1,
+				})
+				break
+			}
 
-				rand.Seed(time.Now().UnixNano())
-				if move && rand.Intn(100) > 60 {
-					closestPosition = closestPosition.Add(backend.Coordinate{
-						X: rand.Intn(2) - 1,
-						Y: rand.Intn(2) - 1,
-					})
-					shoot = false
-				}
+			shootDirection = getShootDirection(world, playerPosition, position)
+			if shootDirection != backend.DirectionStop {
+				shoot = true
+				break
+			}
 
-				if shoot {
-					bots.game.ActionChannel <- backend.LaserAction{
-						ID: uuid.New(),
-						OwnerID: player.ID(),
-						Direction: shootDirection,
-						Created:   time.Now(),
-					}
-					continue
-				}
+			if !move || (position.Distance(playerPosition) < closestPosition.Distance(playerPosition)) {
+				closestPosition = position
+				move = true
+			}
+		}
 
-				if !move {
-					continue
-				}
+		if move && bots.rng.Intn(100) > 60 {
+			closestPosition = closestPosition.Add(backend.Coordinate{
+				X: bots.rng.Intn(2) - 1,
+				Y: bots.rng.Intn(2) - 1,
+			})
+			shoot = false
+		}
 
-				fromTile, ok := world.tiles[playerPosition]
-				if !ok {
-					continue
-				}
-				toTile, ok := world.tiles[closestPosition]
-				if !ok {
-					continue
-				}
+		if shoot {
+			actions = append(actions, backend.LaserAction{
+				ID:        bots.rng.UUID(),
+				OwnerID:   player.ID(),
+				Direction: shootDirection,
+				Created:   bots.clock.Now(),
+			})
+			continue
+		}
 
-				path, _, found := astar.Path(toTile, fromTile)
-				if !found {
-					continue
-				}
+		if !move {
+			continue
+		}
 
-				var moveTowards backend.Coordinate
-				if len(path) > 1 {
-					moveTowards = path[1].(*tile).position
-				} else {
-					moveTowards = path[0].(*tile).position
-				}
+		fromTile, ok := world.tiles[playerPosition]
+		if !ok {
+			continue
+		}
+		toTile, ok := world.tiles[closestPosition]
+		if !ok {
+			continue
+		}
 
-				xDiff := moveTowards.X - playerPosition.X
-				yDiff := moveTowards.Y - playerPosition.Y
-				direction := backend.DirectionStop
-				if xDiff < 0 {
-					direction = backend.DirectionLeft
-				} else if xDiff > 0 {
-					direction = backend.DirectionRight
-				} else if yDiff < 0 {
-					direction = backend.DirectionUp
-				} else if yDiff > 0 {
-					direction = backend.DirectionDown
-				}
-				if direction == backend.DirectionStop {
-					continue
-				}
+		path, _, found := astar.Path(toTile, fromTile)
+		if !found {
+			continue
+		}
 
-				bots.game.ActionChannel <- backend.MoveAction{
-					ID:        player.ID(),
-					Direction: direction,
-					Created:   time.Now(),
-				}
-			}
+		var moveTowards backend.Coordinate
+		if len(path) > 1 {
+			moveTowards = path[1].(*tile).position
+		} else {
+			moveTowards = path[0].(*tile).position
+		}
 
-			time.Sleep(time.Millisecond * 200)
+		xDiff := moveTowards.X - playerPosition.X
+		yDiff := moveTowards.Y - playerPosition.Y
+		direction := backend.DirectionStop
+		if xDiff < 0 {
+			direction = backend.DirectionLeft
+		} else if xDiff > 0 {
+			direction = backend.DirectionRight
+		} else if yDiff < 0 {
+			direction = backend.DirectionUp
+		} else if yDiff > 0 {
+			direction = backend.DirectionDown
 		}
-	}()
+		if direction == backend.DirectionStop {
+			continue
+		}
+
+		actions = append(actions, backend.MoveAction{
+			ID:        player.ID(),
+			Direction: direction,
+			Created:   bots.clock.Now(),
+		})
+	}
+	return actions
 }
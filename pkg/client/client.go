@@ -3,19 +3,26 @@ package client
 import (
 	"context"
 	"fmt"
-	"log"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/nikit34/multiplayer_rpg/pkg/backend"
 	"github.com/nikit34/multiplayer_rpg/pkg/frontend"
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
 	proto "github.com/nikit34/multiplayer_rpg/proto"
 )
 
 
 const (
 	positionHistoryLimit = 5
+	// responseLogSampleRate is how many responses share one debug record.
+	responseLogSampleRate = 100
 )
 
 type GameClient struct {
@@ -23,22 +30,108 @@ type GameClient struct {
 	Stream        proto.Game_StreamClient
 	Game          *backend.Game
 	View          *frontend.View
+	// positionHistory is written by the change goroutine and read by the
+	// receive goroutine, historyMu guards it.
 	positionHistory []backend.Coordinate
+	historyMu       sync.Mutex
+	grpcClient      proto.GameClient
+	// token is replaced by RefreshToken while other goroutines make calls,
+	// tokenMu guards it.
+	token          string
+	tokenExpiresAt time.Time
+	tokenMu        sync.Mutex
+	logger         *logging.Logger
+	responseLogger *logging.Logger
+	exitReason     string
+	exitMu         sync.Mutex
+	// sendMu serializes the sends of the goroutines that share Stream, gRPC
+	// does not allow concurrent sends on one stream.
+	sendMu sync.Mutex
 }
 
 func NewGameClient(game *backend.Game, view *frontend.View) *GameClient {
 	return &GameClient{
-		Game:   game,
-		View:   view,
+		Game:            game,
+		View:            view,
 		positionHistory: make([]backend.Coordinate, positionHistoryLimit),
+		logger:          logging.New(),
+		responseLogger:  logging.New(),
 	}
 }
 
-func (c *GameClient) Connect(grpcClient proto.GameClient, playerID uuid.UUID, playerName string, password string) error {
+func (c *GameClient) Register(grpcClient proto.GameClient, playerName string, accountPassword string, password string) error {
+	req := proto.RegisterRequest{
+		Name:           playerName,
+		Password:       accountPassword,
+		ServerPassword: password,
+	}
+
+	_, err := grpcClient.Register(context.Background(), &req)
+	return err
+}
+
+// ConnectOptions describe who is connecting and to which room, an empty
+// RoomID joins the server's default room.
+type ConnectOptions struct {
+	PlayerID        uuid.UUID
+	PlayerName      string
+	Password        string
+	AccountPassword string
+	RoomID          string
+	RoomPassword    string
+	// Spectator joins the room without a player entity.
+	Spectator bool
+}
+
+func (c *GameClient) ListRooms(grpcClient proto.GameClient) (*proto.ListRoomsResponse, error) {
+	return grpcClient.ListRooms(context.Background(), &proto.ListRoomsRequest{})
+}
+
+func (c *GameClient) CreateRoom(grpcClient proto.GameClient, req *proto.CreateRoomRequest) (*proto.RoomInfo, error) {
+	resp, err := grpcClient.CreateRoom(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Room, nil
+}
+
+// Matchmake waits in the matchmaking queue, passing every queue status to
+// onStatus, and returns the ID of the room the player was matched into.
+func (c *GameClient) Matchmake(ctx context.Context, grpcClient proto.GameClient, options ConnectOptions, mode backend.GameMode, onStatus func(*proto.QueueStatus)) (string, error) {
+	req := proto.MatchmakeRequest{
+		Id:              options.PlayerID.String(),
+		Name:            options.PlayerName,
+		Password:        options.Password,
+		AccountPassword: options.AccountPassword,
+		Mode:            proto.GetProtoGameMode(mode),
+	}
+
+	stream, err := grpcClient.Matchmake(ctx, &req)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		status, err := stream.Recv()
+		if err != nil {
+			return "", err
+		}
+		onStatus(status)
+		if status.State == proto.QueueState_MATCHED {
+			return status.RoomId, nil
+		}
+	}
+}
+
+func (c *GameClient) Connect(grpcClient proto.GameClient, options ConnectOptions) error {
 	req := proto.ConnectRequest{
-		Id:   playerID.String(),
-		Name: playerName,
-		Password: password,
+		Id:              options.PlayerID.String(),
+		Name:            options.PlayerName,
+		Password:        options.Password,
+		AccountPassword: options.AccountPassword,
+		RoomId:          options.RoomID,
+		RoomPassword:    options.RoomPassword,
+		Spectator:       options.Spectator,
 	}
 
 	resp, err := grpcClient.Connect(context.Background(), &req)
@@ -46,29 +139,95 @@ func (c *GameClient) Connect(grpcClient proto.GameClient, playerID uuid.UUID, pl
 		return err
 	}
 
+	playerID := options.PlayerID
+	if resp.PlayerId != "" {
+		playerID, err = uuid.Parse(resp.PlayerId)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.Game.Mu.Lock()
+	if len(resp.Map) > 0 {
+		c.Game.SetMap(backend.ParseMap(resp.Map))
+	}
+	c.Game.Mode = proto.GetBackendGameMode(resp.Mode)
+	c.Game.SetRules(proto.GetBackendRules(resp.Rules))
+
 	for _, entity := range resp.Entities {
 		backendEntity := proto.GetBackendEntity(entity)
 		if backendEntity == nil {
+			c.Game.Mu.Unlock()
 			return fmt.Errorf("can not get backend entity from %+v", entity)
 		}
 
 		c.Game.AddEntity(backendEntity)
 	}
+	c.Game.Mu.Unlock()
+
+	c.grpcClient = grpcClient
+	c.setToken(resp.Token, resp.TokenExpiresAt.AsTime())
 
-	header := metadata.New(map[string]string{"authorization": resp.Token})
-	ctx := metadata.NewOutgoingContext(context.Background(), header)
-	stream, err := grpcClient.Stream(ctx)
+	stream, err := grpcClient.Stream(c.authContext())
 	if err != nil {
 		return err
 	}
 
 	c.CurrentPlayer = playerID
+	c.logger = logging.New("player", playerID, "room", resp.RoomId)
+	c.responseLogger = c.logger.Sample(responseLogSampleRate)
 	c.View.CurrentPlayer = playerID
+	c.View.SetSpectator(options.Spectator)
 	c.Stream = stream
 
 	return nil
 }
 
+func (c *GameClient) setToken(token string, expiresAt time.Time) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	c.token = token
+	c.tokenExpiresAt = expiresAt
+}
+
+// TokenExpiresAt is when the current token stops being accepted.
+func (c *GameClient) TokenExpiresAt() time.Time {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	return c.tokenExpiresAt
+}
+
+func (c *GameClient) authContext() context.Context {
+	c.tokenMu.Lock()
+	header := metadata.New(map[string]string{"authorization": c.token})
+	c.tokenMu.Unlock()
+
+	return metadata.NewOutgoingContext(context.Background(), header)
+}
+
+// RefreshToken replaces the token with a new one for the calls that follow,
+// the old one is revoked. The open stream is not affected, the server checks
+// the token only when a stream is opened.
+func (c *GameClient) RefreshToken() error {
+	resp, err := c.grpcClient.RefreshToken(c.authContext(), &proto.RefreshTokenRequest{})
+	if err != nil {
+		return err
+	}
+
+	c.setToken(resp.Token, resp.TokenExpiresAt.AsTime())
+	return nil
+}
+
+// send sends a request on the stream, it is safe to call from any goroutine.
+func (c *GameClient) send(req *proto.Request) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	return c.Stream.Send(req)
+}
+
 func (c *GameClient) handleMoveChange(change backend.MoveChange) {
 	req := proto.Request{
 		Action: &proto.Request_Move{
@@ -77,8 +236,25 @@ func (c *GameClient) handleMoveChange(change backend.MoveChange) {
 			},
 		},
 	}
-	c.Stream.Send(&req)
+	c.send(&req)
+
+	c.historyMu.Lock()
 	c.positionHistory = append([]backend.Coordinate{change.Position}, c.positionHistory[:positionHistoryLimit]...)
+	c.historyMu.Unlock()
+}
+
+// predictedPosition tells if the player moved to position recently, an
+// update to it confirms the prediction.
+func (c *GameClient) predictedPosition(position backend.Coordinate) bool {
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	for _, predicted := range c.positionHistory {
+		if predicted == position {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *GameClient) handleAddEntityChange(change backend.AddEntityChange) {
@@ -89,7 +265,7 @@ func (c *GameClient) handleAddEntityChange(change backend.AddEntityChange) {
 				Laser: proto.GetProtoLaser(laser),
 			},
 		}
-		c.Stream.Send(&req)
+		c.send(&req)
 	}
 }
 
@@ -117,12 +293,8 @@ func (c *GameClient) handleUpdateEntityResponse(resp *proto.Response) {
 	}
 
 	player, ok := entity.(*backend.Player)
-	if ok && player.ID() == c.CurrentPlayer {
-		for _, position := range c.positionHistory {
-			if player.Position() == position {
-				return
-			}
-		}
+	if ok && player.ID() == c.CurrentPlayer && c.predictedPosition(player.Position()) {
+		return
 	}
 	c.Game.UpdateEntity(entity)
 }
@@ -154,6 +326,35 @@ func (c *GameClient) handlePlayerRespawnResponse(resp *proto.Response) {
 
 	c.Game.AddScore(killedByID)
 	c.Game.UpdateEntity(player)
+
+	killer, ok := c.Game.GetEntity(killedByID).(*backend.Player)
+	if ok {
+		c.View.AddKill(killer, player)
+	}
+}
+
+func (c *GameClient) handlePlayerJoinedResponse(resp *proto.Response) {
+	joined := resp.GetPlayerJoined()
+	player := proto.GetBackendPlayer(joined.Player)
+	if player == nil {
+		c.Exit(fmt.Sprintf("can not get backend player from %+v", joined.Player))
+		return
+	}
+
+	c.Game.AddEntity(player)
+	c.View.AddNotification(fmt.Sprintf("%s joined the game", player.Name))
+}
+
+func (c *GameClient) handlePlayerLeftResponse(resp *proto.Response) {
+	left := resp.GetPlayerLeft()
+	id, err := uuid.Parse(left.Id)
+	if err != nil {
+		c.Exit(fmt.Sprintf("error when parsing UUID: %v", err))
+		return
+	}
+
+	c.Game.RemoveEntity(id)
+	c.View.AddNotification(fmt.Sprintf("%s left the game", left.Name))
 }
 
 func (c *GameClient) handleRoundOverResponse(resp *proto.Response) {
@@ -168,12 +369,33 @@ func (c *GameClient) handleRoundOverResponse(resp *proto.Response) {
 	c.Game.NewRoundAt = respawn.NewRoundAt.AsTime()
 	c.Game.WaitForRound = true
 	c.Game.Score = make(map[uuid.UUID]int)
+
+	winner, ok := c.Game.GetEntity(roundWinner).(*backend.Player)
+	if ok {
+		c.View.AddNotification(fmt.Sprintf("%s won the round", winner.Name))
+	}
+	c.View.ResetStreaks()
 }
 
 func (c *GameClient) handleRoundStartResponse(resp *proto.Response) {
 	roundStart := resp.GetRoundStart()
 	c.Game.WaitForRound = false
 
+	// A round can start on another map, the lasers of the old one are gone.
+	if len(roundStart.Map) > 0 {
+		for id, entity := range c.Game.Entities {
+			if _, ok := entity.(*backend.Laser); ok {
+				c.Game.RemoveEntity(id)
+			}
+		}
+		c.Game.SetMap(backend.ParseMap(roundStart.Map))
+		c.Game.Mode = proto.GetBackendGameMode(roundStart.Mode)
+	}
+	// Servers can change the rules between rounds.
+	if roundStart.Rules != nil {
+		c.Game.SetRules(proto.GetBackendRules(roundStart.Rules))
+	}
+
 	for _, protoPlayer := range roundStart.Players {
 		player := proto.GetBackendPlayer(protoPlayer)
 		if player == nil {
@@ -182,14 +404,164 @@ func (c *GameClient) handleRoundStartResponse(resp *proto.Response) {
 		}
 		c.Game.AddEntity(player)
 	}
+	c.View.ClearMapVote()
+	c.View.AddNotification("A new round has started")
+}
+
+// handleMapVoteResponse shows the maps players vote between while a round is
+// over and the votes so far.
+func (c *GameClient) handleMapVoteResponse(resp *proto.Response) {
+	mapVote := resp.GetMapVote()
+	candidates := make([]frontend.MapCandidate, 0, len(mapVote.Candidates))
+	for _, candidate := range mapVote.Candidates {
+		candidates = append(candidates, frontend.MapCandidate{
+			Map:   candidate.Map,
+			Mode:  proto.GetBackendGameMode(candidate.Mode),
+			Votes: int(candidate.Votes),
+		})
+	}
+	c.View.SetMapVote(candidates)
+}
+
+// handlePingResponse sends the ping back, so the server knows the latency.
+func (c *GameClient) handlePingResponse(resp *proto.Response) {
+	req := proto.Request{
+		Action: &proto.Request_Pong{
+			Pong: &proto.Pong{
+				PingSentAt: resp.GetPing().SentAt,
+			},
+		},
+	}
+	c.send(&req)
+}
+
+// handleLaserAckResponse replaces the predicted laser with the one the server
+// spawned. The prediction keeps its position and start time when it started
+// where the server's laser did, so the laser does not jump.
+func (c *GameClient) handleLaserAckResponse(resp *proto.Response) {
+	ack := resp.GetLaserAck()
+	clientID, err := uuid.Parse(ack.ClientId)
+	if err != nil {
+		c.Exit(fmt.Sprintf("error when parsing UUID: %v", err))
+		return
+	}
+
+	predicted, ok := c.Game.GetEntity(clientID).(*backend.Laser)
+	if ack.Laser == nil {
+		c.Game.RemoveEntity(clientID)
+		c.View.AddNotification(ack.RejectedReason)
+		return
+	}
+	if !ok {
+		// The predicted laser already hit a wall.
+		return
+	}
+
+	laser := proto.GetBackendLaser(ack.Laser)
+	if laser == nil {
+		c.Exit(fmt.Sprintf("can not get backend laser from %+v", ack.Laser))
+		return
+	}
+	if laser.InitialPosition == predicted.InitialPosition {
+		laser.StartTime = predicted.StartTime
+	}
+	c.Game.RemoveEntity(clientID)
+	c.Game.AddEntity(laser)
+}
+
+func (c *GameClient) handleChatMessageResponse(resp *proto.Response) {
+	message := resp.GetChatMessage()
+	c.View.AddChatMessage(frontend.ChatMessage{
+		Channel:   getFrontendChatChannel(message.Channel),
+		Sender:    message.SenderName,
+		Recipient: message.RecipientName,
+		Text:      message.Text,
+		Time:      message.SentAt.AsTime().Local(),
+	})
+}
+
+func (c *GameClient) sendChatMessage(message frontend.ChatMessage) {
+	req := proto.Request{
+		Action: &proto.Request_Chat{
+			Chat: &proto.Chat{
+				Channel:   getProtoChatChannel(message.Channel),
+				Text:      message.Text,
+				Recipient: message.Recipient,
+			},
+		},
+	}
+	c.send(&req)
+}
+
+func (c *GameClient) sendVote(choice int) {
+	req := proto.Request{
+		Action: &proto.Request_Vote{
+			Vote: &proto.Vote{
+				Choice: int32(choice),
+			},
+		},
+	}
+	c.send(&req)
+}
+
+func getFrontendChatChannel(channel proto.ChatChannel) frontend.ChatChannel {
+	switch channel {
+	case proto.ChatChannel_TEAM:
+		return frontend.ChatTeam
+	case proto.ChatChannel_WHISPER:
+		return frontend.ChatWhisper
+	case proto.ChatChannel_SYSTEM:
+		return frontend.ChatSystem
+	}
+	return frontend.ChatGlobal
+}
+
+func getProtoChatChannel(channel frontend.ChatChannel) proto.ChatChannel {
+	switch channel {
+	case frontend.ChatTeam:
+		return proto.ChatChannel_TEAM
+	case frontend.ChatWhisper:
+		return proto.ChatChannel_WHISPER
+	}
+	return proto.ChatChannel_GLOBAL
+}
+
+// messageType names the action of a response for logs, e.g. "UpdateEntity".
+func messageType(action interface{}) string {
+	name := fmt.Sprintf("%T", action)
+	return name[strings.LastIndex(name, "_")+1:]
 }
 
 func (c *GameClient) Exit(message string) {
+	c.exitMu.Lock()
+	c.exitReason = message
+	c.exitMu.Unlock()
+
 	c.View.App.Stop()
-	log.Println(message)
+	c.logger.Error("exiting", "reason", message)
+}
+
+// ExitReason is why the client stopped the view, empty if the player quit.
+func (c *GameClient) ExitReason() string {
+	c.exitMu.Lock()
+	defer c.exitMu.Unlock()
+
+	return c.exitReason
 }
 
 func (c *GameClient) Start() {
+	go func() {
+		for message := range c.View.ChatInput {
+			c.sendChatMessage(message)
+		}
+	}()
+
+	go func() {
+		for choice := range c.View.VoteInput {
+			c.sendVote(choice)
+		}
+	}()
+
 	go func() {
 		for {
 			change := <-c.Game.ChangeChannel
@@ -205,12 +577,23 @@ func (c *GameClient) Start() {
 	go func() {
 		for {
 			resp, err := c.Stream.Recv()
-			log.Printf("Recv %+v", resp)
-
+			if status.Code(err) == codes.Unavailable {
+				// The server tells why, e.g. that it is shutting down.
+				c.Exit(status.Convert(err).Message())
+				return
+			}
 			if err != nil {
 				c.Exit(fmt.Sprintf("can not receive, error: %v", err))
 				return
 			}
+			c.responseLogger.Debug("response", "type", messageType(resp.GetAction()))
+
+			// The pong needs no game state, it is sent without the lock so
+			// a slow send does not hold up the game.
+			if resp.GetPing() != nil {
+				c.handlePingResponse(resp)
+				continue
+			}
 
 			c.Game.Mu.Lock()
 			switch resp.GetAction().(type) {
@@ -226,6 +609,16 @@ func (c *GameClient) Start() {
 				c.handleRoundOverResponse(resp)
 			case *proto.Response_RoundStart:
 				c.handleRoundStartResponse(resp)
+			case *proto.Response_ChatMessage:
+				c.handleChatMessageResponse(resp)
+			case *proto.Response_PlayerJoined:
+				c.handlePlayerJoinedResponse(resp)
+			case *proto.Response_PlayerLeft:
+				c.handlePlayerLeftResponse(resp)
+			case *proto.Response_LaserAck:
+				c.handleLaserAckResponse(resp)
+			case *proto.Response_MapVote:
+				c.handleMapVoteResponse(resp)
 			}
 			c.Game.Mu.Unlock()
 		}
@@ -0,0 +1,386 @@
+// Package config reads the server configuration file. Every setting has a
+// command line flag of the same meaning, flags given on the command line win
+// over the file so a setting can be tried out without editing it.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	"github.com/nikit34/multiplayer_rpg/pkg/discovery"
+)
+
+type Config struct {
+	Network Network `toml:"network"`
+	Auth    Auth    `toml:"auth"`
+	Game    Game    `toml:"game"`
+	Bots    Bots    `toml:"bots"`
+	Maps    Maps    `toml:"maps"`
+
+	Discovery Discovery `toml:"discovery"`
+}
+
+type Network struct {
+	Port                 int           `toml:"port"`
+	MetricsAddr          string        `toml:"metrics_addr"`
+	TLSCert              string        `toml:"tls_cert"`
+	TLSKey               string        `toml:"tls_key"`
+	TLSClientCA          string        `toml:"tls_client_ca"`
+	MaxConnectionsPerIP  int           `toml:"max_connections_per_ip"`
+	MaxConnectsPerMinute int           `toml:"max_connects_per_minute"`
+	ShutdownGrace        time.Duration `toml:"shutdown_grace"`
+	// Reflection serves the gRPC reflection service for generic tools.
+	Reflection bool `toml:"reflection"`
+}
+
+type Auth struct {
+	Password      string        `toml:"password"`
+	Accounts      string        `toml:"accounts"`
+	TokenSecret   string        `toml:"token_secret"`
+	ServerID      string        `toml:"server_id"`
+	TokenLifetime time.Duration `toml:"token_lifetime"`
+	AdminToken    string        `toml:"admin_token"`
+	Bans          string        `toml:"bans"`
+	Revocations   string        `toml:"revocations"`
+}
+
+type Game struct {
+	MaxRooms       int           `toml:"max_rooms"`
+	MaxPlayers     int           `toml:"max_players"`
+	ClientTimeout  time.Duration `toml:"client_timeout"`
+	RoundOverScore int           `toml:"round_over_score"`
+	NewRoundWait   time.Duration `toml:"new_round_wait"`
+	MoveThrottle   time.Duration `toml:"move_throttle"`
+	LaserThrottle  time.Duration `toml:"laser_throttle"`
+	LaserSpeed     time.Duration `toml:"laser_speed"`
+	Seed           int64         `toml:"seed"`
+	ReplayDir      string        `toml:"replay_dir"`
+	Ratings        string        `toml:"ratings"`
+	ChatFilter     string        `toml:"chat_filter"`
+}
+
+type Bots struct {
+	// Count is the number of bots in the default room.
+	Count int `toml:"count"`
+}
+
+type Maps struct {
+	Map  string `toml:"map"`
+	Mode string `toml:"mode"`
+	// Rotation is the list of maps the default room cycles through, one
+	// per round, as "map" or "map:mode". Players vote between the next
+	// ones while a round is over. It is off if empty.
+	Rotation []string `toml:"rotation"`
+}
+
+// Discovery announces the server to the clients on the local network.
+type Discovery struct {
+	Enabled bool `toml:"enabled"`
+	// Address is where the announcements go, the broadcast address of the
+	// local network by default.
+	Address  string        `toml:"address"`
+	Interval time.Duration `toml:"interval"`
+	// Name is the server name clients list, the hostname if empty.
+	Name string `toml:"name"`
+}
+
+// MaxPlayersLimit is the most players a room can have, the spawn points of the
+// maps are made for it.
+const MaxPlayersLimit = 8
+
+func Default() *Config {
+	rules := backend.DefaultRules()
+	return &Config{
+		Network: Network{
+			Port:          8888,
+			ShutdownGrace: 10 * time.Second,
+		},
+		Auth: Auth{
+			TokenLifetime: 24 * time.Hour,
+		},
+		Game: Game{
+			MaxRooms:       16,
+			MaxPlayers:     MaxPlayersLimit,
+			ClientTimeout:  15 * time.Minute,
+			RoundOverScore: rules.RoundOverScore,
+			NewRoundWait:   rules.NewRoundWait,
+			MoveThrottle:   rules.MoveThrottle,
+			LaserThrottle:  rules.LaserThrottle,
+			LaserSpeed:     rules.LaserSpeed,
+		},
+		Maps: Maps{
+			Map:  "default",
+			Mode: "deathmatch",
+		},
+		Discovery: Discovery{
+			Enabled:  true,
+			Address:  discovery.DefaultAddress,
+			Interval: discovery.DefaultInterval,
+		},
+	}
+}
+
+func (game Game) Rules() backend.Rules {
+	return backend.Rules{
+		RoundOverScore: game.RoundOverScore,
+		NewRoundWait:   game.NewRoundWait,
+		MoveThrottle:   game.MoveThrottle,
+		LaserThrottle:  game.LaserThrottle,
+		LaserSpeed:     game.LaserSpeed,
+	}
+}
+
+// RegisterFlags adds a flag for every setting to flagSet, bound to cfg.
+func RegisterFlags(flagSet *flag.FlagSet, cfg *Config) {
+	flagSet.IntVar(&cfg.Network.Port, "port", cfg.Network.Port, "Port to listen on")
+	flagSet.StringVar(&cfg.Network.MetricsAddr, "metrics-addr", cfg.Network.MetricsAddr, "Address to serve Prometheus metrics on at /metrics, e.g. :9100, off if empty")
+	flagSet.StringVar(&cfg.Network.TLSCert, "tls-cert", cfg.Network.TLSCert, "TLS certificate file, enables TLS together with -tls-key")
+	flagSet.StringVar(&cfg.Network.TLSKey, "tls-key", cfg.Network.TLSKey, "TLS private key file")
+	flagSet.StringVar(&cfg.Network.TLSClientCA, "tls-client-ca", cfg.Network.TLSClientCA, "CA used to verify client certificates of trusted hosts")
+	flagSet.IntVar(&cfg.Network.MaxConnectionsPerIP, "max-connections-per-ip", cfg.Network.MaxConnectionsPerIP, "Maximum number of players connected from one IP address, 0 for no limit")
+	flagSet.IntVar(&cfg.Network.MaxConnectsPerMinute, "max-connects-per-minute", cfg.Network.MaxConnectsPerMinute, "Maximum number of connect attempts per minute from one IP address, 0 for no limit")
+	flagSet.DurationVar(&cfg.Network.ShutdownGrace, "shutdown-grace", cfg.Network.ShutdownGrace, "How long players are warned before the server stops on SIGINT or SIGTERM")
+	flagSet.BoolVar(&cfg.Network.Reflection, "reflection", cfg.Network.Reflection, "Serve gRPC server reflection for debugging with generic tools")
+
+	flagSet.StringVar(&cfg.Auth.Password, "password", cfg.Auth.Password, "Server password")
+	flagSet.StringVar(&cfg.Auth.Accounts, "accounts", cfg.Auth.Accounts, "Path to the accounts file, enables registration and login")
+	flagSet.StringVar(&cfg.Auth.TokenSecret, "token-secret", cfg.Auth.TokenSecret, "Secret used to sign session tokens, random if empty")
+	flagSet.StringVar(&cfg.Auth.ServerID, "server-id", cfg.Auth.ServerID, "Server ID embedded in session tokens, defaults to the hostname")
+	flagSet.DurationVar(&cfg.Auth.TokenLifetime, "token-lifetime", cfg.Auth.TokenLifetime, "How long session tokens are valid")
+	flagSet.StringVar(&cfg.Auth.AdminToken, "admin-token", cfg.Auth.AdminToken, "Token that authorizes calls of the Admin service, the service is off if empty")
+	flagSet.StringVar(&cfg.Auth.Bans, "bans", cfg.Auth.Bans, "Path to the ban file, reloaded when it changes, bans are kept in memory if empty")
+	flagSet.StringVar(&cfg.Auth.Revocations, "revocations", cfg.Auth.Revocations, "Path to the revoked tokens file, revocations are kept in memory if empty")
+
+	flagSet.IntVar(&cfg.Game.MaxRooms, "max-rooms", cfg.Game.MaxRooms, "Maximum number of rooms including the default room")
+	flagSet.IntVar(&cfg.Game.MaxPlayers, "max-players", cfg.Game.MaxPlayers, fmt.Sprintf("Maximum number of players of a room, at most %d", MaxPlayersLimit))
+	flagSet.DurationVar(&cfg.Game.ClientTimeout, "client-timeout", cfg.Game.ClientTimeout, "How long a client can stay silent before it is dropped")
+	flagSet.IntVar(&cfg.Game.RoundOverScore, "round-over-score", cfg.Game.RoundOverScore, "Score that wins a round")
+	flagSet.DurationVar(&cfg.Game.NewRoundWait, "new-round-wait", cfg.Game.NewRoundWait, "Break between two rounds")
+	flagSet.DurationVar(&cfg.Game.MoveThrottle, "move-throttle", cfg.Game.MoveThrottle, "Minimum time between two moves of a player")
+	flagSet.DurationVar(&cfg.Game.LaserThrottle, "laser-throttle", cfg.Game.LaserThrottle, "Minimum time between two lasers of a player")
+	flagSet.DurationVar(&cfg.Game.LaserSpeed, "laser-speed", cfg.Game.LaserSpeed, "Time a laser takes to move one tile")
+	flagSet.Int64Var(&cfg.Game.Seed, "seed", cfg.Game.Seed, "Seed for the random numbers of the games, random if 0")
+	flagSet.StringVar(&cfg.Game.ReplayDir, "replay-dir", cfg.Game.ReplayDir, "Directory to record a replay of every round to, recording is off if empty")
+	flagSet.StringVar(&cfg.Game.Ratings, "ratings", cfg.Game.Ratings, "Path to the skill ratings file, ratings are kept in memory if empty")
+	flagSet.StringVar(&cfg.Game.ChatFilter, "chat-filter", cfg.Game.ChatFilter, "File with blocked chat words, one per line")
+
+	flagSet.IntVar(&cfg.Bots.Count, "bots", cfg.Bots.Count, "Number of bots to add to server")
+
+	flagSet.StringVar(&cfg.Maps.Map, "map", cfg.Maps.Map, "Map of the default room, one of "+strings.Join(backend.MapNames(), ", "))
+	flagSet.StringVar(&cfg.Maps.Mode, "mode", cfg.Maps.Mode, "Game mode of the default room, deathmatch or tdm")
+	flagSet.Var((*listValue)(&cfg.Maps.Rotation), "rotation", "Comma separated maps the default room rotates through, one per round, as map or map:mode")
+
+	flagSet.BoolVar(&cfg.Discovery.Enabled, "discovery", cfg.Discovery.Enabled, "Announce the server to clients on the local network")
+	flagSet.StringVar(&cfg.Discovery.Address, "discovery-addr", cfg.Discovery.Address, "UDP address the server is announced to")
+	flagSet.DurationVar(&cfg.Discovery.Interval, "discovery-interval", cfg.Discovery.Interval, "Time between two announcements")
+	flagSet.StringVar(&cfg.Discovery.Name, "name", cfg.Discovery.Name, "Server name shown to clients on the local network, defaults to the hostname")
+}
+
+// listValue is a comma separated flag.
+type listValue []string
+
+func (list *listValue) String() string {
+	if list == nil {
+		return ""
+	}
+	return strings.Join(*list, ",")
+}
+
+func (list *listValue) Set(value string) error {
+	*list = nil
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*list = append(*list, item)
+		}
+	}
+	return nil
+}
+
+// Load reads the config file at path, which may be empty for the defaults,
+// then applies the flags that were set on the command line of flagSet and
+// validates the result. Reloading calls it again with the same flag set, so
+// the command line keeps winning.
+func Load(path string, flagSet *flag.FlagSet) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := decodeFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if flagSet != nil {
+		overrides := flag.NewFlagSet("overrides", flag.ContinueOnError)
+		RegisterFlags(overrides, cfg)
+		var err error
+		flagSet.Visit(func(f *flag.Flag) {
+			if overrides.Lookup(f.Name) != nil && err == nil {
+				err = overrides.Set(f.Name, f.Value.String())
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		if path != "" {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// decodeFile reads the TOML file at path into cfg. Keys that match no
+// setting are errors, so typos do not go unnoticed.
+func decodeFile(path string, cfg *Config) error {
+	meta, err := toml.DecodeFile(path, cfg)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, 0, len(undecoded))
+		for _, key := range undecoded {
+			keys = append(keys, key.String())
+		}
+		return fmt.Errorf("%s: unknown settings %s", path, strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// Validate returns an error listing every invalid setting.
+func (cfg *Config) Validate() error {
+	problems := []string{}
+	add := func(key string, format string, args ...interface{}) {
+		problems = append(problems, key+": "+fmt.Sprintf(format, args...))
+	}
+
+	if cfg.Network.Port < 0 || cfg.Network.Port > 65535 {
+		add("network.port", "%d is not a port", cfg.Network.Port)
+	}
+	if (cfg.Network.TLSCert == "") != (cfg.Network.TLSKey == "") {
+		add("network.tls_cert", "tls_cert and tls_key have to be set together")
+	}
+	if cfg.Network.TLSClientCA != "" && cfg.Network.TLSCert == "" {
+		add("network.tls_client_ca", "requires tls_cert and tls_key")
+	}
+	if cfg.Network.MaxConnectionsPerIP < 0 {
+		add("network.max_connections_per_ip", "can not be negative, 0 turns the limit off")
+	}
+	if cfg.Network.MaxConnectsPerMinute < 0 {
+		add("network.max_connects_per_minute", "can not be negative, 0 turns the limit off")
+	}
+	if cfg.Network.ShutdownGrace < 0 {
+		add("network.shutdown_grace", "can not be negative")
+	}
+
+	if cfg.Auth.TokenLifetime <= 0 {
+		add("auth.token_lifetime", "must be positive")
+	}
+
+	if cfg.Game.MaxRooms < 1 {
+		add("game.max_rooms", "must be at least 1 for the default room")
+	}
+	if cfg.Game.MaxPlayers < 1 || cfg.Game.MaxPlayers > MaxPlayersLimit {
+		add("game.max_players", "must be between 1 and %d, got %d", MaxPlayersLimit, cfg.Game.MaxPlayers)
+	}
+	if cfg.Game.ClientTimeout <= 0 {
+		add("game.client_timeout", "must be positive")
+	}
+	if cfg.Game.RoundOverScore < 1 {
+		add("game.round_over_score", "must be at least 1")
+	}
+	if cfg.Game.NewRoundWait < 0 {
+		add("game.new_round_wait", "can not be negative")
+	}
+	if cfg.Game.MoveThrottle < 0 {
+		add("game.move_throttle", "can not be negative")
+	}
+	if cfg.Game.LaserThrottle < 0 {
+		add("game.laser_throttle", "can not be negative")
+	}
+	if cfg.Game.LaserSpeed < time.Millisecond {
+		add("game.laser_speed", "must be at least 1ms")
+	}
+
+	if cfg.Bots.Count < 0 || cfg.Bots.Count >= cfg.Game.MaxPlayers {
+		add("bots.count", "must be between 0 and %d, one less than max_players", cfg.Game.MaxPlayers-1)
+	}
+
+	if _, err := backend.GetMap(cfg.Maps.Map); err != nil {
+		add("maps.map", "%v, the maps are %s", err, strings.Join(backend.MapNames(), ", "))
+	}
+	if _, err := backend.ParseGameMode(cfg.Maps.Mode); err != nil {
+		add("maps.mode", "%v", err)
+	}
+	for _, entry := range cfg.Maps.Rotation {
+		if _, err := backend.ParseMapChoice(entry, backend.ModeDeathmatch); err != nil {
+			add("maps.rotation", "%v in %q, entries are map or map:mode with a map of %s", err, entry, strings.Join(backend.MapNames(), ", "))
+		}
+	}
+
+	if cfg.Discovery.Enabled {
+		if _, _, err := net.SplitHostPort(cfg.Discovery.Address); err != nil {
+			add("discovery.address", "%v", err)
+		}
+		if cfg.Discovery.Interval < 100*time.Millisecond {
+			add("discovery.interval", "must be at least 100ms")
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid config:\n  %s", strings.Join(problems, "\n  "))
+}
+
+// reloadable are the settings a running server picks up on reload.
+var reloadable = map[string]bool{
+	"network.max_connections_per_ip":  true,
+	"network.max_connects_per_minute": true,
+	"network.shutdown_grace":          true,
+	"auth.password":                   true,
+	"game.max_players":                true,
+	"game.client_timeout":             true,
+	"game.round_over_score":           true,
+	"game.new_round_wait":             true,
+	"game.move_throttle":              true,
+	"game.laser_throttle":             true,
+	"game.laser_speed":                true,
+	"bots.count":                      true,
+	"maps.rotation":                   true,
+	"discovery.name":                  true,
+}
+
+// RestartRequired returns the settings that differ between old and new but
+// only take effect after a restart.
+func RestartRequired(old *Config, new *Config) []string {
+	keys := []string{}
+	oldValue := reflect.ValueOf(old).Elem()
+	newValue := reflect.ValueOf(new).Elem()
+	for i := 0; i < oldValue.NumField(); i++ {
+		table := oldValue.Type().Field(i).Tag.Get("toml")
+		oldTable := oldValue.Field(i)
+		newTable := newValue.Field(i)
+		for j := 0; j < oldTable.NumField(); j++ {
+			key := table + "." + oldTable.Type().Field(j).Tag.Get("toml")
+			if reloadable[key] {
+				continue
+			}
+			if !reflect.DeepEqual(oldTable.Field(j).Interface(), newTable.Field(j).Interface()) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
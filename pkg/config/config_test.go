@@ -0,0 +1,211 @@
+package config
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, text string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "server.toml")
+	if err := ioutil.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+# The main server.
+[network]
+port = 9000
+shutdown_grace = "30s"
+
+[auth]
+password = "hunter # 2" # not part of the password
+
+[game]
+max_players = 6
+round_over_score = 5
+laser_speed = "40ms"
+seed = 1_000
+
+[bots]
+count = 2
+
+[maps]
+mode = "tdm"
+rotation = [
+    "default",
+    'arena:tdm', # the small one
+]
+
+[discovery]
+name = "LAN party"
+interval = "5s"
+`)
+
+	cfg, err := Load(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Default()
+	want.Network.Port = 9000
+	want.Network.ShutdownGrace = 30 * time.Second
+	want.Auth.Password = "hunter # 2"
+	want.Game.MaxPlayers = 6
+	want.Game.RoundOverScore = 5
+	want.Game.LaserSpeed = 40 * time.Millisecond
+	want.Game.Seed = 1000
+	want.Bots.Count = 2
+	want.Maps.Mode = "tdm"
+	want.Maps.Rotation = []string{"default", "arena:tdm"}
+	want.Discovery.Name = "LAN party"
+	want.Discovery.Interval = 5 * time.Second
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("got config %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadTOMLSyntax(t *testing.T) {
+	path := writeConfig(t, `
+maps = { map = "arena", mode = "tdm" }
+
+[auth]
+password = """
+line one
+line two"""
+token_secret = 'C:\keys\secret'
+
+[discovery]
+name = "Tom's \"LAN\" party"
+`)
+
+	cfg, err := Load(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Maps.Map != "arena" || cfg.Maps.Mode != "tdm" {
+		t.Errorf("got maps %+v from the inline table, want arena and tdm", cfg.Maps)
+	}
+	if want := "line one\nline two"; cfg.Auth.Password != want {
+		t.Errorf("got password %q, want %q", cfg.Auth.Password, want)
+	}
+	if want := `C:\keys\secret`; cfg.Auth.TokenSecret != want {
+		t.Errorf("got token secret %q, want %q", cfg.Auth.TokenSecret, want)
+	}
+	if want := `Tom's "LAN" party`; cfg.Discovery.Name != want {
+		t.Errorf("got name %q, want %q", cfg.Discovery.Name, want)
+	}
+}
+
+func TestLoadFlagsOverride(t *testing.T) {
+	path := writeConfig(t, "[network]\nport = 9000\n\n[bots]\ncount = 3\n")
+
+	flagSet := flag.NewFlagSet("server", flag.ContinueOnError)
+	RegisterFlags(flagSet, Default())
+	if err := flagSet.Parse([]string{"-bots", "1", "-rotation", "default,arena"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path, flagSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Network.Port != 9000 {
+		t.Errorf("got port %d from the file, want 9000", cfg.Network.Port)
+	}
+	if cfg.Bots.Count != 1 {
+		t.Errorf("got %d bots, want the 1 of the command line", cfg.Bots.Count)
+	}
+	if want := []string{"default", "arena"}; !reflect.DeepEqual(cfg.Maps.Rotation, want) {
+		t.Errorf("got rotation %v, want %v", cfg.Maps.Rotation, want)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "unknown key",
+			text: "[game]\nround_over = 5\n",
+			want: []string{"server.toml: unknown settings game.round_over"},
+		},
+		{
+			name: "unknown table",
+			text: "[gameplay]\n",
+			want: []string{"server.toml: unknown settings gameplay"},
+		},
+		{
+			name: "wrong type",
+			text: "[network]\nport = \"9000\"\n",
+			want: []string{`line 2 (last key "network.port"): incompatible types`},
+		},
+		{
+			name: "invalid duration",
+			text: "[game]\nlaser_speed = \"fast\"\n",
+			want: []string{`line 2 (last key "game.laser_speed"): invalid duration: "fast"`},
+		},
+		{
+			name: "key set twice",
+			text: "[bots]\ncount = 1\ncount = 2\n",
+			want: []string{"line 3", "bots.count", "already been defined"},
+		},
+		{
+			name: "every invalid setting is listed",
+			text: "[game]\nmax_players = 9\nlaser_speed = \"0s\"\n\n[maps]\nrotation = [\"default\", \"nowhere\", \"arena:ctf\"]\n",
+			want: []string{
+				"game.max_players: must be between 1 and 8, got 9",
+				"game.laser_speed: must be at least 1ms",
+				`maps.rotation: unknown map "nowhere"`,
+				`maps.rotation: unknown game mode "ctf"`,
+			},
+		},
+		{
+			name: "invalid discovery address",
+			text: "[discovery]\naddress = \"255.255.255.255\"\n",
+			want: []string{"discovery.address: address 255.255.255.255: missing port in address"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, test.text), nil)
+			if err == nil {
+				t.Fatal("got no error")
+			}
+			for _, want := range test.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("got error %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	old := Default()
+	next := Default()
+	next.Network.Port = 9000
+	next.Auth.Password = "secret"
+	next.Game.LaserSpeed = time.Second
+	next.Maps.Map = "arena"
+	next.Maps.Rotation = []string{"arena"}
+	next.Discovery.Name = "LAN party"
+	next.Discovery.Enabled = false
+
+	got := RestartRequired(old, next)
+	want := []string{"discovery.enabled", "maps.map", "network.port"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
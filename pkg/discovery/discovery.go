@@ -0,0 +1,257 @@
+// Package discovery lets servers announce themselves on the local network
+// and clients find them. A server broadcasts a small JSON datagram every few
+// seconds, a client listens for them and keeps the servers it heard from
+// recently.
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultPort is the UDP port servers announce themselves on.
+	DefaultPort = 8887
+	// DefaultInterval is how often a server announces itself.
+	DefaultInterval = 2 * time.Second
+
+	// game tells announcements of this game apart from other datagrams on
+	// the port.
+	game    = "tshooter"
+	version = 1
+	// maxPacketSize is more than any announcement needs, longer server
+	// names are cut.
+	maxPacketSize = 1024
+	maxNameLength = 64
+	// missedAnnouncements is how many announcements in a row a server can
+	// miss before clients forget it.
+	missedAnnouncements = 3
+)
+
+// DefaultAddress is where servers announce themselves, the broadcast address
+// of the local network.
+var DefaultAddress = net.JoinHostPort("255.255.255.255", strconv.Itoa(DefaultPort))
+
+// Announcement describes a server to the clients on the local network.
+type Announcement struct {
+	Name        string `json:"name"`
+	Map         string `json:"map"`
+	Mode        string `json:"mode"`
+	Players     int    `json:"players"`
+	MaxPlayers  int    `json:"maxPlayers"`
+	HasPassword bool   `json:"hasPassword"`
+	// HasAccounts tells clients that guests are turned away.
+	HasAccounts bool `json:"hasAccounts"`
+	// Port is the port the game server listens on, clients connect to the
+	// host the announcement came from.
+	Port int `json:"port"`
+}
+
+type packet struct {
+	Game    string `json:"game"`
+	Version int    `json:"version"`
+	// IntervalMS is the time until the next announcement in milliseconds.
+	IntervalMS int64 `json:"intervalMs"`
+	Announcement
+}
+
+func encode(announcement Announcement, interval time.Duration) ([]byte, error) {
+	if len(announcement.Name) > maxNameLength {
+		announcement.Name = announcement.Name[:maxNameLength]
+	}
+	return json.Marshal(packet{
+		Game:         game,
+		Version:      version,
+		IntervalMS:   interval.Milliseconds(),
+		Announcement: announcement,
+	})
+}
+
+func decode(data []byte) (packet, error) {
+	var p packet
+	if err := json.Unmarshal(data, &p); err != nil {
+		return packet{}, err
+	}
+	if p.Game != game || p.Version != version {
+		return packet{}, errors.New("not an announcement of this game")
+	}
+	if p.Port < 1 || p.Port > 65535 {
+		return packet{}, errors.New("announcement without a valid port")
+	}
+	if p.IntervalMS <= 0 {
+		p.IntervalMS = DefaultInterval.Milliseconds()
+	}
+	return p, nil
+}
+
+// Announcer sends an announcement to an address at a fixed interval.
+type Announcer struct {
+	conn     *net.UDPConn
+	interval time.Duration
+	info     func() Announcement
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewAnnouncer announces the server to address, usually DefaultAddress. info
+// is called for every announcement so it always carries the current players.
+func NewAnnouncer(address string, interval time.Duration, info func() Announcement) (*Announcer, error) {
+	udpAddr, err := net.ResolveUDPAddr("udp4", address)
+	if err != nil {
+		return nil, err
+	}
+	// Go sockets may send to broadcast addresses.
+	conn, err := net.DialUDP("udp4", nil, udpAddr)
+	if err != nil {
+		return nil, err
+	}
+	return &Announcer{
+		conn:     conn,
+		interval: interval,
+		info:     info,
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Start announces the server right away and then every interval until Stop is
+// called. onError is called when an announcement could not be sent.
+func (announcer *Announcer) Start(onError func(error)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	announcer.cancel = cancel
+
+	go func() {
+		defer close(announcer.done)
+		ticker := time.NewTicker(announcer.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := announcer.announce(); err != nil && onError != nil {
+				onError(err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (announcer *Announcer) announce() error {
+	data, err := encode(announcer.info(), announcer.interval)
+	if err != nil {
+		return err
+	}
+	_, err = announcer.conn.Write(data)
+	return err
+}
+
+// Stop ends the announcements, clients forget the server after a few missed
+// ones.
+func (announcer *Announcer) Stop() {
+	if announcer.cancel != nil {
+		announcer.cancel()
+		<-announcer.done
+	}
+	announcer.conn.Close()
+}
+
+// Server is a server a Listener heard from.
+type Server struct {
+	Announcement
+	// Address is the host the announcement came from with the announced
+	// port, ready to connect to.
+	Address  string
+	LastSeen time.Time
+
+	// expiry is how long the server is listed after LastSeen.
+	expiry time.Duration
+}
+
+// Listener collects the announcements of the servers on the local network.
+type Listener struct {
+	conn *net.UDPConn
+
+	mu      sync.Mutex
+	servers map[string]Server
+}
+
+// Listen receives announcements on address, usually ":8887". Servers are
+// forgotten when they missed a few announcements.
+func Listen(address string) (*Listener, error) {
+	// Several clients on one host share the port.
+	config := net.ListenConfig{Control: reuseAddress}
+	conn, err := config.ListenPacket(context.Background(), "udp4", address)
+	if err != nil {
+		return nil, err
+	}
+	listener := &Listener{
+		conn:    conn.(*net.UDPConn),
+		servers: make(map[string]Server),
+	}
+	go listener.receive()
+	return listener, nil
+}
+
+// Addr is the address the listener receives on.
+func (listener *Listener) Addr() net.Addr {
+	return listener.conn.LocalAddr()
+}
+
+func (listener *Listener) receive() {
+	buffer := make([]byte, maxPacketSize)
+	for {
+		n, from, err := listener.conn.ReadFromUDP(buffer)
+		if err != nil {
+			// The listener was closed.
+			return
+		}
+		p, err := decode(buffer[:n])
+		if err != nil {
+			continue
+		}
+		address := net.JoinHostPort(from.IP.String(), strconv.Itoa(p.Port))
+
+		listener.mu.Lock()
+		listener.servers[address] = Server{
+			Announcement: p.Announcement,
+			Address:      address,
+			LastSeen:     time.Now(),
+			expiry:       missedAnnouncements * time.Duration(p.IntervalMS) * time.Millisecond,
+		}
+		listener.mu.Unlock()
+	}
+}
+
+// Servers returns the servers heard from recently, sorted by name and
+// address.
+func (listener *Listener) Servers() []Server {
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+
+	servers := make([]Server, 0, len(listener.servers))
+	for address, server := range listener.servers {
+		if time.Since(server.LastSeen) > server.expiry {
+			delete(listener.servers, address)
+			continue
+		}
+		servers = append(servers, server)
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		if servers[i].Name != servers[j].Name {
+			return servers[i].Name < servers[j].Name
+		}
+		return servers[i].Address < servers[j].Address
+	})
+	return servers
+}
+
+func (listener *Listener) Close() error {
+	return listener.conn.Close()
+}
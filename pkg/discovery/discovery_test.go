@@ -0,0 +1,107 @@
+package discovery
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func eventually(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAnnounceOverLoopback(t *testing.T) {
+	listener, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	players := int32(1)
+	announcer, err := NewAnnouncer(listener.Addr().String(), 20*time.Millisecond, func() Announcement {
+		return Announcement{
+			Name:        "LAN party",
+			Map:         "arena",
+			Mode:        "tdm",
+			Players:     int(atomic.LoadInt32(&players)),
+			MaxPlayers:  8,
+			HasPassword: true,
+			HasAccounts: true,
+			Port:        9000,
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	announcer.Start(func(err error) {
+		t.Errorf("failed to announce: %v", err)
+	})
+
+	eventually(t, "the server to be discovered", func() bool {
+		return len(listener.Servers()) == 1
+	})
+	server := listener.Servers()[0]
+	if server.Address != "127.0.0.1:9000" {
+		t.Errorf("got address %q, want 127.0.0.1:9000", server.Address)
+	}
+	want := Announcement{Name: "LAN party", Map: "arena", Mode: "tdm", Players: 1, MaxPlayers: 8, HasPassword: true, HasAccounts: true, Port: 9000}
+	if server.Announcement != want {
+		t.Errorf("got announcement %+v, want %+v", server.Announcement, want)
+	}
+
+	atomic.StoreInt32(&players, 3)
+	eventually(t, "the player count to change", func() bool {
+		servers := listener.Servers()
+		return len(servers) == 1 && servers[0].Players == 3
+	})
+
+	announcer.Stop()
+	eventually(t, "the server to be forgotten", func() bool {
+		return len(listener.Servers()) == 0
+	})
+}
+
+func TestListenIgnoresOtherPackets(t *testing.T) {
+	listener, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	conn, err := net.Dial("udp4", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	packets := []string{
+		"hello",
+		`{"game":"other","version":1,"name":"other game","port":9000}`,
+		`{"game":"tshooter","version":2,"name":"newer version","port":9000}`,
+		`{"game":"tshooter","version":1,"name":"no port"}`,
+		`{"game":"tshooter","version":1,"name":"valid","port":9000}`,
+	}
+	for _, packet := range packets {
+		if _, err := conn.Write([]byte(packet)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	eventually(t, "the valid announcement", func() bool {
+		return len(listener.Servers()) > 0
+	})
+	// The datagrams arrive in order over loopback, the valid one is last.
+	servers := listener.Servers()
+	if len(servers) != 1 || servers[0].Name != "valid" {
+		t.Errorf("got servers %+v, want only the valid announcement", servers)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+// +build !windows
+
+package discovery
+
+import "syscall"
+
+func reuseAddress(network string, address string, conn syscall.RawConn) error {
+	var sockErr error
+	err := conn.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1)
+	})
+	if err != nil {
+		return err
+	}
+	return sockErr
+}
@@ -0,0 +1,9 @@
+package discovery
+
+import "syscall"
+
+// reuseAddress leaves the socket alone, Windows lets only one client on a
+// host listen for announcements.
+func reuseAddress(network string, address string, conn syscall.RawConn) error {
+	return nil
+}
@@ -0,0 +1,138 @@
+package frontend
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tcell "github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+type ChatChannel int
+
+const (
+	ChatGlobal ChatChannel = iota
+	ChatTeam
+	ChatWhisper
+	ChatSystem
+)
+
+const (
+	chatLogHeight   = 6
+	chatLogLimit    = 100
+	chatInputBuffer = 8
+	chatInputLabel  = "say: "
+	whisperPrefix   = "/w "
+	teamPrefix      = "/t "
+)
+
+type ChatMessage struct {
+	Channel   ChatChannel
+	Sender    string
+	Recipient string
+	Text      string
+	Time      time.Time
+}
+
+func (message ChatMessage) format() string {
+	timestamp := message.Time.Format("15:04")
+	text := tview.Escape(message.Text)
+	sender := tview.Escape(message.Sender)
+
+	switch message.Channel {
+	case ChatTeam:
+		return fmt.Sprintf("[gray]%s[-] [green](team) %s:[-] %s", timestamp, sender, text)
+	case ChatWhisper:
+		return fmt.Sprintf("[gray]%s[-] [fuchsia]%s → %s:[-] %s", timestamp, sender, tview.Escape(message.Recipient), text)
+	case ChatSystem:
+		return fmt.Sprintf("[gray]%s[-] [yellow]%s[-]", timestamp, text)
+	}
+	return fmt.Sprintf("[gray]%s[-] [white]%s:[-] %s", timestamp, sender, text)
+}
+
+// parseChatInput turns "/w name text" into a whisper and "/t text" into a
+// team message, everything else goes to the global channel.
+func parseChatInput(text string) (ChatMessage, bool) {
+	text = strings.TrimSpace(text)
+	message := ChatMessage{Channel: ChatGlobal, Text: text}
+
+	switch {
+	case strings.HasPrefix(text, whisperPrefix):
+		parts := strings.SplitN(strings.TrimPrefix(text, whisperPrefix), " ", 2)
+		if len(parts) < 2 {
+			return message, false
+		}
+		message.Channel = ChatWhisper
+		message.Recipient = parts[0]
+		message.Text = strings.TrimSpace(parts[1])
+	case strings.HasPrefix(text, teamPrefix):
+		message.Channel = ChatTeam
+		message.Text = strings.TrimSpace(strings.TrimPrefix(text, teamPrefix))
+	}
+	return message, message.Text != ""
+}
+
+func setupChat(view *View) {
+	chatLog := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true)
+	chatLog.SetBackgroundColor(backgroundColor)
+
+	chatInput := tview.NewInputField().
+		SetLabel(chatInputLabel).
+		SetLabelColor(textColor).
+		SetFieldBackgroundColor(backgroundColor)
+	chatInput.SetBackgroundColor(backgroundColor)
+
+	chatInput.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			message, ok := parseChatInput(chatInput.GetText())
+			if ok {
+				select {
+				case view.ChatInput <- message:
+				default:
+				}
+			}
+		}
+		chatInput.SetText("")
+		view.App.SetFocus(view.viewPort)
+	})
+
+	callback := func() {
+		view.chatMu.Lock()
+		defer view.chatMu.Unlock()
+
+		if !view.chatChanged {
+			return
+		}
+		chatLog.SetText(strings.Join(view.chatLines, "\n"))
+		chatLog.ScrollToEnd()
+		view.chatChanged = false
+	}
+
+	view.drawCallbacks = append(view.drawCallbacks, callback)
+	view.chatLog = chatLog
+	view.chatInput = chatInput
+}
+
+func (view *View) isTyping() bool {
+	return view.chatInput.HasFocus()
+}
+
+// AddChatMessage only records the message, the chat log is redrawn from a draw
+// callback so callers never wait on the UI goroutine.
+func (view *View) AddChatMessage(message ChatMessage) {
+	if message.Time.IsZero() {
+		message.Time = time.Now()
+	}
+
+	view.chatMu.Lock()
+	view.chatLines = append(view.chatLines, message.format())
+	if len(view.chatLines) > chatLogLimit {
+		view.chatLines = view.chatLines[len(view.chatLines)-chatLogLimit:]
+	}
+	view.chatChanged = true
+	view.chatMu.Unlock()
+}
@@ -0,0 +1,147 @@
+package frontend
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rivo/tview"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+)
+
+const (
+	feedLimit            = 5
+	feedWidth            = 36
+	feedLifetime         = 8 * time.Second
+	notificationLifetime = 4 * time.Second
+	streakAnnounceEvery  = 5
+)
+
+type feedEvent struct {
+	text    string
+	created time.Time
+}
+
+// fadeColor dims an event during the last third of its lifetime before it is
+// removed from the panel.
+func fadeColor(created time.Time, lifetime time.Duration, now time.Time) string {
+	age := now.Sub(created)
+	switch {
+	case age > lifetime*2/3:
+		return "gray"
+	case age > lifetime/3:
+		return "silver"
+	}
+	return "white"
+}
+
+func pruneEvents(events []feedEvent, lifetime time.Duration, now time.Time) []feedEvent {
+	live := events[:0]
+	for _, event := range events {
+		if now.Sub(event.created) < lifetime {
+			live = append(live, event)
+		}
+	}
+	return live
+}
+
+func setupFeed(view *View) {
+	killFeed := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignRight)
+	killFeed.SetBackgroundColor(backgroundColor)
+
+	notification := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	notification.SetBackgroundColor(backgroundColor)
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 1, 1, false).
+		AddItem(tview.NewFlex().
+			AddItem(nil, 0, 1, false).
+			AddItem(notification, 0, 2, false).
+			AddItem(nil, 0, 1, false), 1, 1, false).
+		AddItem(tview.NewFlex().
+			AddItem(nil, 0, 1, false).
+			AddItem(killFeed, feedWidth, 1, false).
+			AddItem(nil, 1, 1, false), feedLimit, 1, false).
+		AddItem(nil, 0, 1, false)
+
+	callback := func() {
+		view.feedMu.Lock()
+		defer view.feedMu.Unlock()
+
+		now := time.Now()
+		view.kills = pruneEvents(view.kills, feedLifetime, now)
+		view.notifications = pruneEvents(view.notifications, notificationLifetime, now)
+
+		lines := make([]string, 0, len(view.kills))
+		for _, event := range view.kills {
+			lines = append(lines, fmt.Sprintf("[%s]%s[-]", fadeColor(event.created, feedLifetime, now), event.text))
+		}
+		killFeed.SetText(strings.Join(lines, "\n"))
+
+		text := ""
+		if len(view.notifications) > 0 {
+			event := view.notifications[len(view.notifications)-1]
+			text = fmt.Sprintf("[%s]%s[-]", fadeColor(event.created, notificationLifetime, now), event.text)
+		}
+		notification.SetText(text)
+	}
+
+	view.drawCallbacks = append(view.drawCallbacks, callback)
+	view.pages.AddPage("feed", layout, true, true)
+}
+
+// AddKill adds "killer zapped victim" to the kill feed and announces the
+// killer's streak every streakAnnounceEvery kills without dying.
+func (view *View) AddKill(killer *backend.Player, victim *backend.Player) {
+	now := time.Now()
+
+	view.feedMu.Lock()
+	defer view.feedMu.Unlock()
+
+	view.streaks[victim.ID()] = 0
+	view.streaks[killer.ID()]++
+	streak := view.streaks[killer.ID()]
+
+	view.kills = append(view.kills, feedEvent{
+		text: fmt.Sprintf(
+			"%s %s zapped %s",
+			now.Format("15:04:05"),
+			tview.Escape(killer.Name),
+			tview.Escape(victim.Name),
+		),
+		created: now,
+	})
+	if len(view.kills) > feedLimit {
+		view.kills = view.kills[len(view.kills)-feedLimit:]
+	}
+
+	if streak%streakAnnounceEvery == 0 {
+		view.notifications = append(view.notifications, feedEvent{
+			text:    fmt.Sprintf("%s is on a %d-kill streak", tview.Escape(killer.Name), streak),
+			created: now,
+		})
+	}
+}
+
+func (view *View) AddNotification(text string) {
+	view.feedMu.Lock()
+	defer view.feedMu.Unlock()
+
+	view.notifications = append(view.notifications, feedEvent{
+		text:    tview.Escape(text),
+		created: time.Now(),
+	})
+}
+
+func (view *View) ResetStreaks() {
+	view.feedMu.Lock()
+	defer view.feedMu.Unlock()
+
+	view.streaks = make(map[uuid.UUID]int)
+}
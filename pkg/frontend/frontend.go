@@ -5,6 +5,7 @@ import (
 	"math"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,6 +22,9 @@ const (
 	wallColor       = tcell.Color24
 	laserColor      = tcell.ColorRed
 	drawFrequency   = 17 * time.Millisecond
+	scoreboardWidth = 24
+	playerHelp      = "← → ↑ ↓ move - wasd shoot - enter chat - p score - esc close - ctrl+q quit"
+	spectatorHelp   = "← → ↑ ↓ free camera - f follow - tab/n next player - b previous player - enter chat - ctrl+q quit"
 )
 
 type View struct {
@@ -32,6 +36,26 @@ type View struct {
 	pages         *tview.Pages
 	RoundWait     *tview.TextView
 	Done          chan error
+	ChatInput     chan ChatMessage
+	// VoteInput receives the index of the map candidate the player votes
+	// for.
+	VoteInput      chan int
+	voteMu         sync.Mutex
+	voteCandidates []MapCandidate
+	voteChoice     int
+	chatLog        *tview.TextView
+	chatInput      *tview.InputField
+	chatMu         sync.Mutex
+	chatLines      []string
+	chatChanged    bool
+	feedMu         sync.Mutex
+	kills          []feedEvent
+	notifications  []feedEvent
+	streaks        map[uuid.UUID]int
+	spectator      bool
+	mainLayout     *tview.Flex
+	scoreboard     *tview.TextView
+	helpText       *tview.TextView
 }
 
 func withinDrawBounds(x, y, width, height int) bool {
@@ -44,6 +68,9 @@ func setupViewPort(view *View) {
 		SetBackgroundColor(backgroundColor)
 	cameraX := 0
 	cameraY := 0
+	// Spectators either follow followID or move the camera freely.
+	freeCamera := false
+	followID := uuid.Nil
 
 	box.SetDrawFunc(
 		func(screen tcell.Screen, x int, y int, width int, height int) (int, int, int, int) {
@@ -52,30 +79,44 @@ func setupViewPort(view *View) {
 
 			style := tcell.StyleDefault.Background(backgroundColor)
 
-			view.Game.Mu.RLock()
-
-			currentEntity := view.Game.GetEntity(view.CurrentPlayer)
-			if currentEntity == nil {
-				return 0, 0, 0, 0
-			}
-			currentPlayerPosition := currentEntity.(*backend.Player).Position()
-
-			cameraDiffX := float64(cameraX - currentPlayerPosition.X)
-			cameraDiffY := float64(cameraY - currentPlayerPosition.Y)
-			cameraDiffXMax := float64(width / 6)
-			cameraDiffYMax := float64(height / 6)
-			if math.Abs(cameraDiffX) > cameraDiffXMax {
-				if cameraDiffX <= 0 {
-					cameraX++
+			var target *backend.Player
+			if view.spectator {
+				if !freeCamera {
+					target = view.followedPlayer(&followID)
+				}
+				if target != nil {
+					box.SetTitle(fmt.Sprintf("spectating %s", target.Name))
 				} else {
-					cameraX--
+					box.SetTitle("spectating - free camera")
+				}
+			} else {
+				currentEntity := view.Game.GetEntity(view.CurrentPlayer)
+				if currentEntity == nil {
+					return 0, 0, 0, 0
 				}
+				target = currentEntity.(*backend.Player)
 			}
-			if math.Abs(cameraDiffY) > cameraDiffYMax {
-				if cameraDiffY <= 0 {
-					cameraY++
-				} else {
-					cameraY--
+
+			if target != nil {
+				targetPosition := target.Position()
+
+				cameraDiffX := float64(cameraX - targetPosition.X)
+				cameraDiffY := float64(cameraY - targetPosition.Y)
+				cameraDiffXMax := float64(width / 6)
+				cameraDiffYMax := float64(height / 6)
+				if math.Abs(cameraDiffX) > cameraDiffXMax {
+					if cameraDiffX <= 0 {
+						cameraX++
+					} else {
+						cameraX--
+					}
+				}
+				if math.Abs(cameraDiffY) > cameraDiffYMax {
+					if cameraDiffY <= 0 {
+						cameraY++
+					} else {
+						cameraY--
+					}
 				}
 			}
 
@@ -145,6 +186,45 @@ func setupViewPort(view *View) {
 	)
 
 	box.SetInputCapture(func(e *tcell.EventKey) *tcell.EventKey {
+		if e.Key() == tcell.KeyEnter {
+			view.App.SetFocus(view.chatInput)
+			return nil
+		}
+
+		if view.spectator {
+			switch e.Key() {
+			case tcell.KeyUp:
+				freeCamera = true
+				cameraY--
+			case tcell.KeyDown:
+				freeCamera = true
+				cameraY++
+			case tcell.KeyLeft:
+				freeCamera = true
+				cameraX--
+			case tcell.KeyRight:
+				freeCamera = true
+				cameraX++
+			case tcell.KeyTab:
+				freeCamera = false
+				view.cycleFollow(&followID, 1)
+			case tcell.KeyBacktab:
+				freeCamera = false
+				view.cycleFollow(&followID, -1)
+			}
+			switch e.Rune() {
+			case 'f':
+				freeCamera = !freeCamera
+			case 'n':
+				freeCamera = false
+				view.cycleFollow(&followID, 1)
+			case 'b':
+				freeCamera = false
+				view.cycleFollow(&followID, -1)
+			}
+			return e
+		}
+
 		direction := backend.DirectionStop
 		switch e.Key() {
 		case tcell.KeyUp:
@@ -157,11 +237,11 @@ func setupViewPort(view *View) {
 			direction = backend.DirectionRight
 		}
 		if direction != backend.DirectionStop {
-			view.Game.ActionChannel <- backend.MoveAction{
+			view.Game.SendAction(backend.MoveAction{
 				ID:        view.CurrentPlayer,
 				Direction: direction,
-				Created:   time.Now(),
-			}
+				Created:   view.Game.Clock().Now(),
+			})
 		}
 
 		laserDirection := backend.DirectionStop
@@ -176,27 +256,115 @@ func setupViewPort(view *View) {
 			laserDirection = backend.DirectionRight
 		}
 		if laserDirection != backend.DirectionStop {
-			view.Game.ActionChannel <- backend.LaserAction{
+			view.Game.SendAction(backend.LaserAction{
 				OwnerID:   view.CurrentPlayer,
 				ID:        uuid.New(),
 				Direction: laserDirection,
-				Created: time.Now(),
-			}
+				Created:   view.Game.Clock().Now(),
+			})
 		}
 		return e
 	})
 
 	helpText := tview.NewTextView().
-				SetTextAlign(tview.AlignCenter).
-				SetText("← → ↑ ↓ move - wasd shoot - p score - esc close - ctrl+q quit").
-				SetTextColor(textColor)
+		SetTextAlign(tview.AlignCenter).
+		SetText(playerHelp).
+		SetTextColor(textColor)
 	helpText.SetBackgroundColor(backgroundColor)
+
+	scoreboard := tview.NewTextView()
+	scoreboard.SetBorder(true).SetTitle("Score").SetBackgroundColor(backgroundColor)
+	view.drawCallbacks = append(view.drawCallbacks, func() {
+		if view.spectator {
+			scoreboard.SetText(view.scoreText())
+		}
+	})
+
+	mainLayout := tview.NewFlex().
+		AddItem(box, 0, 1, true).
+		AddItem(scoreboard, 0, 0, false)
 	flex := tview.NewFlex().
-			SetDirection(tview.FlexRow).
-			AddItem(box, 0, 1, true).
-			AddItem(helpText, 1, 1, false)
+		SetDirection(tview.FlexRow).
+		AddItem(mainLayout, 0, 1, true).
+		AddItem(view.chatLog, chatLogHeight, 1, false).
+		AddItem(view.chatInput, 1, 1, false).
+		AddItem(helpText, 1, 1, false)
 	view.pages.AddPage("viewport", flex, true, true)
 	view.viewPort = box
+	view.mainLayout = mainLayout
+	view.scoreboard = scoreboard
+	view.helpText = helpText
+}
+
+// SetSpectator switches the view to spectating, with a camera that follows
+// any player or moves freely and a permanent scoreboard. It has to be called
+// before Start.
+func (view *View) SetSpectator(spectator bool) {
+	view.spectator = spectator
+	if spectator {
+		view.mainLayout.ResizeItem(view.scoreboard, scoreboardWidth, 0)
+		view.helpText.SetText(spectatorHelp)
+	} else {
+		view.mainLayout.ResizeItem(view.scoreboard, 0, 0)
+		view.helpText.SetText(playerHelp)
+	}
+}
+
+// SetHelpText replaces the key help below the chat. Once the view has started
+// it has to be called from the UI goroutine, e.g. with App.QueueUpdate.
+func (view *View) SetHelpText(text string) {
+	view.helpText.SetText(text)
+}
+
+// sortedPlayers must be called with view.Game.Mu held.
+func (view *View) sortedPlayers() []*backend.Player {
+	players := make([]*backend.Player, 0)
+	for _, entity := range view.Game.Entities {
+		player, ok := entity.(*backend.Player)
+		if ok {
+			players = append(players, player)
+		}
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return strings.ToLower(players[i].Name) < strings.ToLower(players[j].Name)
+	})
+	return players
+}
+
+// followedPlayer returns the player the spectator camera follows and picks the
+// first one if that player has left. It must be called with view.Game.Mu held.
+func (view *View) followedPlayer(followID *uuid.UUID) *backend.Player {
+	player, ok := view.Game.GetEntity(*followID).(*backend.Player)
+	if ok {
+		return player
+	}
+
+	players := view.sortedPlayers()
+	if len(players) == 0 {
+		return nil
+	}
+	*followID = players[0].ID()
+	return players[0]
+}
+
+func (view *View) cycleFollow(followID *uuid.UUID, step int) {
+	view.Game.Mu.RLock()
+	defer view.Game.Mu.RUnlock()
+
+	players := view.sortedPlayers()
+	if len(players) == 0 {
+		return
+	}
+
+	current := 0
+	for i, player := range players {
+		if player.ID() == *followID {
+			current = i
+			break
+		}
+	}
+	next := (current + step + len(players)) % len(players)
+	*followID = players[next].ID()
 }
 
 func centeredModal(p tview.Primitive) tview.Primitive {
@@ -209,56 +377,58 @@ func centeredModal(p tview.Primitive) tview.Primitive {
 		).AddItem(nil, 0, 1, false)
 }
 
-func setupScoreModal(view *View) {
-	textView := tview.NewTextView()
-	textView.SetBorder(true).SetTitle("Score").SetBackgroundColor(backgroundColor)
-	modal := centeredModal(textView)
+func (view *View) scoreText() string {
+	view.Game.Mu.RLock()
+	defer view.Game.Mu.RUnlock()
 
-	callback := func() {
-		view.Game.Mu.RLock()
-		defer view.Game.Mu.RUnlock()
+	text := ""
+	type PlayerScore struct {
+		Name  string
+		Score int
+	}
+	playerScore := make([]PlayerScore, 0)
 
-		text := ""
-		type PlayerScore struct {
-			Name  string
-			Score int
+	for _, entity := range view.Game.Entities {
+		player, ok := entity.(*backend.Player)
+		if !ok {
+			continue
 		}
-		playerScore := make([]PlayerScore, 0)
-
-		for _, entity := range view.Game.Entities {
-			player, ok := entity.(*backend.Player)
-			if !ok {
-				continue
-			}
-
-			score, ok := view.Game.Score[player.ID()]
-			if !ok {
-				score = 0
-			}
 
-			playerScore = append(playerScore, PlayerScore{
-				Name:  player.Name,
-				Score: score,
-			})
+		score, ok := view.Game.Score[player.ID()]
+		if !ok {
+			score = 0
 		}
 
-		sort.Slice(playerScore, func(i, j int) bool {
-			if playerScore[i].Score > playerScore[j].Score {
-				return true
-			} else if playerScore[i].Score < playerScore[j].Score {
-				return false
-			} else if strings.ToLower(playerScore[i].Name) < strings.ToLower(playerScore[j].Name) {
-				return true
-			}
-			return false
+		playerScore = append(playerScore, PlayerScore{
+			Name:  player.Name,
+			Score: score,
 		})
+	}
 
-		for _, playerScore := range playerScore {
-			text += fmt.Sprintf("%s - %d\n", playerScore.Name, playerScore.Score)
+	sort.Slice(playerScore, func(i, j int) bool {
+		if playerScore[i].Score > playerScore[j].Score {
+			return true
+		} else if playerScore[i].Score < playerScore[j].Score {
+			return false
+		} else if strings.ToLower(playerScore[i].Name) < strings.ToLower(playerScore[j].Name) {
+			return true
 		}
+		return false
+	})
+
+	for _, playerScore := range playerScore {
+		text += fmt.Sprintf("%s - %d\n", playerScore.Name, playerScore.Score)
+	}
+	return text
+}
 
-		view.Game.Mu.RUnlock()
-		textView.SetText(text)
+func setupScoreModal(view *View) {
+	textView := tview.NewTextView()
+	textView.SetBorder(true).SetTitle("Score").SetBackgroundColor(backgroundColor)
+	modal := centeredModal(textView)
+
+	callback := func() {
+		textView.SetText(view.scoreText())
 	}
 
 	view.drawCallbacks = append(view.drawCallbacks, callback)
@@ -276,23 +446,33 @@ func setupRoundWaitModal(view *View) {
 	modal := centeredModal(textView)
 	view.pages.AddPage("roundwait", modal, true, false)
 
+	wasWaiting := false
 	callback := func() {
 		view.Game.Mu.RLock()
 		defer view.Game.Mu.RUnlock()
 
 		if view.Game.WaitForRound {
+			wasWaiting = true
 			view.pages.ShowPage("roundwait")
 
-			seconds := int(view.Game.NewRoundAt.Sub(time.Now()).Seconds())
+			seconds := int(view.Game.NewRoundAt.Sub(view.Game.Clock().Now()).Seconds())
 			if seconds < 0 {
 				seconds = 0
 			}
 
-			player := view.Game.GetEntity(view.Game.RoundWinner).(*backend.Player)
-			text := fmt.Sprintf("\nWinner: %s\n\n", player.Name)
+			winner := "a player who left"
+			player, ok := view.Game.GetEntity(view.Game.RoundWinner).(*backend.Player)
+			if ok {
+				winner = player.Name
+			}
+			text := fmt.Sprintf("\nWinner: %s\n\n", winner)
 			text += fmt.Sprintf("New round in %d seconds...", seconds)
+			if vote := view.mapVoteText(); vote != "" {
+				text += "\n\n" + vote
+			}
 			textView.SetText(text)
-		} else {
+		} else if wasWaiting {
+			wasWaiting = false
 			view.pages.HidePage("roundwait")
 			view.App.SetFocus(view.viewPort)
 		}
@@ -311,19 +491,30 @@ func NewView(game *backend.Game) *View {
 		pages:         pages,
 		drawCallbacks: make([]func(), 0),
 		Done:          make(chan error),
+		ChatInput:     make(chan ChatMessage, chatInputBuffer),
+		VoteInput:     make(chan int, voteInputBuffer),
+		voteChoice:    -1,
+		streaks:       make(map[uuid.UUID]int),
 	}
 
+	setupChat(view)
 	setupViewPort(view)
 	setupScoreModal(view)
 	setupRoundWaitModal(view)
+	setupFeed(view)
 
 	app.SetInputCapture(func(e *tcell.EventKey) *tcell.EventKey {
-		if e.Rune() == 'p' {
+		if e.Rune() == 'p' && !view.isTyping() {
 			pages.ShowPage("score")
 		}
+		// Number keys vote for the next map while a round is over.
+		if e.Rune() >= '1' && e.Rune() <= '9' && !view.isTyping() {
+			view.vote(int(e.Rune() - '1'))
+		}
 		switch e.Key() {
 		case tcell.KeyEsc:
 			pages.HidePage("score")
+			view.chatInput.SetText("")
 			app.SetFocus(view.viewPort)
 
 		case tcell.KeyCtrlQ:
@@ -338,7 +529,7 @@ func NewView(game *backend.Game) *View {
 		}
 		return e
 	})
-	app.SetRoot(pages, true)
+	app.SetRoot(pages, true).SetFocus(view.viewPort)
 	return view
 }
 
@@ -0,0 +1,95 @@
+package frontend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+)
+
+const voteInputBuffer = 4
+
+// MapCandidate is a map players can vote for while a round is over, with the
+// votes it got so far.
+type MapCandidate struct {
+	Map   string
+	Mode  backend.GameMode
+	Votes int
+}
+
+// SetMapVote shows the candidates for the next map in the round wait modal.
+// The tallies change with every vote, the player's own vote is kept as long
+// as the candidates stay the same.
+func (view *View) SetMapVote(candidates []MapCandidate) {
+	view.voteMu.Lock()
+	defer view.voteMu.Unlock()
+
+	if !sameCandidates(view.voteCandidates, candidates) {
+		view.voteChoice = -1
+	}
+	view.voteCandidates = candidates
+}
+
+// ClearMapVote hides the vote once the next round started.
+func (view *View) ClearMapVote() {
+	view.voteMu.Lock()
+	defer view.voteMu.Unlock()
+
+	view.voteCandidates = nil
+	view.voteChoice = -1
+}
+
+func sameCandidates(a []MapCandidate, b []MapCandidate) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Map != b[i].Map || a[i].Mode != b[i].Mode {
+			return false
+		}
+	}
+	return true
+}
+
+// vote sends the player's vote for the candidate at choice.
+func (view *View) vote(choice int) {
+	view.voteMu.Lock()
+	if view.spectator || choice < 0 || choice >= len(view.voteCandidates) {
+		view.voteMu.Unlock()
+		return
+	}
+	view.voteChoice = choice
+	view.voteMu.Unlock()
+
+	select {
+	case view.VoteInput <- choice:
+	default:
+	}
+}
+
+// mapVoteText lists the candidates with their votes, empty if there is no
+// vote.
+func (view *View) mapVoteText() string {
+	view.voteMu.Lock()
+	defer view.voteMu.Unlock()
+
+	if len(view.voteCandidates) == 0 {
+		return ""
+	}
+	lines := []string{"Next map:"}
+	for i, candidate := range view.voteCandidates {
+		marker := " "
+		if i == view.voteChoice {
+			marker = ">"
+		}
+		votes := "votes"
+		if candidate.Votes == 1 {
+			votes = "vote"
+		}
+		lines = append(lines, fmt.Sprintf("%s %d. %s (%s) - %d %s", marker, i+1, candidate.Map, candidate.Mode, candidate.Votes, votes))
+	}
+	if !view.spectator {
+		lines = append(lines, "", fmt.Sprintf("press 1-%d to vote", len(view.voteCandidates)))
+	}
+	return strings.Join(lines, "\n")
+}
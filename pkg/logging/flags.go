@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"flag"
+	"io"
+	"os"
+)
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// Options are what the logging flags of a command ask for.
+type Options struct {
+	Level  string
+	Format string
+	File   string
+}
+
+// RegisterFlags adds -log-level, -log-format and -log-file to flagSet. Commands
+// that draw a terminal UI pass a defaultFile, so the log does not end up on
+// the screen.
+func RegisterFlags(flagSet *flag.FlagSet, defaultFile string) *Options {
+	options := &Options{}
+	flagSet.StringVar(&options.Level, "log-level", "info", "Minimum level of log records, debug, info, warn or error")
+	flagSet.StringVar(&options.Format, "log-format", "text", "Format of log records, text or json")
+	flagSet.StringVar(&options.File, "log-file", defaultFile, "File to append log records to, standard error if empty")
+	return options
+}
+
+// Setup configures logging from the options. The returned closer closes the
+// log file.
+func (options *Options) Setup() (io.Closer, error) {
+	level, err := ParseLevel(options.Level)
+	if err != nil {
+		return nil, err
+	}
+	format, err := ParseFormat(options.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.File == "" {
+		Configure(os.Stderr, level, format)
+		return nopCloser{}, nil
+	}
+
+	file, err := os.OpenFile(options.File, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	Configure(file, level, format)
+	return file, nil
+}
@@ -0,0 +1,281 @@
+// Package logging writes leveled log records with key value fields as text or
+// JSON lines.
+//
+//	logger := logging.New("room", roomID)
+//	logger.Info("player joined", "player", playerID)
+//
+// All loggers write to one output that main sets up with Configure, usually
+// from the -log-level, -log-format and -log-file flags of RegisterFlags.
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (level Level) String() string {
+	switch level {
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return "info"
+}
+
+func ParseLevel(name string) (Level, error) {
+	for _, level := range []Level{LevelDebug, LevelInfo, LevelWarn, LevelError} {
+		if strings.EqualFold(name, level.String()) {
+			return level, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q, use debug, info, warn or error", name)
+}
+
+type Format int
+
+const (
+	FormatText Format = iota
+	FormatJSON
+)
+
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "text":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return FormatText, fmt.Errorf("unknown log format %q, use text or json", name)
+}
+
+type output struct {
+	mu     sync.Mutex
+	writer io.Writer
+	level  Level
+	format Format
+}
+
+var std = &output{writer: os.Stderr, level: LevelInfo, format: FormatText}
+
+// Configure sets where and how every logger writes.
+func Configure(writer io.Writer, level Level, format Format) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+
+	std.writer = writer
+	std.level = level
+	std.format = format
+}
+
+// Logger adds its fields to every record. It is safe for concurrent use.
+type Logger struct {
+	fields  []interface{}
+	sampler *sampler
+}
+
+// New returns a logger with fields, which are pairs of a string key and any
+// value.
+func New(fields ...interface{}) *Logger {
+	return &Logger{fields: fields}
+}
+
+// With returns a logger with more fields.
+func (logger *Logger) With(fields ...interface{}) *Logger {
+	combined := make([]interface{}, 0, len(logger.fields)+len(fields))
+	combined = append(combined, logger.fields...)
+	combined = append(combined, fields...)
+	return &Logger{fields: combined, sampler: logger.sampler}
+}
+
+// sampler counts the records of every message to only let every nth through.
+type sampler struct {
+	every  uint64
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+func (s *sampler) keep(message string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := s.counts[message]
+	s.counts[message] = count + 1
+	return count%s.every == 0
+}
+
+// Sample returns a logger for high frequency events that only writes the
+// first of every n records with the same message. The records carry the
+// rate as the sampled field.
+func (logger *Logger) Sample(n int) *Logger {
+	if n <= 1 {
+		return logger
+	}
+	sampled := logger.With("sampled", n)
+	sampled.sampler = &sampler{every: uint64(n), counts: make(map[string]uint64)}
+	return sampled
+}
+
+// Enabled tells whether records of level are written, to skip building
+// expensive fields.
+func (logger *Logger) Enabled(level Level) bool {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+
+	return level >= std.level
+}
+
+func (logger *Logger) Debug(message string, fields ...interface{}) {
+	logger.log(LevelDebug, message, fields)
+}
+
+func (logger *Logger) Info(message string, fields ...interface{}) {
+	logger.log(LevelInfo, message, fields)
+}
+
+func (logger *Logger) Warn(message string, fields ...interface{}) {
+	logger.log(LevelWarn, message, fields)
+}
+
+func (logger *Logger) Error(message string, fields ...interface{}) {
+	logger.log(LevelError, message, fields)
+}
+
+func (logger *Logger) log(level Level, message string, fields []interface{}) {
+	if !logger.Enabled(level) {
+		return
+	}
+	if logger.sampler != nil && !logger.sampler.keep(message) {
+		return
+	}
+
+	all := make([]interface{}, 0, len(logger.fields)+len(fields))
+	all = append(all, logger.fields...)
+	all = append(all, fields...)
+	keys, values := pairs(all)
+
+	std.mu.Lock()
+	defer std.mu.Unlock()
+
+	now := time.Now()
+	var line []byte
+	if std.format == FormatJSON {
+		line = formatJSON(now, level, message, keys, values)
+	} else {
+		line = formatText(now, level, message, keys, values)
+	}
+	std.writer.Write(line)
+}
+
+// pairs splits fields into keys and values, a key without a value is kept
+// under "!BADKEY" like a value without a key.
+func pairs(fields []interface{}) ([]string, []interface{}) {
+	keys := make([]string, 0, len(fields)/2)
+	values := make([]interface{}, 0, len(fields)/2)
+	for i := 0; i < len(fields); i += 2 {
+		key, ok := fields[i].(string)
+		if !ok || i+1 == len(fields) {
+			keys = append(keys, "!BADKEY")
+			values = append(values, fields[i])
+			i--
+			continue
+		}
+		keys = append(keys, key)
+		values = append(values, fields[i+1])
+	}
+	return keys, values
+}
+
+// plain turns values that do not print well on their own into strings.
+func plain(value interface{}) interface{} {
+	switch value := value.(type) {
+	case nil, string, bool, int, int32, int64, uint, uint32, uint64, float32, float64:
+		return value
+	case error:
+		return value.Error()
+	case time.Duration:
+		return value.String()
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return value.String()
+	}
+	return fmt.Sprintf("%+v", value)
+}
+
+func formatText(now time.Time, level Level, message string, keys []string, values []interface{}) []byte {
+	builder := strings.Builder{}
+	builder.WriteString(now.Format("2006-01-02T15:04:05.000Z07:00"))
+	builder.WriteString(" ")
+	builder.WriteString(strings.ToUpper(level.String()))
+	builder.WriteString(" ")
+	builder.WriteString(message)
+	for i, key := range keys {
+		text := fmt.Sprint(plain(values[i]))
+		if text == "" || strings.ContainsAny(text, " \"=\n\t") {
+			text = strconv.Quote(text)
+		}
+		builder.WriteString(" ")
+		builder.WriteString(key)
+		builder.WriteString("=")
+		builder.WriteString(text)
+	}
+	builder.WriteString("\n")
+	return []byte(builder.String())
+}
+
+func formatJSON(now time.Time, level Level, message string, keys []string, values []interface{}) []byte {
+	record := map[string]interface{}{}
+	for i, key := range keys {
+		record[key] = plain(values[i])
+	}
+	record["time"] = now.Format(time.RFC3339Nano)
+	record["level"] = level.String()
+	record["msg"] = message
+
+	// time, level and msg go first so the lines are easy to read.
+	names := make([]string, 0, len(record))
+	for name := range record {
+		if name != "time" && name != "level" && name != "msg" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	names = append([]string{"time", "level", "msg"}, names...)
+
+	builder := strings.Builder{}
+	builder.WriteString("{")
+	for i, name := range names {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		key, _ := json.Marshal(name)
+		value, err := json.Marshal(record[name])
+		if err != nil {
+			value, _ = json.Marshal(fmt.Sprint(record[name]))
+		}
+		builder.Write(key)
+		builder.WriteString(":")
+		builder.Write(value)
+	}
+	builder.WriteString("}\n")
+	return []byte(builder.String())
+}
@@ -0,0 +1,273 @@
+// Package metrics keeps counters, gauges and histograms and serves them in
+// the Prometheus text format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Default is the registry the server metrics are kept in.
+var Default = NewRegistry()
+
+type metric interface {
+	write(writer *bufio.Writer)
+}
+
+// Registry is a set of metrics, it is safe for concurrent use.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+	names   map[string]bool
+}
+
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+func (registry *Registry) register(name string, metric metric) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.names[name] {
+		panic(fmt.Sprintf("metric %s is registered twice", name))
+	}
+	registry.names[name] = true
+	registry.metrics = append(registry.metrics, metric)
+}
+
+// ServeHTTP writes all metrics in the Prometheus text format.
+func (registry *Registry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	registry.mu.Lock()
+	metrics := make([]metric, len(registry.metrics))
+	copy(metrics, registry.metrics)
+	registry.mu.Unlock()
+
+	writer := bufio.NewWriter(w)
+	for _, metric := range metrics {
+		metric.write(writer)
+	}
+	writer.Flush()
+}
+
+// series is what all metrics have in common, a name and one value per
+// combination of label values.
+type series struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (s *series) key(values []string) string {
+	if len(values) != len(s.labels) {
+		panic(fmt.Sprintf("metric %s has labels %v, got values %v", s.name, s.labels, values))
+	}
+	return strings.Join(values, "\xff")
+}
+
+func (s *series) writeHeader(writer *bufio.Writer) {
+	help := strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s.help)
+	fmt.Fprintf(writer, "# HELP %s %s\n", s.name, help)
+	fmt.Fprintf(writer, "# TYPE %s %s\n", s.name, s.kind)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelText formats the labels of a key and extra labels like le as
+// {a="1",b="2"}.
+func (s *series) labelText(key string, extra ...string) string {
+	pairs := []string{}
+	if len(s.labels) > 0 {
+		for i, value := range strings.Split(key, "\xff") {
+			pairs = append(pairs, fmt.Sprintf(`%s="%s"`, s.labels[i], labelValueReplacer.Replace(value)))
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extra[i], labelValueReplacer.Replace(extra[i+1])))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// value is a counter or a gauge.
+type value struct {
+	series
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (v *value) add(delta float64, labelValues []string) {
+	key := v.key(labelValues)
+	v.mu.Lock()
+	v.values[key] += delta
+	v.mu.Unlock()
+}
+
+func (v *value) write(writer *bufio.Writer) {
+	v.writeHeader(writer)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.values) == 0 && len(v.labels) == 0 {
+		fmt.Fprintf(writer, "%s 0\n", v.name)
+		return
+	}
+	for _, key := range sortedKeys(v.values) {
+		fmt.Fprintf(writer, "%s%s %s\n", v.name, v.labelText(key), formatValue(v.values[key]))
+	}
+}
+
+// Counter only goes up.
+type Counter struct {
+	value
+}
+
+func (registry *Registry) NewCounter(name string, help string, labels ...string) *Counter {
+	counter := &Counter{value{
+		series: series{name: name, help: help, kind: "counter", labels: labels},
+		values: make(map[string]float64),
+	}}
+	registry.register(name, counter)
+	return counter
+}
+
+// Inc adds one to the counter with the given label values.
+func (counter *Counter) Inc(labelValues ...string) {
+	counter.add(1, labelValues)
+}
+
+func (counter *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("counter %s can not go down", counter.name))
+	}
+	counter.add(delta, labelValues)
+}
+
+// Gauge goes up and down.
+type Gauge struct {
+	value
+}
+
+func (registry *Registry) NewGauge(name string, help string, labels ...string) *Gauge {
+	gauge := &Gauge{value{
+		series: series{name: name, help: help, kind: "gauge", labels: labels},
+		values: make(map[string]float64),
+	}}
+	registry.register(name, gauge)
+	return gauge
+}
+
+func (gauge *Gauge) Add(delta float64, labelValues ...string) {
+	gauge.add(delta, labelValues)
+}
+
+func (gauge *Gauge) Set(value float64, labelValues ...string) {
+	key := gauge.key(labelValues)
+	gauge.mu.Lock()
+	gauge.values[key] = value
+	gauge.mu.Unlock()
+}
+
+// DefaultBuckets suit durations in seconds from 100µs to 2.5s.
+var DefaultBuckets = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+type histogramValue struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// Histogram counts observations into buckets.
+type Histogram struct {
+	series
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogramValue
+}
+
+// NewHistogram takes the upper bounds of the buckets in increasing order,
+// the +Inf bucket is added automatically.
+func (registry *Registry) NewHistogram(name string, help string, buckets []float64, labels ...string) *Histogram {
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("buckets of histogram %s are not sorted", name))
+	}
+	histogram := &Histogram{
+		series:  series{name: name, help: help, kind: "histogram", labels: labels},
+		buckets: buckets,
+		values:  make(map[string]*histogramValue),
+	}
+	registry.register(name, histogram)
+	return histogram
+}
+
+func (histogram *Histogram) Observe(observed float64, labelValues ...string) {
+	key := histogram.key(labelValues)
+
+	histogram.mu.Lock()
+	defer histogram.mu.Unlock()
+
+	value, ok := histogram.values[key]
+	if !ok {
+		value = &histogramValue{counts: make([]uint64, len(histogram.buckets))}
+		histogram.values[key] = value
+	}
+	for i, bound := range histogram.buckets {
+		if observed <= bound {
+			value.counts[i]++
+		}
+	}
+	value.sum += observed
+	value.count++
+}
+
+func (histogram *Histogram) write(writer *bufio.Writer) {
+	histogram.writeHeader(writer)
+
+	histogram.mu.Lock()
+	defer histogram.mu.Unlock()
+
+	keys := make([]string, 0, len(histogram.values))
+	for key := range histogram.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := histogram.values[key]
+		for i, bound := range histogram.buckets {
+			fmt.Fprintf(writer, "%s_bucket%s %d\n", histogram.name, histogram.labelText(key, "le", formatValue(bound)), value.counts[i])
+		}
+		fmt.Fprintf(writer, "%s_bucket%s %d\n", histogram.name, histogram.labelText(key, "le", "+Inf"), value.count)
+		fmt.Fprintf(writer, "%s_sum%s %s\n", histogram.name, histogram.labelText(key), formatValue(value.sum))
+		fmt.Fprintf(writer, "%s_count%s %d\n", histogram.name, histogram.labelText(key), value.count)
+	}
+}
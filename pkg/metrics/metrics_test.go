@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func expose(t *testing.T, registry *Registry) string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	if got := recorder.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("got content type %q", got)
+	}
+	return recorder.Body.String()
+}
+
+func assertExposition(t *testing.T, got string, want string) {
+	t.Helper()
+
+	want = strings.TrimLeft(want, "\n")
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCounterAndGauge(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounter("requests_total", "Requests by path.\nSecond \\ line.", "path", "method")
+	players := registry.NewGauge("players", "Players online.")
+	registry.NewCounter("unused_total", "Never counted.", "type")
+
+	requests.Inc("/b", "GET")
+	requests.Add(2, "/a", "GET")
+	requests.Inc(`C:\x "quoted"`+"\nnext", "POST")
+	players.Set(3)
+	players.Add(-1)
+
+	assertExposition(t, expose(t, registry), `
+# HELP requests_total Requests by path.\nSecond \\ line.
+# TYPE requests_total counter
+requests_total{path="/a",method="GET"} 2
+requests_total{path="/b",method="GET"} 1
+requests_total{path="C:\\x \"quoted\"\nnext",method="POST"} 1
+# HELP players Players online.
+# TYPE players gauge
+players 2
+# HELP unused_total Never counted.
+# TYPE unused_total counter
+`)
+}
+
+func TestHistogram(t *testing.T) {
+	registry := NewRegistry()
+	latency := registry.NewHistogram("latency_seconds", "Latency.", []float64{0.1, 0.5, 1}, "room")
+
+	for _, observed := range []float64{0.05, 0.1, 0.3, 2} {
+		latency.Observe(observed, "main")
+	}
+	latency.Observe(math.Inf(1), "other")
+
+	assertExposition(t, expose(t, registry), `
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{room="main",le="0.1"} 2
+latency_seconds_bucket{room="main",le="0.5"} 3
+latency_seconds_bucket{room="main",le="1"} 3
+latency_seconds_bucket{room="main",le="+Inf"} 4
+latency_seconds_sum{room="main"} 2.45
+latency_seconds_count{room="main"} 4
+latency_seconds_bucket{room="other",le="0.1"} 0
+latency_seconds_bucket{room="other",le="0.5"} 0
+latency_seconds_bucket{room="other",le="1"} 0
+latency_seconds_bucket{room="other",le="+Inf"} 1
+latency_seconds_sum{room="other"} +Inf
+latency_seconds_count{room="other"} 1
+`)
+}
+
+func TestRegistryPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(registry *Registry)
+	}{
+		{"registered twice", func(registry *Registry) {
+			registry.NewGauge("players", "Players.")
+			registry.NewCounter("players", "Players.")
+		}},
+		{"unsorted buckets", func(registry *Registry) {
+			registry.NewHistogram("latency_seconds", "Latency.", []float64{1, 0.5})
+		}},
+		{"missing label", func(registry *Registry) {
+			registry.NewCounter("requests_total", "Requests.", "path").Inc()
+		}},
+		{"counter going down", func(registry *Registry) {
+			registry.NewCounter("requests_total", "Requests.").Add(-1)
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("did not panic")
+				}
+			}()
+			test.run(NewRegistry())
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package rating
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	InitialRating = 1500.0
+	// kFactor is how far a single match against one opponent can move a rating.
+	kFactor = 32.0
+)
+
+type Rating struct {
+	PlayerID uuid.UUID `json:"playerId"`
+	Name     string    `json:"name"`
+	Rating   float64   `json:"rating"`
+	Matches  int       `json:"matches"`
+	Updated  time.Time `json:"updated"`
+}
+
+type Result struct {
+	PlayerID uuid.UUID
+	Name     string
+	Score    int
+}
+
+// Ratings keeps an Elo rating per player. An empty path keeps the ratings in
+// memory only.
+type Ratings struct {
+	path     string
+	mu       sync.RWMutex
+	byPlayer map[uuid.UUID]*Rating
+}
+
+func NewRatings(path string) (*Ratings, error) {
+	ratings := &Ratings{
+		path:     path,
+		byPlayer: make(map[uuid.UUID]*Rating),
+	}
+	if path == "" {
+		return ratings, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ratings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*Rating, 0)
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	for _, rating := range list {
+		ratings.byPlayer[rating.PlayerID] = rating
+	}
+	return ratings, nil
+}
+
+func (ratings *Ratings) save() error {
+	if ratings.path == "" {
+		return nil
+	}
+
+	list := make([]*Rating, 0, len(ratings.byPlayer))
+	for _, rating := range ratings.byPlayer {
+		list = append(list, rating)
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(ratings.path), ".ratings-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), ratings.path)
+}
+
+func (ratings *Ratings) Get(playerID uuid.UUID) float64 {
+	ratings.mu.RLock()
+	defer ratings.mu.RUnlock()
+
+	rating, ok := ratings.byPlayer[playerID]
+	if !ok {
+		return InitialRating
+	}
+	return rating.Rating
+}
+
+// expectedScore is the chance of a player rated a beating a player rated b.
+func expectedScore(a float64, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// Record updates the ratings from one finished match. Every player is scored
+// against every other player by comparing their kills, so a free for all
+// counts as a round of one on one matches, each weighted down by the number
+// of opponents.
+func (ratings *Ratings) Record(results []Result) error {
+	if len(results) < 2 {
+		return nil
+	}
+
+	ratings.mu.Lock()
+	defer ratings.mu.Unlock()
+
+	current := make([]float64, len(results))
+	for i, result := range results {
+		current[i] = InitialRating
+		if rating, ok := ratings.byPlayer[result.PlayerID]; ok {
+			current[i] = rating.Rating
+		}
+	}
+
+	k := kFactor / float64(len(results)-1)
+	now := time.Now()
+	for i, result := range results {
+		delta := 0.0
+		for j, opponent := range results {
+			if i == j {
+				continue
+			}
+			actual := 0.5
+			if result.Score > opponent.Score {
+				actual = 1
+			} else if result.Score < opponent.Score {
+				actual = 0
+			}
+			delta += k * (actual - expectedScore(current[i], current[j]))
+		}
+
+		rating, ok := ratings.byPlayer[result.PlayerID]
+		if !ok {
+			rating = &Rating{PlayerID: result.PlayerID, Rating: InitialRating}
+			ratings.byPlayer[result.PlayerID] = rating
+		}
+		rating.Name = result.Name
+		rating.Rating = current[i] + delta
+		rating.Matches++
+		rating.Updated = now
+	}
+
+	return ratings.save()
+}
@@ -0,0 +1,94 @@
+package rating
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func assertRating(t *testing.T, ratings *Ratings, playerID uuid.UUID, want float64) {
+	t.Helper()
+
+	if got := ratings.Get(playerID); math.Abs(got-want) > 0.01 {
+		t.Errorf("got rating %.2f, want %.2f", got, want)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	ratings, err := NewRatings("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	assertRating(t, ratings, alice, InitialRating)
+
+	// A player alone is not rated.
+	if err := ratings.Record([]Result{{PlayerID: alice, Score: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	assertRating(t, ratings, alice, InitialRating)
+
+	// Equal players split the expected score, the winner takes half of k.
+	if err := ratings.Record([]Result{{PlayerID: alice, Score: 3}, {PlayerID: bob, Score: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	assertRating(t, ratings, alice, InitialRating+kFactor/2)
+	assertRating(t, ratings, bob, InitialRating-kFactor/2)
+
+	// The favourite gains less for winning again, what one gains the other
+	// loses.
+	if err := ratings.Record([]Result{{PlayerID: alice, Score: 2}, {PlayerID: bob, Score: 0}}); err != nil {
+		t.Fatal(err)
+	}
+	gain := kFactor * (1 - expectedScore(InitialRating+kFactor/2, InitialRating-kFactor/2))
+	assertRating(t, ratings, alice, InitialRating+kFactor/2+gain)
+	assertRating(t, ratings, bob, InitialRating-kFactor/2-gain)
+
+	// A draw between equals changes nothing.
+	dave := uuid.New()
+	if err := ratings.Record([]Result{
+		{PlayerID: carol, Score: 1},
+		{PlayerID: dave, Score: 1},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	assertRating(t, ratings, carol, InitialRating)
+	assertRating(t, ratings, dave, InitialRating)
+
+	// In a match of four every opponent counts a third of k.
+	eve, frank := uuid.New(), uuid.New()
+	if err := ratings.Record([]Result{
+		{PlayerID: carol, Score: 5},
+		{PlayerID: dave, Score: 1},
+		{PlayerID: eve, Score: 1},
+		{PlayerID: frank, Score: 1},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	assertRating(t, ratings, carol, InitialRating+kFactor/2)
+	assertRating(t, ratings, dave, InitialRating-kFactor/6)
+}
+
+func TestRatingsReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratings.json")
+	ratings, err := NewRatings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, bob := uuid.New(), uuid.New()
+	if err := ratings.Record([]Result{{PlayerID: alice, Name: "Alice", Score: 1}, {PlayerID: bob, Name: "Bob"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewRatings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertRating(t, reloaded, alice, InitialRating+kFactor/2)
+	assertRating(t, reloaded, bob, InitialRating-kFactor/2)
+	if got := reloaded.byPlayer[alice]; got.Name != "Alice" || got.Matches != 1 {
+		t.Errorf("got %+v after the reload, want Alice with 1 match", got)
+	}
+}
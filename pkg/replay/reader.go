@@ -0,0 +1,88 @@
+package replay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	protobuf "google.golang.org/protobuf/proto"
+
+	proto "github.com/nikit34/multiplayer_rpg/proto"
+)
+
+// maxMessageSize guards against reading a corrupt length prefix.
+const maxMessageSize = 16 << 20
+
+// Reader reads a replay file written by a Recorder.
+type Reader struct {
+	Header     *proto.ReplayHeader
+	file       *os.File
+	compressed *gzip.Reader
+	reader     *bufio.Reader
+}
+
+func Open(path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	reader := &Reader{
+		Header:     &proto.ReplayHeader{},
+		file:       file,
+		compressed: compressed,
+		reader:     bufio.NewReader(compressed),
+	}
+	if err := reader.readMessage(reader.Header); err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("can not read replay header: %w", err)
+	}
+	if reader.Header.Version != Version {
+		reader.Close()
+		return nil, fmt.Errorf("unsupported replay version %d", reader.Header.Version)
+	}
+	return reader, nil
+}
+
+// Next returns the next frame, or io.EOF after the last one.
+func (reader *Reader) Next() (*proto.ReplayFrame, error) {
+	frame := &proto.ReplayFrame{}
+	if err := reader.readMessage(frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+func (reader *Reader) readMessage(message protobuf.Message) error {
+	size, err := binary.ReadUvarint(reader.reader)
+	if err != nil {
+		return err
+	}
+	if size > maxMessageSize {
+		return errors.New("replay message is too large")
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(reader.reader, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return protobuf.Unmarshal(data, message)
+}
+
+func (reader *Reader) Close() error {
+	reader.compressed.Close()
+	return reader.file.Close()
+}
@@ -0,0 +1,293 @@
+package replay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
+	proto "github.com/nikit34/multiplayer_rpg/proto"
+)
+
+// Version is bumped whenever the layout of a replay file changes.
+const Version = 1
+
+// frameBuffer is how many frames may wait for the disk before new ones are
+// dropped, recording never holds up the game.
+const frameBuffer = 1024
+
+// MapHash identifies a map so a viewer can tell whether it has the same one.
+func MapHash(rows []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(rows, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Recorder writes the changes of a game to a replay file.
+//
+// A replay file is a gzip stream of length prefixed messages, a
+// proto.ReplayHeader followed by proto.ReplayFrame messages. The frames are
+// compressed into a temporary file while the match is running, Close then
+// writes the header, which knows the players and the duration, as a first gzip
+// member and appends the frames.
+//
+// A Recorder is not safe for concurrent use.
+type Recorder struct {
+	game    *backend.Game
+	path    string
+	tmp     *os.File
+	header  *proto.ReplayHeader
+	started time.Time
+	last    time.Time
+	players map[string]bool
+	frames  chan *proto.ReplayFrame
+	written chan error
+	dropped int
+}
+
+// NewRecorder starts recording game to path with the current map and entities
+// as the initial state.
+func NewRecorder(path string, game *backend.Game) (*Recorder, error) {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".replay-*")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	recorder := &Recorder{
+		game:    game,
+		path:    path,
+		tmp:     tmp,
+		started: now,
+		last:    now,
+		players: make(map[string]bool),
+		frames:  make(chan *proto.ReplayFrame, frameBuffer),
+		written: make(chan error, 1),
+	}
+
+	game.Mu.RLock()
+	rows := backend.MapRows(game.GetMap())
+	recorder.header = &proto.ReplayHeader{
+		Version: Version,
+		MapHash: MapHash(rows),
+		Map:     rows,
+		Mode:    proto.GetProtoGameMode(game.Mode),
+		Started: timestamppb.New(now),
+		Rules:   proto.GetProtoRules(game.Rules()),
+	}
+	for _, entity := range game.Entities {
+		recorder.header.Entities = append(recorder.header.Entities, proto.GetProtoEntity(entity))
+		if player, ok := entity.(*backend.Player); ok {
+			recorder.addPlayer(player.ID().String(), player.Name)
+		}
+	}
+	game.Mu.RUnlock()
+
+	go recorder.writeFrames()
+	return recorder, nil
+}
+
+func (recorder *Recorder) addPlayer(id string, name string) {
+	if recorder.players[id] {
+		return
+	}
+	recorder.players[id] = true
+	recorder.header.Players = append(recorder.header.Players, &proto.ReplayPlayer{
+		Id:   id,
+		Name: name,
+	})
+}
+
+// Record adds a change to the replay. Changes without a client visible effect
+// are skipped.
+func (recorder *Recorder) Record(change backend.Change) {
+	resp := recorder.response(change)
+	if resp == nil {
+		return
+	}
+	recorder.RecordResponse(resp)
+}
+
+// RecordResponse adds a message that is sent to clients outside of the change
+// stream, like players joining.
+func (recorder *Recorder) RecordResponse(resp *proto.Response) {
+	if joined := resp.GetPlayerJoined(); joined != nil {
+		recorder.addPlayer(joined.Player.Id, joined.Player.Name)
+	}
+
+	now := time.Now()
+	recorder.last = now
+	frame := &proto.ReplayFrame{
+		OffsetMs: now.Sub(recorder.started).Milliseconds(),
+		Response: resp,
+	}
+	select {
+	case recorder.frames <- frame:
+	default:
+		recorder.dropped++
+	}
+}
+
+func (recorder *Recorder) response(change backend.Change) *proto.Response {
+	switch change := change.(type) {
+	case backend.MoveChange:
+		return &proto.Response{
+			Action: &proto.Response_UpdateEntity{
+				UpdateEntity: &proto.UpdateEntity{Entity: proto.GetProtoEntity(change.Entity)},
+			},
+		}
+	case backend.AddEntityChange:
+		if player, ok := change.Entity.(*backend.Player); ok {
+			recorder.addPlayer(player.ID().String(), player.Name)
+		}
+		return &proto.Response{
+			Action: &proto.Response_AddEntity{
+				AddEntity: &proto.AddEntity{Entity: proto.GetProtoEntity(change.Entity)},
+			},
+		}
+	case backend.RemoveEntityChange:
+		return &proto.Response{
+			Action: &proto.Response_RemoveEntity{
+				RemoveEntity: &proto.RemoveEntity{Id: change.Entity.ID().String()},
+			},
+		}
+	case backend.PlayerRespawnChange:
+		return &proto.Response{
+			Action: &proto.Response_PlayerRespawn{
+				PlayerRespawn: &proto.PlayerRespawn{
+					Player:     proto.GetProtoPlayer(change.Player),
+					KilledById: change.KilledByID.String(),
+				},
+			},
+		}
+	case backend.RoundOverChange:
+		recorder.game.Mu.RLock()
+		defer recorder.game.Mu.RUnlock()
+		newRoundAt, err := ptypes.TimestampProto(recorder.game.NewRoundAt)
+		if err != nil {
+			return nil
+		}
+		return &proto.Response{
+			Action: &proto.Response_RoundOver{
+				RoundOver: &proto.RoundOver{
+					RoundWinnerId: recorder.game.RoundWinner.String(),
+					NewRoundAt:    newRoundAt,
+				},
+			},
+		}
+	case backend.RoundStartChange:
+		recorder.game.Mu.RLock()
+		defer recorder.game.Mu.RUnlock()
+		players := []*proto.Player{}
+		for _, entity := range recorder.game.Entities {
+			if player, ok := entity.(*backend.Player); ok {
+				players = append(players, proto.GetProtoPlayer(player))
+			}
+		}
+		return &proto.Response{
+			Action: &proto.Response_RoundStart{
+				RoundStart: &proto.RoundStart{
+					Players: players,
+					Map:     backend.MapRows(recorder.game.GetMap()),
+					Mode:    proto.GetProtoGameMode(recorder.game.Mode),
+					Rules:   proto.GetProtoRules(recorder.game.Rules()),
+				},
+			},
+		}
+	}
+	return nil
+}
+
+func (recorder *Recorder) writeFrames() {
+	compressed := gzip.NewWriter(recorder.tmp)
+	writer := bufio.NewWriter(compressed)
+	var err error
+	for frame := range recorder.frames {
+		if err == nil {
+			err = writeMessage(writer, frame)
+		}
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := compressed.Close(); err == nil {
+		err = closeErr
+	}
+	recorder.written <- err
+}
+
+// Close finishes the replay file. The temporary file is removed if the replay
+// could not be written.
+func (recorder *Recorder) Close() error {
+	close(recorder.frames)
+	err := <-recorder.written
+	if err == nil {
+		err = recorder.finish()
+	}
+	recorder.tmp.Close()
+	os.Remove(recorder.tmp.Name())
+
+	if recorder.dropped > 0 {
+		logging.New().Warn("replay dropped frames", "path", recorder.path, "dropped", recorder.dropped)
+	}
+	return err
+}
+
+func (recorder *Recorder) finish() error {
+	recorder.header.DurationMs = recorder.last.Sub(recorder.started).Milliseconds()
+
+	file, err := ioutil.TempFile(filepath.Dir(recorder.path), ".replay-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	compressed := gzip.NewWriter(file)
+	if err := writeMessage(compressed, recorder.header); err != nil {
+		return err
+	}
+	if err := compressed.Close(); err != nil {
+		return err
+	}
+
+	if _, err := recorder.tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, recorder.tmp); err != nil {
+		return err
+	}
+	if err := file.Chmod(0644); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), recorder.path)
+}
+
+func writeMessage(writer io.Writer, message protobuf.Message) error {
+	data, err := protobuf.Marshal(message)
+	if err != nil {
+		return err
+	}
+	size := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(size, uint64(len(data)))
+	if _, err := writer.Write(size[:n]); err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
@@ -0,0 +1,170 @@
+package replay
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	proto "github.com/nikit34/multiplayer_rpg/proto"
+)
+
+var testMap = []string{
+	"██████",
+	"█S  S█",
+	"██████",
+}
+
+func newPlayer(name string, x int) *backend.Player {
+	return &backend.Player{
+		Name:            name,
+		Icon:            'p',
+		IdentifierBase:  backend.IdentifierBase{UUID: uuid.New()},
+		CurrentPosition: backend.Coordinate{X: x},
+	}
+}
+
+// record writes a replay of Alice, who is there from the start, Bob, who is
+// added while recording, and Carol, who joins as a message.
+func record(t *testing.T, path string) (alice *backend.Player, bob *backend.Player, carol *backend.Player) {
+	t.Helper()
+
+	game := backend.NewGame()
+	game.SetMap(backend.ParseMap(testMap))
+	game.Mode = backend.ModeTeamDeathmatch
+	alice = newPlayer("Alice", -1)
+	game.AddEntity(alice)
+
+	recorder, err := NewRecorder(path, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob = newPlayer("Bob", 1)
+	recorder.Record(backend.AddEntityChange{Entity: bob})
+	time.Sleep(20 * time.Millisecond)
+	recorder.Record(backend.MoveChange{Entity: alice, Direction: backend.DirectionRight})
+	carol = newPlayer("Carol", 0)
+	recorder.RecordResponse(&proto.Response{
+		Action: &proto.Response_PlayerJoined{
+			PlayerJoined: &proto.PlayerJoined{Player: proto.GetProtoPlayer(carol)},
+		},
+	})
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return alice, bob, carol
+}
+
+// readAll reads every frame of the replay at path.
+func readAll(path string) (*proto.ReplayHeader, []*proto.ReplayFrame, error) {
+	reader, err := Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer reader.Close()
+
+	frames := make([]*proto.ReplayFrame, 0)
+	for {
+		frame, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return reader.Header, frames, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		frames = append(frames, frame)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "match.replay")
+	alice, bob, carol := record(t, path)
+
+	header, frames, err := readAll(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if header.Version != Version {
+		t.Errorf("got version %d, want %d", header.Version, Version)
+	}
+	if header.MapHash != MapHash(testMap) || len(header.Map) != len(testMap) {
+		t.Errorf("got map %q with hash %s, want %q", header.Map, header.MapHash, testMap)
+	}
+	if header.Mode != proto.GameMode_TEAM_DEATHMATCH {
+		t.Errorf("got mode %v, want %v", header.Mode, proto.GameMode_TEAM_DEATHMATCH)
+	}
+	if header.DurationMs < 20 {
+		t.Errorf("got duration %dms, want at least 20ms", header.DurationMs)
+	}
+	if len(header.Entities) != 1 || header.Entities[0].GetPlayer().GetId() != alice.ID().String() {
+		t.Errorf("got initial entities %v, want only Alice", header.Entities)
+	}
+	wantPlayers := []*backend.Player{alice, bob, carol}
+	if len(header.Players) != len(wantPlayers) {
+		t.Fatalf("got players %v, want Alice, Bob and Carol", header.Players)
+	}
+	for i, player := range wantPlayers {
+		if header.Players[i].Id != player.ID().String() || header.Players[i].Name != player.Name {
+			t.Errorf("got player %v, want %s %s", header.Players[i], player.ID(), player.Name)
+		}
+	}
+
+	if len(frames) != 3 {
+		t.Fatalf("got %d frames, want 3", len(frames))
+	}
+	if frames[0].Response.GetAddEntity().GetEntity().GetPlayer().GetId() != bob.ID().String() {
+		t.Errorf("got first frame %v, want Bob added", frames[0].Response)
+	}
+	if frames[1].Response.GetUpdateEntity().GetEntity().GetPlayer().GetId() != alice.ID().String() {
+		t.Errorf("got second frame %v, want Alice moved", frames[1].Response)
+	}
+	if frames[2].Response.GetPlayerJoined().GetPlayer().GetId() != carol.ID().String() {
+		t.Errorf("got third frame %v, want Carol joined", frames[2].Response)
+	}
+	for i := 1; i < len(frames); i++ {
+		if frames[i].OffsetMs < frames[i-1].OffsetMs {
+			t.Errorf("frame %d at %dms comes before frame %d at %dms", i, frames[i].OffsetMs, i-1, frames[i-1].OffsetMs)
+		}
+	}
+	if frames[1].OffsetMs < 20 {
+		t.Errorf("got the move at %dms, want it after 20ms", frames[1].OffsetMs)
+	}
+}
+
+func TestTruncated(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "match.replay")
+	record(t, path)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The header and the frames are separate gzip members, a file cut right
+	// after the header is a valid replay without frames. Every other cut has
+	// to be an error.
+	truncated := filepath.Join(dir, "truncated.replay")
+	valid := 0
+	for size := 0; size < len(data); size++ {
+		if err := ioutil.WriteFile(truncated, data[:size], 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, frames, err := readAll(truncated)
+		if err != nil {
+			continue
+		}
+		valid++
+		if len(frames) > 0 {
+			t.Errorf("read %d frames of a replay cut to %d of %d bytes without an error", len(frames), size, len(data))
+		}
+	}
+	if valid > 1 {
+		t.Errorf("read %d cut replays without an error, want at most the one cut after the header", valid)
+	}
+}
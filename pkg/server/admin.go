@@ -0,0 +1,469 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	proto "github.com/nikit34/multiplayer_rpg/proto"
+)
+
+// AdminMethods are the calls of the Admin service. They do not take a player
+// token, so they have to be left out of the token interceptors and checked
+// by the admin interceptor instead.
+var AdminMethods = adminMethods()
+
+func adminMethods() []string {
+	methods := make([]string, 0, len(proto.Admin_ServiceDesc.Methods))
+	for _, method := range proto.Admin_ServiceDesc.Methods {
+		methods = append(methods, "/"+proto.Admin_ServiceDesc.ServiceName+"/"+method.MethodName)
+	}
+	return methods
+}
+
+// UnaryAdminInterceptor lets calls of the Admin service through only if they
+// carry the admin token in the authorization header. Other calls pass.
+func UnaryAdminInterceptor(token string) grpc.UnaryServerInterceptor {
+	admin := make(map[string]bool)
+	for _, method := range AdminMethods {
+		admin[method] = true
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !admin[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		headers, _ := metadata.FromIncomingContext(ctx)
+		provided := headers["authorization"]
+		if token == "" || len(provided) == 0 || subtle.ConstantTimeCompare([]byte(provided[0]), []byte(token)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid admin token")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AdminServer lets operators moderate a running server.
+type AdminServer struct {
+	proto.UnimplementedAdminServer
+	server *GameServer
+}
+
+func NewAdminServer(server *GameServer) *AdminServer {
+	return &AdminServer{server: server}
+}
+
+func (a *AdminServer) ListPlayers(ctx context.Context, req *proto.ListPlayersRequest) (*proto.ListPlayersResponse, error) {
+	players := make([]*proto.PlayerInfo, 0)
+	for _, room := range a.server.getRooms() {
+		players = append(players, room.players()...)
+	}
+	return &proto.ListPlayersResponse{
+		Players: players,
+	}, nil
+}
+
+func (a *AdminServer) Kick(ctx context.Context, req *proto.KickRequest) (*proto.KickResponse, error) {
+	room, currentClient, ok := a.server.findClient(req.Player)
+	if !ok {
+		return nil, fmt.Errorf("no player %q is connected", req.Player)
+	}
+
+	message := "you have been kicked"
+	if req.Reason != "" {
+		message += ": " + req.Reason
+	}
+	room.kick(currentClient.playerID, errors.New(message))
+	currentClient.logger.Info("kicked player", "reason", req.Reason)
+	return &proto.KickResponse{}, nil
+}
+
+func (a *AdminServer) Ban(ctx context.Context, req *proto.BanRequest) (*proto.BanResponse, error) {
+	ban := Ban{
+		Account: req.Account,
+		Reason:  req.Reason,
+		Created: time.Now(),
+	}
+	if req.Ip != "" {
+		network, err := ParseNetwork(req.Ip)
+		if err != nil {
+			return nil, err
+		}
+		ban.Network = network
+	}
+	if ban.Account == "" && ban.Network == nil {
+		return nil, errors.New("an account or an IP address is required")
+	}
+	if req.Duration != nil {
+		duration, err := ptypes.Duration(req.Duration)
+		if err != nil {
+			return nil, err
+		}
+		if duration <= 0 {
+			return nil, errors.New("the duration must be positive")
+		}
+		ban.Expires = ban.Created.Add(duration)
+	}
+	if err := a.server.bans.Add(ban); err != nil {
+		return nil, fmt.Errorf("can not save ban: %w", err)
+	}
+
+	kicked := a.server.kickBanned()
+	logger.Info("banned", "account", req.Account, "ip", req.Ip, "reason", req.Reason, "expires", ban.Expires, "kicked", kicked)
+	return &proto.BanResponse{
+		Kicked: int32(kicked),
+	}, nil
+}
+
+func (a *AdminServer) Unban(ctx context.Context, req *proto.UnbanRequest) (*proto.UnbanResponse, error) {
+	var network *net.IPNet
+	if req.Ip != "" {
+		parsed, err := ParseNetwork(req.Ip)
+		if err != nil {
+			return nil, err
+		}
+		network = parsed
+	}
+	if req.Account == "" && network == nil {
+		return nil, errors.New("an account or an IP address is required")
+	}
+
+	removed, err := a.server.bans.Remove(req.Account, network)
+	if err != nil {
+		return nil, fmt.Errorf("can not save bans: %w", err)
+	}
+	logger.Info("unbanned", "account", req.Account, "ip", req.Ip, "removed", removed)
+	return &proto.UnbanResponse{
+		Removed: int32(removed),
+	}, nil
+}
+
+func (a *AdminServer) ListBans(ctx context.Context, req *proto.ListBansRequest) (*proto.ListBansResponse, error) {
+	bans := make([]*proto.BanInfo, 0)
+	for _, ban := range a.server.bans.List() {
+		created, err := ptypes.TimestampProto(ban.Created)
+		if err != nil {
+			return nil, err
+		}
+		info := &proto.BanInfo{
+			Account: ban.Account,
+			Reason:  ban.Reason,
+			Created: created,
+		}
+		if ban.Network != nil {
+			info.Ip = ban.Network.String()
+		}
+		if !ban.Expires.IsZero() {
+			info.Expires, err = ptypes.TimestampProto(ban.Expires)
+			if err != nil {
+				return nil, err
+			}
+		}
+		bans = append(bans, info)
+	}
+	return &proto.ListBansResponse{
+		Bans: bans,
+	}, nil
+}
+
+// Mute also takes the ID of a player that is not connected, so a player can
+// be muted before they come back.
+func (a *AdminServer) Mute(ctx context.Context, req *proto.MuteRequest) (*proto.MuteResponse, error) {
+	playerID, err := uuid.Parse(req.Player)
+	if err != nil {
+		_, currentClient, ok := a.server.findClient(req.Player)
+		if !ok {
+			return nil, fmt.Errorf("no player %q is connected", req.Player)
+		}
+		playerID = currentClient.playerID
+	}
+
+	a.server.setMuted(playerID, req.Muted)
+	logger.Info("muted player", "player", playerID, "muted", req.Muted)
+	return &proto.MuteResponse{}, nil
+}
+
+func (a *AdminServer) findRoom(name string) (*Room, error) {
+	room, ok := a.server.findRoom(name)
+	if !ok {
+		return nil, fmt.Errorf("room %q does not exist", name)
+	}
+	return room, nil
+}
+
+func (a *AdminServer) ChangeMap(ctx context.Context, req *proto.ChangeMapRequest) (*proto.ChangeMapResponse, error) {
+	room, err := a.findRoom(req.Room)
+	if err != nil {
+		return nil, err
+	}
+
+	room.settingsMu.RLock()
+	mapName := room.settings.Map
+	room.settingsMu.RUnlock()
+	if req.Map != "" {
+		mapName = req.Map
+	}
+	mode := room.getMode()
+	if req.Mode != "" {
+		mode, err = backend.ParseGameMode(req.Mode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := room.changeMap(mapName, mode); err != nil {
+		return nil, err
+	}
+	return &proto.ChangeMapResponse{
+		Room: room.info(),
+	}, nil
+}
+
+func (a *AdminServer) EndRound(ctx context.Context, req *proto.EndRoundRequest) (*proto.EndRoundResponse, error) {
+	room, err := a.findRoom(req.Room)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Restart {
+		room.game.RestartRound()
+	} else {
+		room.game.EndRound()
+	}
+	room.logger.Info("ended round", "restart", req.Restart)
+	return &proto.EndRoundResponse{}, nil
+}
+
+func (a *AdminServer) AddBot(ctx context.Context, req *proto.AddBotRequest) (*proto.AddBotResponse, error) {
+	room, err := a.findRoom(req.Room)
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := room.addBot(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.AddBotResponse{
+		Bot: &proto.PlayerInfo{
+			Id:       player.ID().String(),
+			Name:     player.Name,
+			RoomId:   room.id.String(),
+			RoomName: room.settings.Name,
+			Bot:      true,
+			Team:     int32(player.Team),
+		},
+	}, nil
+}
+
+func (a *AdminServer) RemoveBot(ctx context.Context, req *proto.RemoveBotRequest) (*proto.RemoveBotResponse, error) {
+	room, err := a.findRoom(req.Room)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := room.removeBot(req.Name); err != nil {
+		return nil, err
+	}
+	return &proto.RemoveBotResponse{}, nil
+}
+
+// Broadcast sends a system message to one room, or to all of them if no room
+// is given.
+func (a *AdminServer) Broadcast(ctx context.Context, req *proto.BroadcastRequest) (*proto.BroadcastResponse, error) {
+	text := sanitizeChat(req.Text)
+	if text == "" {
+		return nil, errors.New("the message is empty")
+	}
+	if utf8.RuneCountInString(text) > maxChatLength {
+		return nil, errors.New("the message is too long")
+	}
+
+	rooms := a.server.getRooms()
+	if req.Room != "" {
+		room, err := a.findRoom(req.Room)
+		if err != nil {
+			return nil, err
+		}
+		rooms = []*Room{room}
+	}
+
+	for _, room := range rooms {
+		room.broadcast(newChatResponse(&proto.ChatMessage{
+			Channel:    proto.ChatChannel_SYSTEM,
+			SenderName: "server",
+			Text:       text,
+		}))
+	}
+	logger.Info("broadcast message", "rooms", len(rooms), "text", text)
+	return &proto.BroadcastResponse{
+		Rooms: int32(len(rooms)),
+	}, nil
+}
+
+// players describes the clients and bots of the room.
+func (room *Room) players() []*proto.PlayerInfo {
+	// The bots are listed before locking the game, they lock it themselves.
+	botIDs := room.bots.PlayerIDs()
+
+	room.mu.RLock()
+	infos := make([]*proto.PlayerInfo, 0, len(room.clients)+len(botIDs))
+	for _, currentClient := range room.clients {
+		infos = append(infos, &proto.PlayerInfo{
+			Id:            currentClient.playerID.String(),
+			Name:          currentClient.name,
+			RoomId:        room.id.String(),
+			RoomName:      room.settings.Name,
+			Address:       currentClient.address,
+			LatencyMillis: int32(currentClient.getLatency() / time.Millisecond),
+			Spectator:     currentClient.spectator,
+			Muted:         room.server.isMuted(currentClient.playerID),
+		})
+	}
+	room.mu.RUnlock()
+
+	room.game.Mu.RLock()
+	defer room.game.Mu.RUnlock()
+
+	for _, info := range infos {
+		playerID := uuid.MustParse(info.Id)
+		info.Score = int32(room.game.Score[playerID])
+		if player, ok := room.game.GetEntity(playerID).(*backend.Player); ok {
+			info.Team = int32(player.Team)
+		}
+	}
+	for _, botID := range botIDs {
+		player, ok := room.game.GetEntity(botID).(*backend.Player)
+		if !ok {
+			continue
+		}
+		infos = append(infos, &proto.PlayerInfo{
+			Id:       player.ID().String(),
+			Name:     player.Name,
+			RoomId:   room.id.String(),
+			RoomName: room.settings.Name,
+			Bot:      true,
+			Team:     int32(player.Team),
+			Score:    int32(room.game.Score[player.ID()]),
+		})
+	}
+	return infos
+}
+
+// kick ends the stream of a client with err. A client that has not opened
+// its stream yet is removed right away.
+func (room *Room) kick(playerID uuid.UUID, err error) {
+	room.mu.RLock()
+	currentClient, ok := room.clients[playerID]
+	streaming := ok && currentClient.streamServer != nil
+	room.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	currentClient.stop(err)
+	if !streaming {
+		room.server.leaveRoom(room, playerID)
+	}
+}
+
+func (room *Room) changeMap(mapName string, mode backend.GameMode) error {
+	gameMap, err := backend.GetMap(mapName)
+	if err != nil {
+		return err
+	}
+
+	room.settingsMu.Lock()
+	room.settings.Map = mapName
+	room.settings.Mode = mode
+	// The map an admin picks wins over the rotation.
+	room.queuedMap = nil
+	room.settingsMu.Unlock()
+
+	room.game.ChangeMap(gameMap, mode)
+	room.bots.MapChanged()
+	room.logger.Info("changed map", "map", mapName, "mode", mode)
+	return nil
+}
+
+func (room *Room) addBot(name string) (*backend.Player, error) {
+	room.settingsMu.Lock()
+	if room.bots.Count() >= room.server.getMaxRoomBots() {
+		room.settingsMu.Unlock()
+		return nil, fmt.Errorf("a room can have at most %d bots", room.server.getMaxRoomBots())
+	}
+	// Removed bots leave gaps, so the first free name is taken.
+	for i := room.bots.Count(); name == ""; i++ {
+		name = fmt.Sprintf("Bob %d", i)
+		if room.findPlayerByName(name) != nil {
+			name = ""
+		}
+	}
+	if !validRoomName.MatchString(name) {
+		room.settingsMu.Unlock()
+		return nil, errors.New("invalid bot name provided")
+	}
+	if room.findPlayerByName(name) != nil {
+		room.settingsMu.Unlock()
+		return nil, fmt.Errorf("the room already has a player named %q", name)
+	}
+	player := room.bots.AddBot(name)
+	room.settings.Bots = room.bots.Count()
+	room.settingsMu.Unlock()
+
+	room.announceJoined(player)
+	room.logger.Info("added bot", "player", player.ID(), "name", name)
+	return player, nil
+}
+
+func (room *Room) removeBot(name string) error {
+	player := room.findPlayerByName(name)
+	if player == nil || !room.bots.IsBot(player.ID()) {
+		return fmt.Errorf("the room has no bot named %q", name)
+	}
+
+	room.settingsMu.Lock()
+	room.bots.RemoveBot(player.ID())
+	room.settings.Bots = room.bots.Count()
+	room.settingsMu.Unlock()
+
+	room.announceLeft(player.ID(), player.Name)
+	room.logger.Info("removed bot", "player", player.ID(), "name", player.Name)
+	return nil
+}
+
+// setBots adds or removes bots until the room has count of them.
+func (room *Room) setBots(count int) error {
+	for room.bots.Count() < count {
+		if _, err := room.addBot(""); err != nil {
+			return err
+		}
+	}
+	for _, playerID := range room.bots.PlayerIDs() {
+		if room.bots.Count() <= count {
+			break
+		}
+		room.game.Mu.RLock()
+		player, ok := room.game.GetEntity(playerID).(*backend.Player)
+		room.game.Mu.RUnlock()
+		if !ok {
+			continue
+		}
+		if err := room.removeBot(player.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
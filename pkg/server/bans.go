@@ -0,0 +1,278 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ban keeps a player out by account name, by IP range or by both.
+type Ban struct {
+	// Account is compared to the player name on servers without accounts,
+	// where anyone can pick any name.
+	Account string
+	// Network is a single address for plain IP bans.
+	Network *net.IPNet
+	Reason  string
+	Created time.Time
+	// Expires is zero for permanent bans.
+	Expires time.Time
+}
+
+// banRecord is how a ban is stored in the ban file.
+type banRecord struct {
+	Account string     `json:"account,omitempty"`
+	Address string     `json:"address,omitempty"`
+	Reason  string     `json:"reason,omitempty"`
+	Created time.Time  `json:"created"`
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// ParseNetwork parses an IP address or a CIDR range.
+func ParseNetwork(address string) (*net.IPNet, error) {
+	if strings.Contains(address, "/") {
+		_, network, err := net.ParseCIDR(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP range %q", address)
+		}
+		return network, nil
+	}
+
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", address)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+func (ban Ban) expired(now time.Time) bool {
+	return !ban.Expires.IsZero() && !now.Before(ban.Expires)
+}
+
+func (ban Ban) matches(account string, ip net.IP) bool {
+	if ban.Account != "" && strings.EqualFold(ban.Account, account) {
+		return true
+	}
+	return ban.Network != nil && ip != nil && ban.Network.Contains(ip)
+}
+
+// Message is what the banned player is told.
+func (ban Ban) Message() string {
+	message := "you are banned from this server"
+	if ban.Reason != "" {
+		message += ": " + ban.Reason
+	}
+	if !ban.Expires.IsZero() {
+		message += fmt.Sprintf(" (until %s)", ban.Expires.UTC().Format(time.RFC3339))
+	}
+	return message
+}
+
+// Bans is the list of bans of a server, it is safe for concurrent use. An
+// empty path keeps the bans in memory only, otherwise every change is saved
+// and edits of the file are picked up by Reload.
+type Bans struct {
+	path    string
+	mu      sync.RWMutex
+	bans    []Ban
+	modTime time.Time
+}
+
+func NewBans(path string) (*Bans, error) {
+	bans := &Bans{
+		path: path,
+	}
+	if path == "" {
+		return bans, nil
+	}
+
+	if _, err := bans.Reload(); err != nil {
+		return nil, err
+	}
+	return bans, nil
+}
+
+// Reload reads the ban file again if it changed since it was last read or
+// written, and reports whether it did.
+func (bans *Bans) Reload() (bool, error) {
+	if bans.path == "" {
+		return false, nil
+	}
+
+	info, err := os.Stat(bans.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	bans.mu.Lock()
+	defer bans.mu.Unlock()
+
+	if info.ModTime().Equal(bans.modTime) {
+		return false, nil
+	}
+
+	data, err := ioutil.ReadFile(bans.path)
+	if err != nil {
+		return false, err
+	}
+	records := make([]banRecord, 0)
+	if err := json.Unmarshal(data, &records); err != nil {
+		return false, fmt.Errorf("can not parse %s: %w", bans.path, err)
+	}
+
+	list := make([]Ban, 0, len(records))
+	for _, record := range records {
+		ban := Ban{
+			Account: record.Account,
+			Reason:  record.Reason,
+			Created: record.Created,
+		}
+		if record.Address != "" {
+			ban.Network, err = ParseNetwork(record.Address)
+			if err != nil {
+				return false, fmt.Errorf("can not parse %s: %w", bans.path, err)
+			}
+		}
+		if record.Expires != nil {
+			ban.Expires = *record.Expires
+		}
+		if ban.Account == "" && ban.Network == nil {
+			return false, fmt.Errorf("can not parse %s: a ban needs an account or an address", bans.path)
+		}
+		list = append(list, ban)
+	}
+
+	bans.bans = list
+	bans.modTime = info.ModTime()
+	return true, nil
+}
+
+// save must be called with bans.mu held. Expired bans are dropped from the
+// file.
+func (bans *Bans) save() error {
+	if bans.path == "" {
+		return nil
+	}
+
+	now := time.Now()
+	records := make([]banRecord, 0, len(bans.bans))
+	for _, ban := range bans.bans {
+		if ban.expired(now) {
+			continue
+		}
+		record := banRecord{
+			Account: ban.Account,
+			Reason:  ban.Reason,
+			Created: ban.Created,
+		}
+		if ban.Network != nil {
+			record.Address = ban.Network.String()
+		}
+		if !ban.Expires.IsZero() {
+			expires := ban.Expires
+			record.Expires = &expires
+		}
+		records = append(records, record)
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(bans.path), ".bans-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), bans.path); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(bans.path)
+	if err != nil {
+		return err
+	}
+	bans.modTime = info.ModTime()
+	return nil
+}
+
+func (bans *Bans) Add(ban Ban) error {
+	bans.mu.Lock()
+	defer bans.mu.Unlock()
+
+	bans.bans = append(bans.bans, ban)
+	return bans.save()
+}
+
+// Remove lifts the bans of an account and of an address or range, either may
+// be empty. It returns how many bans were lifted.
+func (bans *Bans) Remove(account string, network *net.IPNet) (int, error) {
+	bans.mu.Lock()
+	defer bans.mu.Unlock()
+
+	kept := make([]Ban, 0, len(bans.bans))
+	for _, ban := range bans.bans {
+		accountMatches := account == "" || strings.EqualFold(ban.Account, account)
+		networkMatches := network == nil || (ban.Network != nil && ban.Network.String() == network.String())
+		if !accountMatches || !networkMatches {
+			kept = append(kept, ban)
+		}
+	}
+	removed := len(bans.bans) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	bans.bans = kept
+	return removed, bans.save()
+}
+
+// List returns the bans that have not expired.
+func (bans *Bans) List() []Ban {
+	bans.mu.RLock()
+	defer bans.mu.RUnlock()
+
+	now := time.Now()
+	list := make([]Ban, 0, len(bans.bans))
+	for _, ban := range bans.bans {
+		if !ban.expired(now) {
+			list = append(list, ban)
+		}
+	}
+	return list
+}
+
+// Find returns the ban of an account or IP address, either may be empty.
+func (bans *Bans) Find(account string, ip net.IP) (Ban, bool) {
+	bans.mu.RLock()
+	defer bans.mu.RUnlock()
+
+	now := time.Now()
+	for _, ban := range bans.bans {
+		if !ban.expired(now) && ban.matches(account, ip) {
+			return ban, true
+		}
+	}
+	return Ban{}, false
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBanFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bans")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "bans.json")
+
+	bans, err := NewBans(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	network, err := ParseNetwork("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bans.Add(Ban{Network: network, Reason: "spam", Created: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	expires := time.Now().Add(time.Hour)
+	if err := bans.Add(Ban{Account: "Alice", Created: time.Now(), Expires: expires}); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := NewBans(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ban, ok := loaded.Find("", net.ParseIP("10.1.2.3")); !ok || ban.Reason != "spam" {
+		t.Fatalf("got %+v, %v for an address in the banned range", ban, ok)
+	}
+	if _, ok := loaded.Find("", net.ParseIP("11.1.2.3")); ok {
+		t.Fatal("address outside the banned range is banned")
+	}
+	if ban, ok := loaded.Find("alice", nil); !ok || !ban.Expires.Equal(expires) {
+		t.Fatalf("got %+v, %v for the banned account", ban, ok)
+	}
+
+	// Edits of the file are picked up by a reload.
+	data := []byte(`[{"address": "192.168.1.7", "reason": "edited"}]`)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := loaded.Reload()
+	if err != nil || !changed {
+		t.Fatalf("got %v, %v reloading an edited file", changed, err)
+	}
+	if _, ok := loaded.Find("alice", net.ParseIP("10.1.2.3")); ok {
+		t.Fatal("bans removed from the file still apply")
+	}
+	if ban, ok := loaded.Find("", net.ParseIP("192.168.1.7")); !ok || ban.Reason != "edited" {
+		t.Fatalf("got %+v, %v for an address added to the file", ban, ok)
+	}
+	if changed, err := loaded.Reload(); err != nil || changed {
+		t.Fatalf("got %v, %v reloading an unchanged file", changed, err)
+	}
+
+	single, err := ParseNetwork("192.168.1.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := loaded.Remove("", single); err != nil || removed != 1 {
+		t.Fatalf("got %d, %v removing the edited ban", removed, err)
+	}
+	if _, ok := loaded.Find("", net.ParseIP("192.168.1.7")); ok {
+		t.Fatal("removed ban still applies")
+	}
+}
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	proto "github.com/nikit34/multiplayer_rpg/proto"
+)
+
+const (
+	maxChatLength  = 200
+	chatRateLimit  = 5
+	chatRateWindow = 10 * time.Second
+)
+
+type ChatFilter struct {
+	patterns []*regexp.Regexp
+}
+
+// LoadChatFilter reads one blocked word per line, ignoring blank lines and
+// lines starting with #.
+func LoadChatFilter(path string) (*ChatFilter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	filter := &ChatFilter{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		filter.patterns = append(filter.patterns, regexp.MustCompile("(?i)"+regexp.QuoteMeta(word)))
+	}
+	return filter, scanner.Err()
+}
+
+func (filter *ChatFilter) Filter(text string) string {
+	if filter == nil {
+		return text
+	}
+	for _, pattern := range filter.patterns {
+		text = pattern.ReplaceAllStringFunc(text, func(match string) string {
+			return strings.Repeat("*", utf8.RuneCountInString(match))
+		})
+	}
+	return text
+}
+
+func (s *GameServer) SetChatFilter(filter *ChatFilter) {
+	s.chatFilter = filter
+}
+
+func sanitizeChat(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+	return strings.TrimSpace(text)
+}
+
+// allowChat keeps the send times of the last chatRateLimit messages and
+// rejects a message if the oldest of them is still within chatRateWindow.
+func (c *client) allowChat(now time.Time) bool {
+	if len(c.chatTimes) >= chatRateLimit && now.Sub(c.chatTimes[0]) < chatRateWindow {
+		return false
+	}
+	c.chatTimes = append(c.chatTimes, now)
+	if len(c.chatTimes) > chatRateLimit {
+		c.chatTimes = c.chatTimes[1:]
+	}
+	return true
+}
+
+func newChatResponse(message *proto.ChatMessage) *proto.Response {
+	message.SentAt = ptypes.TimestampNow()
+	return &proto.Response{
+		Action: &proto.Response_ChatMessage{
+			ChatMessage: message,
+		},
+	}
+}
+
+func (room *Room) sendSystemMessage(playerID uuid.UUID, text string) {
+	room.sendToPlayers(newChatResponse(&proto.ChatMessage{
+		Channel: proto.ChatChannel_SYSTEM,
+		Text:    text,
+	}), playerID)
+}
+
+func (room *Room) getPlayer(playerID uuid.UUID) *backend.Player {
+	room.game.Mu.RLock()
+	defer room.game.Mu.RUnlock()
+
+	player, ok := room.game.GetEntity(playerID).(*backend.Player)
+	if !ok {
+		return nil
+	}
+	return player
+}
+
+// findClientByName finds a connected player or spectator, bots have no
+// client.
+func (room *Room) findClientByName(name string) *client {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	for _, currentClient := range room.clients {
+		if strings.EqualFold(currentClient.name, name) {
+			return currentClient
+		}
+	}
+	return nil
+}
+
+func (room *Room) findPlayerByName(name string) *backend.Player {
+	room.game.Mu.RLock()
+	defer room.game.Mu.RUnlock()
+
+	for _, entity := range room.game.Entities {
+		player, ok := entity.(*backend.Player)
+		if ok && strings.EqualFold(player.Name, name) {
+			return player
+		}
+	}
+	return nil
+}
+
+func (room *Room) getTeamPlayerIDs(team int) []uuid.UUID {
+	room.game.Mu.RLock()
+	defer room.game.Mu.RUnlock()
+
+	ids := make([]uuid.UUID, 0)
+	for _, entity := range room.game.Entities {
+		player, ok := entity.(*backend.Player)
+		if ok && player.Team == team {
+			ids = append(ids, player.ID())
+		}
+	}
+	return ids
+}
+
+func (room *Room) handleChatRequest(req *proto.Request, currentClient *client) {
+	chat := req.GetChat()
+
+	sender := room.getPlayer(currentClient.playerID)
+	if sender == nil && currentClient.spectator {
+		// Spectators have no entity, they chat under their name without a team.
+		sender = &backend.Player{
+			Name:           currentClient.name,
+			IdentifierBase: backend.IdentifierBase{UUID: currentClient.playerID},
+		}
+	}
+	if sender == nil {
+		return
+	}
+	if room.server.isMuted(currentClient.playerID) {
+		room.sendSystemMessage(sender.ID(), "you have been muted")
+		return
+	}
+
+	text := sanitizeChat(chat.Text)
+	if text == "" {
+		return
+	}
+	if utf8.RuneCountInString(text) > maxChatLength {
+		room.sendSystemMessage(sender.ID(), "message is too long")
+		return
+	}
+	if !currentClient.allowChat(time.Now()) {
+		room.sendSystemMessage(sender.ID(), "you are sending messages too quickly")
+		return
+	}
+
+	message := &proto.ChatMessage{
+		Channel:    chat.Channel,
+		SenderId:   sender.ID().String(),
+		SenderName: sender.Name,
+		Text:       room.server.chatFilter.Filter(text),
+	}
+
+	switch chat.Channel {
+	case proto.ChatChannel_GLOBAL:
+		room.broadcast(newChatResponse(message))
+
+	case proto.ChatChannel_TEAM:
+		if sender.Team == 0 {
+			room.sendSystemMessage(sender.ID(), "you are not on a team")
+			return
+		}
+		room.sendToPlayers(newChatResponse(message), room.getTeamPlayerIDs(sender.Team)...)
+
+	case proto.ChatChannel_WHISPER:
+		recipient := room.findClientByName(chat.Recipient)
+		if recipient == nil {
+			if bot := room.findPlayerByName(chat.Recipient); bot != nil {
+				room.sendSystemMessage(sender.ID(), bot.Name+" is a bot and cannot read whispers")
+				return
+			}
+			room.sendSystemMessage(sender.ID(), "no player named "+chat.Recipient)
+			return
+		}
+		message.RecipientName = recipient.name
+		room.sendToPlayers(newChatResponse(message), sender.ID(), recipient.playerID)
+	}
+}
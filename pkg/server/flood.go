@@ -0,0 +1,146 @@
+package server
+
+import (
+	"time"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+)
+
+// requestLimit is a token bucket: a client may send burst requests of a type
+// at once and rate more every second. The game throttles moves and lasers
+// itself, these limits only keep floods away from its action channel.
+type requestLimit struct {
+	rate  float64
+	burst float64
+}
+
+// requestLimits are keyed by messageType. The rates of moves and lasers
+// follow the throttles of the rules, see throttledLimit.
+var requestLimits = map[string]requestLimit{
+	"Move":  {burst: 10},
+	"Laser": {burst: 4},
+	"Chat":  {rate: 1, burst: 5},
+	"Pong":  {rate: 1, burst: 2},
+}
+
+var defaultRequestLimit = requestLimit{rate: 5, burst: 5}
+
+const (
+	// throttleSlack is how many more actions than the game accepts a client
+	// may send, key repeat and network jitter bunch them up.
+	throttleSlack = 2
+	// maxActionRate limits actions the rules do not throttle, or barely.
+	maxActionRate = 30
+)
+
+// throttledLimit sets the rate of limit for an action the game accepts once
+// every throttle.
+func throttledLimit(limit requestLimit, throttle time.Duration) requestLimit {
+	limit.rate = maxActionRate
+	if throttle > 0 {
+		if rate := throttleSlack / throttle.Seconds(); rate < maxActionRate {
+			limit.rate = rate
+		}
+	}
+	return limit
+}
+
+// A client whose requests keep being dropped is warned after floodWarnDrops
+// drops within floodWindow and disconnected after floodDisconnectDrops.
+const (
+	floodWindow          = 10 * time.Second
+	floodWarnDrops       = 10
+	floodDisconnectDrops = 100
+)
+
+type floodVerdict int
+
+const (
+	floodAllow floodVerdict = iota
+	floodDrop
+	floodWarn
+	floodDisconnect
+)
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (bucket *tokenBucket) take(limit requestLimit, now time.Time) bool {
+	if bucket.last.IsZero() {
+		bucket.tokens = limit.burst
+	} else {
+		bucket.tokens += now.Sub(bucket.last).Seconds() * limit.rate
+		if bucket.tokens > limit.burst {
+			bucket.tokens = limit.burst
+		}
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// requestLimiter is only used by the goroutine receiving the requests of one
+// stream, so it needs no lock.
+type requestLimiter struct {
+	// rules returns the current rules of the room, they can change between
+	// rounds.
+	rules       func() backend.Rules
+	buckets     map[string]*tokenBucket
+	windowStart time.Time
+	drops       int
+}
+
+func newRequestLimiter(rules func() backend.Rules) *requestLimiter {
+	return &requestLimiter{
+		rules:   rules,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+func (limiter *requestLimiter) limit(requestType string) requestLimit {
+	limit, ok := requestLimits[requestType]
+	if !ok {
+		return defaultRequestLimit
+	}
+	switch requestType {
+	case "Move":
+		return throttledLimit(limit, limiter.rules().MoveThrottle)
+	case "Laser":
+		return throttledLimit(limit, limiter.rules().LaserThrottle)
+	}
+	return limit
+}
+
+// check takes a token for a request and decides what to do with it. Only the
+// drop that crosses floodWarnDrops warns, so a flooding client is warned once
+// per window.
+func (limiter *requestLimiter) check(requestType string, now time.Time) floodVerdict {
+	limit := limiter.limit(requestType)
+	bucket, ok := limiter.buckets[requestType]
+	if !ok {
+		bucket = &tokenBucket{}
+		limiter.buckets[requestType] = bucket
+	}
+	if bucket.take(limit, now) {
+		return floodAllow
+	}
+
+	if now.Sub(limiter.windowStart) > floodWindow {
+		limiter.windowStart = now
+		limiter.drops = 0
+	}
+	limiter.drops++
+	switch {
+	case limiter.drops >= floodDisconnectDrops:
+		return floodDisconnect
+	case limiter.drops == floodWarnDrops:
+		return floodWarn
+	}
+	return floodDrop
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"github.com/google/uuid"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthMethods are the calls of the grpc.health.v1 service, probes call them
+// without a token.
+var HealthMethods = []string{
+	"/grpc.health.v1.Health/Check",
+	"/grpc.health.v1.Health/Watch",
+}
+
+// gameService is the health service name of the game, the empty name is the
+// overall status of the server.
+const gameService = "proto.Game"
+
+// RoomHealthService is the health service name of a room.
+func RoomHealthService(roomID uuid.UUID) string {
+	return "room/" + roomID.String()
+}
+
+// SetHealth makes the server report its status to healthServer: the server
+// and the game are serving until it shuts down or its default room closes,
+// every room is serving while it is open. It must be called before serving.
+func (s *GameServer) SetHealth(healthServer *health.Server) {
+	s.healthMu.Lock()
+	s.health = healthServer
+	s.healthMu.Unlock()
+
+	s.setHealth("", true)
+	s.setHealth(gameService, true)
+	for _, room := range s.getRooms() {
+		s.setHealth(RoomHealthService(room.id), true)
+	}
+}
+
+func (s *GameServer) getHealth() *health.Server {
+	s.healthMu.RLock()
+	defer s.healthMu.RUnlock()
+
+	return s.health
+}
+
+func (s *GameServer) setHealth(service string, serving bool) {
+	healthServer := s.getHealth()
+	if healthServer == nil {
+		return
+	}
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	healthServer.SetServingStatus(service, status)
+}
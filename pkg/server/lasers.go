@@ -0,0 +1,91 @@
+package server
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	proto "github.com/nikit34/multiplayer_rpg/proto"
+)
+
+// A laser request is pending until the game spawned or throttled it. The
+// game drops actions between rounds without telling anyone, so pending lasers
+// are forgotten after pendingLaserTimeout.
+const pendingLaserTimeout = 10 * time.Second
+
+type pendingLaser struct {
+	clientID  uuid.UUID
+	ownerID   uuid.UUID
+	requested time.Time
+}
+
+func (room *Room) addPendingLaser(laserID uuid.UUID, pending pendingLaser) {
+	room.lasersMu.Lock()
+	defer room.lasersMu.Unlock()
+
+	for id, other := range room.pendingLasers {
+		if pending.requested.Sub(other.requested) > pendingLaserTimeout {
+			delete(room.pendingLasers, id)
+		}
+	}
+	room.pendingLasers[laserID] = pending
+}
+
+func (room *Room) takePendingLaser(laserID uuid.UUID) (pendingLaser, bool) {
+	room.lasersMu.Lock()
+	defer room.lasersMu.Unlock()
+
+	pending, ok := room.pendingLasers[laserID]
+	delete(room.pendingLasers, laserID)
+	return pending, ok
+}
+
+// ackLaser tells the owner which laser the server spawned for its request.
+func (room *Room) ackLaser(pending pendingLaser, laser *backend.Laser) {
+	room.sendToPlayers(&proto.Response{
+		Action: &proto.Response_LaserAck{
+			LaserAck: &proto.LaserAck{
+				ClientId: pending.clientID.String(),
+				Laser:    proto.GetProtoLaser(laser),
+			},
+		},
+	}, pending.ownerID)
+}
+
+// rejectLaser tells the owner to remove its predicted laser. reason is shown
+// to the player, cause labels the metric.
+func (room *Room) rejectLaser(ownerID uuid.UUID, clientID uuid.UUID, cause string, reason string) {
+	lasersRejected.Inc(cause)
+	room.sendToPlayers(&proto.Response{
+		Action: &proto.Response_LaserAck{
+			LaserAck: &proto.LaserAck{
+				ClientId:       clientID.String(),
+				RejectedReason: reason,
+			},
+		},
+	}, ownerID)
+}
+
+// roomObserver adds the rejection of throttled laser requests to the metrics
+// of gameObserver.
+type roomObserver struct {
+	gameObserver
+	room *Room
+}
+
+func (observer roomObserver) ActionThrottled(action backend.Action) {
+	observer.gameObserver.ActionThrottled(action)
+
+	laser, ok := action.(backend.LaserAction)
+	if !ok {
+		return
+	}
+	pending, ok := observer.room.takePendingLaser(laser.ID)
+	if !ok {
+		return
+	}
+	// The game is locked while it calls the observer, so the rejection is
+	// sent without waiting for the clients.
+	go observer.room.rejectLaser(pending.ownerID, pending.clientID, "throttled", "you are shooting too quickly")
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const attemptWindow = time.Minute
+
+// connectLimits caps how many connections and connect attempts a single host
+// may have, zero means no limit. It keeps the connected clients of all rooms
+// itself, so connecting never waits for a room that is busy sending.
+type connectLimits struct {
+	mu             sync.Mutex
+	maxConnections int
+	maxAttempts    int
+	// attempts holds the times of the attempts of every host within the last
+	// attemptWindow.
+	attempts map[string][]time.Time
+	// hosts counts the connected clients of every host, reserved the
+	// clients that are still connecting.
+	hosts    map[string]int
+	reserved map[string]int
+	clients  map[uuid.UUID]connection
+}
+
+// connection is a client and the room it is connected to.
+type connection struct {
+	room   *Room
+	client *client
+}
+
+func newConnectLimits() *connectLimits {
+	return &connectLimits{
+		attempts: make(map[string][]time.Time),
+		hosts:    make(map[string]int),
+		reserved: make(map[string]int),
+		clients:  make(map[uuid.UUID]connection),
+	}
+}
+
+// connect counts a client that joined a room.
+func (limits *connectLimits) connect(room *Room, newClient *client) {
+	limits.mu.Lock()
+	defer limits.mu.Unlock()
+
+	limits.clients[newClient.playerID] = connection{room: room, client: newClient}
+	if newClient.host != "" {
+		limits.hosts[newClient.host]++
+	}
+}
+
+// disconnect forgets a client that left its room.
+func (limits *connectLimits) disconnect(oldClient *client) {
+	limits.mu.Lock()
+	defer limits.mu.Unlock()
+
+	if current, ok := limits.clients[oldClient.playerID]; !ok || current.client != oldClient {
+		return
+	}
+	delete(limits.clients, oldClient.playerID)
+	if oldClient.host == "" {
+		return
+	}
+	limits.hosts[oldClient.host]--
+	if limits.hosts[oldClient.host] <= 0 {
+		delete(limits.hosts, oldClient.host)
+	}
+}
+
+// reserve holds a connection of host for a client that is connecting, it
+// reports false if the host has no connection left. The check and the
+// reservation are one step, so concurrent connects can not both take the
+// last connection. The client calls release once it joined or failed to.
+func (limits *connectLimits) reserve(host string) bool {
+	limits.mu.Lock()
+	defer limits.mu.Unlock()
+
+	if limits.maxConnections > 0 && limits.hosts[host]+limits.reserved[host] >= limits.maxConnections {
+		return false
+	}
+	limits.reserved[host]++
+	return true
+}
+
+// release gives back a reservation, a client that joined is counted by
+// connect instead.
+func (limits *connectLimits) release(host string) {
+	limits.mu.Lock()
+	defer limits.mu.Unlock()
+
+	limits.reserved[host]--
+	if limits.reserved[host] <= 0 {
+		delete(limits.reserved, host)
+	}
+}
+
+// total counts the clients of all rooms.
+func (limits *connectLimits) total() int {
+	limits.mu.Lock()
+	defer limits.mu.Unlock()
+
+	return len(limits.clients)
+}
+
+// find looks for a connected client by player ID or name.
+func (limits *connectLimits) find(player string) (connection, bool) {
+	limits.mu.Lock()
+	defer limits.mu.Unlock()
+
+	for playerID, current := range limits.clients {
+		if playerID.String() == player || strings.EqualFold(current.client.name, player) {
+			return current, true
+		}
+	}
+	return connection{}, false
+}
+
+// list returns the connected clients of all rooms.
+func (limits *connectLimits) list() []connection {
+	limits.mu.Lock()
+	defer limits.mu.Unlock()
+
+	connections := make([]connection, 0, len(limits.clients))
+	for _, current := range limits.clients {
+		connections = append(connections, current)
+	}
+	return connections
+}
+
+func (limits *connectLimits) set(maxConnections int, maxAttempts int) {
+	limits.mu.Lock()
+	defer limits.mu.Unlock()
+
+	limits.maxConnections = maxConnections
+	limits.maxAttempts = maxAttempts
+}
+
+func (limits *connectLimits) getMaxConnections() int {
+	limits.mu.Lock()
+	defer limits.mu.Unlock()
+
+	return limits.maxConnections
+}
+
+// attempt records a connect attempt of a host and reports whether it is
+// within the limit. Attempts over the limit are not recorded, so retrying
+// does not push back when the host is let in again.
+func (limits *connectLimits) attempt(host string, now time.Time) bool {
+	limits.mu.Lock()
+	defer limits.mu.Unlock()
+
+	for otherHost, times := range limits.attempts {
+		recent := times[:0]
+		for _, attemptTime := range times {
+			if now.Sub(attemptTime) < attemptWindow {
+				recent = append(recent, attemptTime)
+			}
+		}
+		if len(recent) == 0 {
+			delete(limits.attempts, otherHost)
+			continue
+		}
+		limits.attempts[otherHost] = recent
+	}
+
+	if limits.maxAttempts > 0 && len(limits.attempts[host]) >= limits.maxAttempts {
+		return false
+	}
+	limits.attempts[host] = append(limits.attempts[host], now)
+	return true
+}
@@ -0,0 +1,261 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	proto "github.com/nikit34/multiplayer_rpg/proto"
+)
+
+const (
+	matchSize          = 4
+	matchTick          = time.Second
+	initialRatingRange = 100.0
+	// ratingRangeGrowth widens the acceptable rating gap per second waited.
+	ratingRangeGrowth = 25.0
+	maxRatingRange    = 1000.0
+	// backfillAfter is how long a player waits before the match is started
+	// with whoever is in range and bots for the empty slots.
+	backfillAfter = 30 * time.Second
+)
+
+type queueEntry struct {
+	playerID uuid.UUID
+	name     string
+	mode     backend.GameMode
+	rating   float64
+	enqueued time.Time
+	// status holds the latest update only, the matchmaker is its only writer.
+	status chan *proto.QueueStatus
+	left   bool
+}
+
+func (entry *queueEntry) update(status *proto.QueueStatus) {
+	select {
+	case <-entry.status:
+	default:
+	}
+	entry.status <- status
+}
+
+func ratingRange(waited time.Duration) float64 {
+	return math.Min(initialRatingRange+ratingRangeGrowth*waited.Seconds(), maxRatingRange)
+}
+
+// Matchmaker groups queued players of the same mode by rating and starts a
+// rated room for every group.
+type Matchmaker struct {
+	server *GameServer
+	mu     sync.Mutex
+	queue  []*queueEntry
+	// averageWait is a moving average of how long matched players waited.
+	averageWait map[backend.GameMode]time.Duration
+}
+
+func newMatchmaker(server *GameServer) *Matchmaker {
+	return &Matchmaker{
+		server:      server,
+		queue:       make([]*queueEntry, 0),
+		averageWait: make(map[backend.GameMode]time.Duration),
+	}
+}
+
+func (matchmaker *Matchmaker) enqueue(playerID uuid.UUID, name string, mode backend.GameMode, rating float64) (*queueEntry, error) {
+	matchmaker.mu.Lock()
+	defer matchmaker.mu.Unlock()
+
+	for _, entry := range matchmaker.queue {
+		if entry.playerID == playerID {
+			return nil, errors.New("player is already queued")
+		}
+	}
+
+	entry := &queueEntry{
+		playerID: playerID,
+		name:     name,
+		mode:     mode,
+		rating:   rating,
+		enqueued: time.Now(),
+		status:   make(chan *proto.QueueStatus, 1),
+	}
+	matchmaker.queue = append(matchmaker.queue, entry)
+	return entry, nil
+}
+
+func (matchmaker *Matchmaker) dequeue(entry *queueEntry) {
+	matchmaker.mu.Lock()
+	defer matchmaker.mu.Unlock()
+
+	entry.left = true
+	for i, queued := range matchmaker.queue {
+		if queued == entry {
+			matchmaker.queue = append(matchmaker.queue[:i], matchmaker.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// run matches the queue every matchTick until the server shuts down.
+func (matchmaker *Matchmaker) run() {
+	ticker := time.NewTicker(matchTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-matchmaker.server.shutdown:
+			return
+		case now := <-ticker.C:
+			for _, group := range matchmaker.match(now) {
+				matchmaker.startMatch(group)
+			}
+		}
+	}
+}
+
+// match takes the groups that are ready to play out of the queue, oldest
+// entries first, and sends a status update to everyone still waiting.
+func (matchmaker *Matchmaker) match(now time.Time) [][]*queueEntry {
+	matchmaker.mu.Lock()
+	defer matchmaker.mu.Unlock()
+
+	groups := make([][]*queueEntry, 0)
+	matched := make(map[*queueEntry]bool)
+
+	for _, entry := range matchmaker.queue {
+		if matched[entry] {
+			continue
+		}
+
+		waited := now.Sub(entry.enqueued)
+		maxGap := ratingRange(waited)
+		others := make([]*queueEntry, 0)
+		for _, other := range matchmaker.queue {
+			if other == entry || matched[other] || other.mode != entry.mode {
+				continue
+			}
+			if math.Abs(other.rating-entry.rating) <= maxGap {
+				others = append(others, other)
+			}
+		}
+		sort.SliceStable(others, func(i, j int) bool {
+			return math.Abs(others[i].rating-entry.rating) < math.Abs(others[j].rating-entry.rating)
+		})
+		candidates := append([]*queueEntry{entry}, others...)
+
+		if len(candidates) < matchSize && waited < backfillAfter {
+			continue
+		}
+		if len(candidates) > matchSize {
+			candidates = candidates[:matchSize]
+		}
+
+		for _, candidate := range candidates {
+			matched[candidate] = true
+			matchmaker.recordWait(candidate.mode, now.Sub(candidate.enqueued))
+		}
+		groups = append(groups, candidates)
+	}
+
+	waiting := make([]*queueEntry, 0, len(matchmaker.queue))
+	for _, entry := range matchmaker.queue {
+		if !matched[entry] {
+			waiting = append(waiting, entry)
+		}
+	}
+	matchmaker.queue = waiting
+
+	queued := make(map[backend.GameMode]int32)
+	for _, entry := range waiting {
+		queued[entry.mode]++
+	}
+	positions := make(map[backend.GameMode]int32)
+	for _, entry := range waiting {
+		positions[entry.mode]++
+		waited := now.Sub(entry.enqueued)
+		entry.update(&proto.QueueStatus{
+			State:                proto.QueueState_WAITING,
+			Position:             positions[entry.mode],
+			PlayersInQueue:       queued[entry.mode],
+			Rating:               int32(entry.rating),
+			RatingRange:          int32(ratingRange(waited)),
+			WaitedSeconds:        int32(waited.Seconds()),
+			EstimatedWaitSeconds: int32(matchmaker.estimateWait(entry.mode, waited).Seconds()),
+		})
+	}
+
+	return groups
+}
+
+func (matchmaker *Matchmaker) recordWait(mode backend.GameMode, waited time.Duration) {
+	average, ok := matchmaker.averageWait[mode]
+	if !ok {
+		matchmaker.averageWait[mode] = waited
+		return
+	}
+	matchmaker.averageWait[mode] = (average*7 + waited*3) / 10
+}
+
+// estimateWait falls back to the backfill time until a match was made, a
+// match is started with bots at the latest by then anyway.
+func (matchmaker *Matchmaker) estimateWait(mode backend.GameMode, waited time.Duration) time.Duration {
+	expected, ok := matchmaker.averageWait[mode]
+	if !ok || expected > backfillAfter {
+		expected = backfillAfter
+	}
+	if waited >= expected {
+		return 0
+	}
+	return expected - waited
+}
+
+func (matchmaker *Matchmaker) startMatch(group []*queueEntry) {
+	invited := make(map[uuid.UUID]bool)
+	for _, entry := range group {
+		invited[entry.playerID] = true
+	}
+
+	room, err := matchmaker.server.AddRoom(RoomSettings{
+		Name:     fmt.Sprintf("Match %s", group[0].name),
+		Mode:     group[0].mode,
+		Map:      defaultMap,
+		Bots:     matchSize - len(group),
+		Rated:    true,
+		Unlisted: true,
+		Invited:  invited,
+	})
+	if err != nil {
+		logger.Warn("unable to start match", "error", err)
+		matchmaker.requeue(group)
+		return
+	}
+
+	for _, entry := range group {
+		entry.update(&proto.QueueStatus{
+			State:  proto.QueueState_MATCHED,
+			Rating: int32(entry.rating),
+			RoomId: room.id.String(),
+		})
+	}
+}
+
+// requeue puts a group back in front of the queue keeping their wait time,
+// except for players that gave up in the meantime.
+func (matchmaker *Matchmaker) requeue(group []*queueEntry) {
+	matchmaker.mu.Lock()
+	defer matchmaker.mu.Unlock()
+
+	queue := make([]*queueEntry, 0, len(group)+len(matchmaker.queue))
+	for _, entry := range group {
+		if !entry.left {
+			queue = append(queue, entry)
+		}
+	}
+	matchmaker.queue = append(queue, matchmaker.queue...)
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	proto "github.com/nikit34/multiplayer_rpg/proto"
+)
+
+func enqueue(t *testing.T, matchmaker *Matchmaker, mode backend.GameMode, rating float64) *queueEntry {
+	t.Helper()
+
+	entry, err := matchmaker.enqueue(uuid.New(), "Alice", mode, rating)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+func latestStatus(t *testing.T, entry *queueEntry) *proto.QueueStatus {
+	t.Helper()
+
+	select {
+	case status := <-entry.status:
+		return status
+	default:
+		t.Fatal("no queue status sent")
+		return nil
+	}
+}
+
+func TestRatingRange(t *testing.T) {
+	tests := []struct {
+		waited time.Duration
+		want   float64
+	}{
+		{0, initialRatingRange},
+		{10 * time.Second, initialRatingRange + 10*ratingRangeGrowth},
+		{time.Hour, maxRatingRange},
+	}
+	for _, test := range tests {
+		if got := ratingRange(test.waited); got != test.want {
+			t.Errorf("got range %.0f after %v, want %.0f", got, test.waited, test.want)
+		}
+	}
+}
+
+func TestMatchWidensRange(t *testing.T) {
+	// The matchmaker is not run, match is called with the time instead.
+	matchmaker := newMatchmaker(nil)
+	entries := []*queueEntry{
+		enqueue(t, matchmaker, backend.ModeDeathmatch, 1500),
+		enqueue(t, matchmaker, backend.ModeDeathmatch, 1520),
+		enqueue(t, matchmaker, backend.ModeDeathmatch, 1480),
+		enqueue(t, matchmaker, backend.ModeDeathmatch, 1700),
+	}
+	// Another mode is never matched with them.
+	other := enqueue(t, matchmaker, backend.ModeTeamDeathmatch, 1500)
+	start := entries[0].enqueued
+
+	if groups := matchmaker.match(start); len(groups) != 0 {
+		t.Fatalf("got %d groups before the range covers everyone, want none", len(groups))
+	}
+	status := latestStatus(t, entries[3])
+	if status.State != proto.QueueState_WAITING || status.Position != 4 || status.PlayersInQueue != 4 {
+		t.Errorf("got status %+v, want fourth of four waiting", status)
+	}
+
+	// The oldest entry is 200 below 1700, its range covers that after 4
+	// seconds.
+	groups := matchmaker.match(start.Add(5 * time.Second))
+	if len(groups) != 1 || len(groups[0]) != matchSize {
+		t.Fatalf("got groups %v, want one full group", groups)
+	}
+	for _, entry := range entries {
+		found := false
+		for _, matched := range groups[0] {
+			found = found || matched == entry
+		}
+		if !found {
+			t.Errorf("player rated %.0f was not matched", entry.rating)
+		}
+	}
+	if len(matchmaker.queue) != 1 || matchmaker.queue[0] != other {
+		t.Errorf("got %d queued, want only the player of the other mode", len(matchmaker.queue))
+	}
+}
+
+func TestMatchBackfillsWithBots(t *testing.T) {
+	ts := newTestServer(t)
+	matchmaker := newMatchmaker(ts.server)
+	alice := enqueue(t, matchmaker, backend.ModeDeathmatch, 1500)
+	bob := enqueue(t, matchmaker, backend.ModeDeathmatch, 1550)
+	start := alice.enqueued
+
+	if groups := matchmaker.match(start.Add(backfillAfter - time.Second)); len(groups) != 0 {
+		t.Fatalf("got %d groups before the backfill, want none", len(groups))
+	}
+	groups := matchmaker.match(start.Add(backfillAfter))
+	if len(groups) != 1 || len(groups[0]) != 2 {
+		t.Fatalf("got groups %v, want one group of two", groups)
+	}
+
+	matchmaker.startMatch(groups[0])
+	status := latestStatus(t, bob)
+	if status.State != proto.QueueState_MATCHED {
+		t.Fatalf("got status %+v, want matched", status)
+	}
+	room, ok := ts.server.getRoom(uuid.MustParse(status.RoomId))
+	if !ok {
+		t.Fatalf("room %s of the match does not exist", status.RoomId)
+	}
+	t.Cleanup(func() {
+		room.close(nil)
+	})
+	if got := room.bots.Count(); got != matchSize-2 {
+		t.Errorf("got %d bots, want %d", got, matchSize-2)
+	}
+	if !room.settings.Rated || !room.settings.Invited[alice.playerID] || !room.settings.Invited[bob.playerID] {
+		t.Errorf("got settings %+v, want a rated room for the group", room.settings)
+	}
+}
+
+func TestMatchmakerStopsOnShutdown(t *testing.T) {
+	ts := newTestServer(t)
+	matchmaker := newMatchmaker(ts.server)
+	done := make(chan struct{})
+	go func() {
+		matchmaker.run()
+		close(done)
+	}()
+
+	ts.server.Shutdown(0)
+	select {
+	case <-done:
+	case <-time.After(convergeTimeout):
+		t.Fatal("the matchmaker still runs after the shutdown")
+	}
+}
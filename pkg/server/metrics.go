@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	"github.com/nikit34/multiplayer_rpg/pkg/metrics"
+)
+
+var (
+	connectedClients = metrics.Default.NewGauge(
+		"tshooter_connected_clients",
+		"Clients with an open game stream.",
+	)
+	openRooms = metrics.Default.NewGauge(
+		"tshooter_rooms",
+		"Rooms that are open.",
+	)
+	actionsApplied = metrics.Default.NewCounter(
+		"tshooter_actions_total",
+		"Actions performed by the games, including the rejected ones.",
+		"type",
+	)
+	actionsThrottled = metrics.Default.NewCounter(
+		"tshooter_actions_throttled_total",
+		"Actions rejected because they came too soon after the last one.",
+		"type",
+	)
+	requestsDropped = metrics.Default.NewCounter(
+		"tshooter_requests_dropped_total",
+		"Requests dropped because a client sent too many of them.",
+		"type",
+	)
+	floodDisconnects = metrics.Default.NewCounter(
+		"tshooter_flood_disconnects_total",
+		"Clients disconnected for flooding the server with requests.",
+	)
+	lasersRejected = metrics.Default.NewCounter(
+		"tshooter_lasers_rejected_total",
+		"Laser requests rejected by the server, by cause.",
+		"cause",
+	)
+	broadcastDuration = metrics.Default.NewHistogram(
+		"tshooter_broadcast_duration_seconds",
+		"Time it takes to send a message to every client of a room.",
+		metrics.DefaultBuckets,
+	)
+	sendQueueDepth = metrics.Default.NewGauge(
+		"tshooter_send_queue_depth",
+		"Messages waiting for their room to send them.",
+	)
+	tickDuration = metrics.Default.NewHistogram(
+		"tshooter_tick_duration_seconds",
+		"Time a step of a game loop takes.",
+		metrics.DefaultBuckets,
+	)
+	grpcErrors = metrics.Default.NewCounter(
+		"tshooter_grpc_errors_total",
+		"Failed gRPC calls by method and status code.",
+		"method", "code",
+	)
+)
+
+// gameObserver exports what the games of all rooms do.
+type gameObserver struct{}
+
+func actionType(action backend.Action) string {
+	switch action.(type) {
+	case backend.MoveAction:
+		return "move"
+	case backend.LaserAction:
+		return "laser"
+	}
+	return fmt.Sprintf("%T", action)
+}
+
+func (gameObserver) ActionApplied(action backend.Action) {
+	actionsApplied.Inc(actionType(action))
+}
+
+func (gameObserver) ActionThrottled(action backend.Action) {
+	actionsThrottled.Inc(actionType(action))
+}
+
+func (gameObserver) Ticked(duration time.Duration) {
+	tickDuration.Observe(duration.Seconds())
+}
+
+// countError counts a failed call. Canceled is left out, it is how a client
+// that goes away ends its call.
+func countError(method string, err error) {
+	if err == nil {
+		return
+	}
+	code := status.FromContextError(err).Code()
+	if s, ok := status.FromError(err); ok {
+		code = s.Code()
+	}
+	if code == codes.Canceled {
+		return
+	}
+	grpcErrors.Inc(method, code.String())
+}
+
+// UnaryMetricsInterceptor counts failed calls, it goes first so calls the
+// auth interceptor rejects are counted too.
+func UnaryMetricsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		countError(info.FullMethod, err)
+		return resp, err
+	}
+}
+
+// StreamMetricsInterceptor counts failed streams.
+func StreamMetricsInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, stream)
+		countError(info.FullMethod, err)
+		return err
+	}
+}
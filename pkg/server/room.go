@@ -0,0 +1,795 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	"github.com/nikit34/multiplayer_rpg/pkg/bot"
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
+	"github.com/nikit34/multiplayer_rpg/pkg/rating"
+	"github.com/nikit34/multiplayer_rpg/pkg/replay"
+	proto "github.com/nikit34/multiplayer_rpg/proto"
+)
+
+var (
+	ErrRoomClosed       = errors.New("the room has been closed")
+	ErrRoomFull         = errors.New("the room is full")
+	ErrNoSpectatorSlots = errors.New("the room has no room for more spectators")
+)
+
+type RoomSettings struct {
+	Name     string
+	Mode     backend.GameMode
+	Map      string
+	Password string
+	Bots     int
+	// Persistent rooms stay open when the last player leaves.
+	Persistent bool
+	// Rated rooms update the players' skill ratings after every round.
+	Rated bool
+	// Unlisted rooms are not returned by ListRooms.
+	Unlisted bool
+	// Invited limits the room to these players if it is not nil.
+	Invited map[uuid.UUID]bool
+	// Rotation are the maps the room plays one after another, a round on
+	// each, as "map" or "map:mode". Players vote between the next maps of
+	// the rotation while a round is over. The room stays on Map if it is
+	// empty.
+	Rotation []string
+}
+
+// Room is a single match with its own game, bots and clients. Rooms do not
+// share any game state, and a panic in one of them only closes that room.
+type Room struct {
+	id       uuid.UUID
+	settings RoomSettings
+	// settingsMu guards the map, mode, bots and rotation of settings, which
+	// admins can change while the room is open, vote and queuedMap.
+	settingsMu sync.RWMutex
+	// vote is the map vote while a round is over, nil otherwise.
+	vote *mapVote
+	// queuedMap is the map of the next round, until it starts.
+	queuedMap *backend.MapChoice
+	game      *backend.Game
+	bots      *bot.Bots
+	clients   map[uuid.UUID]*client
+	// numPlayers and numSpectators are kept next to clients so the room list
+	// can be read without waiting on a room that is busy broadcasting.
+	numPlayers    int32
+	numSpectators int32
+	mu            sync.RWMutex
+	closing       bool
+	closed        chan struct{}
+	closeOnce     sync.Once
+	created       time.Time
+	server        *GameServer
+	recorder      *replay.Recorder
+	recorderMu    sync.Mutex
+	logger        *logging.Logger
+	// broadcastLogger samples the messages sent to the clients.
+	broadcastLogger *logging.Logger
+	// pendingLasers maps the IDs the server picked for requested lasers to
+	// the requests, until the game spawned them.
+	pendingLasers map[uuid.UUID]pendingLaser
+	lasersMu      sync.Mutex
+}
+
+func newRoom(server *GameServer, settings RoomSettings) (*Room, error) {
+	gameMap, err := backend.GetMap(settings.Map)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parseRotation(settings.Rotation, settings.Mode); err != nil {
+		return nil, err
+	}
+
+	game := backend.NewGame()
+	game.SetClock(server.clock)
+	game.SetRNG(backend.NewRNG(server.rng.Int63()))
+	game.SetMap(gameMap)
+	game.Mode = settings.Mode
+	// AddRoom holds server.mu.
+	game.SetRules(server.rules)
+
+	bots := bot.NewBots(game)
+	for i := 0; i < settings.Bots; i++ {
+		bots.AddBot(fmt.Sprintf("Bob %d", i))
+	}
+
+	room := &Room{
+		id:            uuid.New(),
+		settings:      settings,
+		game:          game,
+		bots:          bots,
+		clients:       make(map[uuid.UUID]*client),
+		closed:        make(chan struct{}),
+		created:       time.Now(),
+		server:        server,
+		pendingLasers: make(map[uuid.UUID]pendingLaser),
+	}
+	game.SetObserver(roomObserver{room: room})
+	room.logger = logger.With("room", room.id)
+	room.broadcastLogger = room.logger.Sample(logSampleRate)
+
+	game.Start()
+	bots.Start()
+	room.run(room.watchChanges)
+	room.run(room.watchTimeout)
+	room.run(room.watchLatency)
+	room.run(room.watchGame)
+	return room, nil
+}
+
+// run recovers a panic in one of the room loops and closes the room instead
+// of taking down the server.
+func (room *Room) run(loop func()) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				room.close(fmt.Errorf("room %s panicked: %v", room.id, r))
+			}
+		}()
+		loop()
+	}()
+}
+
+func (room *Room) close(err error) {
+	room.closeOnce.Do(func() {
+		if err != nil {
+			room.logger.Error("closing room", "error", err)
+		} else {
+			room.logger.Info("closing room")
+		}
+
+		room.server.removeRoom(room.id)
+
+		room.mu.Lock()
+		room.closing = true
+		playerIDs := make([]uuid.UUID, 0, len(room.clients))
+		for playerID, currentClient := range room.clients {
+			currentClient.stop(ErrRoomClosed)
+			playerIDs = append(playerIDs, playerID)
+		}
+		room.mu.Unlock()
+
+		room.server.releasePlayers(room, playerIDs...)
+
+		close(room.closed)
+		room.bots.Stop()
+		room.game.Stop()
+		// The round being played is saved as it is.
+		room.stopRecording()
+	})
+}
+
+// closeIfEmpty closes a room that is not persistent once nobody is left in it.
+func (room *Room) closeIfEmpty() {
+	room.mu.Lock()
+	empty := len(room.clients) == 0 && !room.settings.Persistent
+	if empty {
+		room.closing = true
+	}
+	room.mu.Unlock()
+
+	if empty {
+		room.close(nil)
+	}
+}
+
+func (room *Room) watchGame() {
+	select {
+	case <-room.closed:
+	case <-room.game.Done():
+		room.close(room.game.Err())
+	}
+}
+
+func (room *Room) info() *proto.RoomInfo {
+	room.settingsMu.RLock()
+	defer room.settingsMu.RUnlock()
+
+	return &proto.RoomInfo{
+		Id:            room.id.String(),
+		Name:          room.settings.Name,
+		Mode:          proto.GetProtoGameMode(room.settings.Mode),
+		Map:           room.settings.Map,
+		Players:       atomic.LoadInt32(&room.numPlayers),
+		MaxPlayers:    room.server.getMaxPlayers(),
+		HasPassword:   room.settings.Password != "",
+		Bots:          int32(room.settings.Bots),
+		Spectators:    atomic.LoadInt32(&room.numSpectators),
+		MaxSpectators: maxSpectators,
+	}
+}
+
+func (room *Room) getMode() backend.GameMode {
+	room.game.Mu.RLock()
+	defer room.game.Mu.RUnlock()
+
+	return room.game.Mode
+}
+
+func (room *Room) getRules() backend.Rules {
+	room.game.Mu.RLock()
+	defer room.game.Mu.RUnlock()
+
+	return room.game.Rules()
+}
+
+func (room *Room) hasPlayer(playerID uuid.UUID) bool {
+	room.game.Mu.RLock()
+	defer room.game.Mu.RUnlock()
+
+	return room.game.GetEntity(playerID) != nil
+}
+
+func (room *Room) broadcast(resp *proto.Response) {
+	sendQueueDepth.Add(1)
+	room.mu.Lock()
+	sendQueueDepth.Add(-1)
+	started := time.Now()
+	sent := 0
+	for _, currentClient := range room.clients {
+		if currentClient.streamServer == nil {
+			continue
+		}
+		if err := currentClient.streamServer.Send(resp); err != nil {
+			currentClient.logger.Warn("broadcast failed", "error", err)
+			currentClient.stop(errors.New("failed to broadcast message"))
+			continue
+		}
+		sent++
+	}
+	room.mu.Unlock()
+	room.broadcastLogger.Debug("broadcast", "type", messageType(resp.GetAction()), "clients", sent)
+	broadcastDuration.Observe(time.Since(started).Seconds())
+}
+
+func (room *Room) sendToPlayers(resp *proto.Response, playerIDs ...uuid.UUID) {
+	recipients := make(map[uuid.UUID]bool)
+	for _, playerID := range playerIDs {
+		recipients[playerID] = true
+	}
+
+	sendQueueDepth.Add(1)
+	room.mu.Lock()
+	sendQueueDepth.Add(-1)
+	for playerID := range recipients {
+		currentClient, ok := room.clients[playerID]
+		if !ok || currentClient.streamServer == nil {
+			continue
+		}
+		if err := currentClient.streamServer.Send(resp); err != nil {
+			currentClient.logger.Warn("send failed", "error", err)
+			currentClient.stop(errors.New("failed to send message"))
+		}
+	}
+	room.mu.Unlock()
+}
+
+func (room *Room) handleMoveChange(change backend.MoveChange) {
+	resp := proto.Response{
+		Action: &proto.Response_UpdateEntity{
+			UpdateEntity: &proto.UpdateEntity{
+				Entity: proto.GetProtoEntity(change.Entity),
+			},
+		},
+	}
+	room.broadcast(&resp)
+}
+
+// handleAddEntityChange also acknowledges a requested laser to its owner, who
+// ignores the broadcast of its own lasers.
+func (room *Room) handleAddEntityChange(change backend.AddEntityChange) {
+	if laser, ok := change.Entity.(*backend.Laser); ok {
+		if pending, ok := room.takePendingLaser(laser.ID()); ok {
+			room.ackLaser(pending, laser)
+		}
+	}
+
+	resp := proto.Response{
+		Action: &proto.Response_AddEntity{
+			AddEntity: &proto.AddEntity{
+				Entity: proto.GetProtoEntity(change.Entity),
+			},
+		},
+	}
+	room.broadcast(&resp)
+}
+
+func (room *Room) handleRemoveEntityChange(change backend.RemoveEntityChange) {
+	resp := proto.Response{
+		Action: &proto.Response_RemoveEntity{
+			RemoveEntity: &proto.RemoveEntity{
+				Id: change.Entity.ID().String(),
+			},
+		},
+	}
+	room.broadcast(&resp)
+}
+
+func (room *Room) handlePlayerRespawnChange(change backend.PlayerRespawnChange) {
+	resp := proto.Response{
+		Action: &proto.Response_PlayerRespawn{
+			PlayerRespawn: &proto.PlayerRespawn{
+				Player:     proto.GetProtoPlayer(change.Player),
+				KilledById: change.KilledByID.String(),
+			},
+		},
+	}
+	room.broadcast(&resp)
+}
+
+func (room *Room) handleRoundOverChange(change backend.RoundOverChange) {
+	room.game.Mu.RLock()
+	defer room.game.Mu.RUnlock()
+
+	timestamp, err := ptypes.TimestampProto(room.game.NewRoundAt)
+	if err != nil {
+		room.logger.Error("unable to convert new round time", "time", room.game.NewRoundAt, "error", err)
+		return
+	}
+	resp := proto.Response{
+		Action: &proto.Response_RoundOver{
+			RoundOver: &proto.RoundOver{
+				RoundWinnerId: room.game.RoundWinner.String(),
+				NewRoundAt:    timestamp,
+			},
+		},
+	}
+	room.broadcast(&resp)
+}
+
+// recordResults rates the players of a finished round, bots are left out.
+// Only account players are rated, a guest could claim the ID of another.
+func (room *Room) recordResults() {
+	if !room.settings.Rated || !room.server.HasAccounts() {
+		return
+	}
+
+	room.mu.RLock()
+	playerIDs := make([]uuid.UUID, 0, len(room.clients))
+	for playerID := range room.clients {
+		playerIDs = append(playerIDs, playerID)
+	}
+	room.mu.RUnlock()
+
+	results := make([]rating.Result, 0, len(playerIDs))
+	room.game.Mu.RLock()
+	for _, playerID := range playerIDs {
+		player, ok := room.game.GetEntity(playerID).(*backend.Player)
+		if !ok {
+			continue
+		}
+		results = append(results, rating.Result{
+			PlayerID: playerID,
+			Name:     player.Name,
+			Score:    room.game.Score[playerID],
+		})
+	}
+	room.game.Mu.RUnlock()
+
+	if err := room.server.getRatings().Record(results); err != nil {
+		room.logger.Warn("unable to save ratings", "error", err)
+	}
+}
+
+func (room *Room) handleRoundStartChange(change backend.RoundStartChange) {
+	players := []*proto.Player{}
+	room.game.Mu.RLock()
+	for _, entity := range room.game.Entities {
+		player, ok := entity.(*backend.Player)
+		if !ok {
+			continue
+		}
+		players = append(players, proto.GetProtoPlayer(player))
+	}
+	// The map goes along because admins can change it between rounds.
+	mapRows := backend.MapRows(room.game.GetMap())
+	mode := proto.GetProtoGameMode(room.game.Mode)
+	rules := proto.GetProtoRules(room.game.Rules())
+	room.game.Mu.RUnlock()
+
+	resp := proto.Response{
+		Action: &proto.Response_RoundStart{
+			RoundStart: &proto.RoundStart{
+				Players: players,
+				Map:     mapRows,
+				Mode:    mode,
+				Rules:   rules,
+			},
+		},
+	}
+	room.broadcast(&resp)
+}
+
+func (room *Room) watchChanges() {
+	room.startRecording()
+	defer room.stopRecording()
+
+	for {
+		var change backend.Change
+		select {
+		case <-room.closed:
+			return
+		case change = <-room.game.ChangeChannel:
+		}
+
+		if _, ok := change.(backend.RoundStartChange); ok {
+			room.startRecording()
+		}
+		room.recordChange(change)
+
+		switch change_type := change.(type) {
+		case backend.MoveChange:
+			room.handleMoveChange(change_type)
+		case backend.AddEntityChange:
+			room.handleAddEntityChange(change_type)
+		case backend.RemoveEntityChange:
+			room.handleRemoveEntityChange(change_type)
+		case backend.PlayerRespawnChange:
+			room.handlePlayerRespawnChange(change_type)
+		case backend.RoundOverChange:
+			room.recordResults()
+			room.stopRecording()
+			room.handleRoundOverChange(change_type)
+			room.startMapVote()
+		case backend.RoundStartChange:
+			room.applyQueuedMap()
+			room.handleRoundStartChange(change_type)
+		}
+	}
+}
+
+// startRecording starts a replay of the round that is about to be played, one
+// file per round.
+func (room *Room) startRecording() {
+	if room.server.replayDir == "" {
+		return
+	}
+	room.stopRecording()
+
+	name := fmt.Sprintf("%s-%s.replay", time.Now().Format("20060102-150405"), room.id)
+	recorder, err := replay.NewRecorder(filepath.Join(room.server.replayDir, name), room.game)
+	if err != nil {
+		room.logger.Warn("unable to record replay", "error", err)
+		return
+	}
+	room.recorderMu.Lock()
+	room.recorder = recorder
+	room.recorderMu.Unlock()
+}
+
+func (room *Room) stopRecording() {
+	room.recorderMu.Lock()
+	recorder := room.recorder
+	room.recorder = nil
+	room.recorderMu.Unlock()
+
+	if recorder == nil {
+		return
+	}
+	if err := recorder.Close(); err != nil {
+		room.logger.Warn("unable to save replay", "error", err)
+	}
+}
+
+func (room *Room) recordChange(change backend.Change) {
+	room.recorderMu.Lock()
+	defer room.recorderMu.Unlock()
+	if room.recorder != nil {
+		room.recorder.Record(change)
+	}
+}
+
+// recordResponse records what is broadcast outside of the change stream.
+func (room *Room) recordResponse(resp *proto.Response) {
+	room.recorderMu.Lock()
+	defer room.recorderMu.Unlock()
+	if room.recorder != nil {
+		room.recorder.RecordResponse(resp)
+	}
+}
+
+func (room *Room) watchTimeout() {
+	timeoutTicker := time.NewTicker(timeoutCheckFrequency)
+	defer timeoutTicker.Stop()
+
+	for {
+		room.mu.RLock()
+		for _, client := range room.clients {
+			if client.spectator {
+				continue
+			}
+			if time.Since(client.lastMessage) > room.server.getClientTimeout() {
+				client.stop(errors.New("you have been timed out"))
+			}
+		}
+		room.mu.RUnlock()
+
+		select {
+		case <-room.closed:
+			return
+		case <-timeoutTicker.C:
+		}
+	}
+}
+
+// watchLatency pings the clients, the pongs tell their round trip time.
+func (room *Room) watchLatency() {
+	pingTicker := time.NewTicker(pingFrequency)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case <-room.closed:
+			return
+		case <-pingTicker.C:
+		}
+
+		room.broadcast(&proto.Response{
+			Action: &proto.Response_Ping{
+				Ping: &proto.Ping{
+					SentAt: ptypes.TimestampNow(),
+				},
+			},
+		})
+	}
+}
+
+// join reserves a client slot before the player is added, so concurrent
+// joins can not overfill the room. Spectators get the game stream without
+// an entity. address is where the player connects from.
+func (room *Room) join(playerID uuid.UUID, name string, spectator bool, address string) (*client, error) {
+	newClient, err := room.addClient(playerID, name, spectator, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if !spectator && !room.hasPlayer(playerID) {
+		room.addPlayer(playerID, name)
+	}
+	return newClient, nil
+}
+
+func (room *Room) leave(playerID uuid.UUID) {
+	currentClient, ok := room.getClient(playerID)
+	room.removeClient(playerID)
+	if ok && !currentClient.spectator {
+		room.removePlayer(playerID)
+	}
+}
+
+// countClients must be called with room.mu held.
+func (room *Room) countClients() {
+	players := 0
+	for _, currentClient := range room.clients {
+		if !currentClient.spectator {
+			players++
+		}
+	}
+	atomic.StoreInt32(&room.numPlayers, int32(players))
+	atomic.StoreInt32(&room.numSpectators, int32(len(room.clients)-players))
+}
+
+func (room *Room) addClient(playerID uuid.UUID, name string, spectator bool, address string) (*client, error) {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	if room.closing {
+		return nil, ErrRoomClosed
+	}
+	if spectator && atomic.LoadInt32(&room.numSpectators) >= maxSpectators {
+		return nil, ErrNoSpectatorSlots
+	}
+	if !spectator && atomic.LoadInt32(&room.numPlayers) >= room.server.getMaxPlayers() {
+		return nil, ErrRoomFull
+	}
+
+	id := uuid.New()
+	clientLogger := room.logger.With("client", id, "player", playerID, "name", name)
+	newClient := &client{
+		id:            id,
+		playerID:      playerID,
+		name:          name,
+		spectator:     spectator,
+		address:       address,
+		host:          addressHost(address),
+		done:          make(chan error, 1),
+		lastMessage:   time.Now(),
+		logger:        clientLogger,
+		requestLogger: clientLogger.Sample(logSampleRate),
+	}
+	room.clients[playerID] = newClient
+	room.countClients()
+	room.server.limits.connect(room, newClient)
+	return newClient, nil
+}
+
+func (room *Room) getClient(playerID uuid.UUID) (*client, bool) {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	currentClient, ok := room.clients[playerID]
+	return currentClient, ok
+}
+
+func (room *Room) removeClient(playerID uuid.UUID) {
+	room.mu.Lock()
+	oldClient, ok := room.clients[playerID]
+	delete(room.clients, playerID)
+	room.countClients()
+	room.mu.Unlock()
+
+	if ok {
+		room.server.limits.disconnect(oldClient)
+	}
+}
+
+func (room *Room) addPlayer(playerID uuid.UUID, name string) *backend.Player {
+	icon, _ := utf8.DecodeLastRuneInString(strings.ToUpper(name))
+
+	room.game.Mu.Lock()
+	spawnPoints := room.game.GetMapByType()[backend.MapTypeSpawn]
+	startCoordinate := spawnPoints[room.game.RNG().Intn(len(spawnPoints))]
+
+	player := &backend.Player{
+		Name:            name,
+		Icon:            icon,
+		IdentifierBase:  backend.IdentifierBase{UUID: playerID},
+		CurrentPosition: startCoordinate,
+		Team:            room.game.NextTeam(),
+	}
+	room.game.AddEntity(player)
+	room.game.Mu.Unlock()
+
+	room.announceJoined(player)
+	return player
+}
+
+func (room *Room) announceJoined(player *backend.Player) {
+	resp := proto.Response{
+		Action: &proto.Response_PlayerJoined{
+			PlayerJoined: &proto.PlayerJoined{
+				Player: proto.GetProtoPlayer(player),
+			},
+		},
+	}
+	room.recordResponse(&resp)
+	room.broadcast(&resp)
+}
+
+func (room *Room) removePlayer(playerID uuid.UUID) {
+	room.game.Mu.Lock()
+	name := ""
+	player, ok := room.game.GetEntity(playerID).(*backend.Player)
+	if ok {
+		name = player.Name
+	}
+	room.game.RemoveEntity(playerID)
+	room.game.Mu.Unlock()
+
+	room.announceLeft(playerID, name)
+}
+
+func (room *Room) announceLeft(playerID uuid.UUID, name string) {
+	resp := proto.Response{
+		Action: &proto.Response_PlayerLeft{
+			PlayerLeft: &proto.PlayerLeft{
+				Id:   playerID.String(),
+				Name: name,
+			},
+		},
+	}
+	room.recordResponse(&resp)
+	room.broadcast(&resp)
+}
+
+func (room *Room) getEntities() []*proto.Entity {
+	room.game.Mu.RLock()
+	defer room.game.Mu.RUnlock()
+
+	entities := make([]*proto.Entity, 0)
+	for _, entity := range room.game.Entities {
+		protoEntity := proto.GetProtoEntity(entity)
+		if protoEntity != nil {
+			entities = append(entities, protoEntity)
+		}
+	}
+	return entities
+}
+
+func (room *Room) getMapRows() []string {
+	room.game.Mu.RLock()
+	defer room.game.Mu.RUnlock()
+
+	return backend.MapRows(room.game.GetMap())
+}
+
+func (room *Room) handleMoveRequest(req *proto.Request, currentClient *client) {
+	move := req.GetMove()
+
+	room.game.SendAction(backend.MoveAction{
+		ID:        currentClient.playerID,
+		Direction: proto.GetBackendDirection(move.Direction),
+		Created:   room.game.Clock().Now(),
+	})
+}
+
+// handleLaserRequest spawns the laser under an ID picked by the server, the
+// ID the client proposed is only used to acknowledge the request. The
+// position and start time the client predicted are ignored.
+func (room *Room) handleLaserRequest(req *proto.Request, currentClient *client) {
+	laser := req.GetLaser()
+	clientID, err := uuid.Parse(laser.Id)
+	if err != nil {
+		currentClient.stop(errors.New("invalid laser ID provided"))
+		return
+	}
+
+	direction := proto.GetBackendDirection(laser.Direction)
+	if direction == backend.DirectionStop {
+		room.rejectLaser(currentClient.playerID, clientID, "direction", "invalid laser direction")
+		return
+	}
+
+	room.game.Mu.RLock()
+	roundOver := room.game.WaitForRound
+	room.game.Mu.RUnlock()
+	if roundOver {
+		room.rejectLaser(currentClient.playerID, clientID, "round_over", "the round is over")
+		return
+	}
+
+	now := room.game.Clock().Now()
+	laserID := room.game.RNG().UUID()
+	room.addPendingLaser(laserID, pendingLaser{
+		clientID:  clientID,
+		ownerID:   currentClient.playerID,
+		requested: now,
+	})
+	room.game.SendAction(backend.LaserAction{
+		OwnerID:   currentClient.playerID,
+		ID:        laserID,
+		Direction: direction,
+		Created:   now,
+	})
+}
+
+// messageType names the action of a request or response for logs, e.g.
+// "UpdateEntity".
+func messageType(action interface{}) string {
+	name := fmt.Sprintf("%T", action)
+	return name[strings.LastIndex(name, "_")+1:]
+}
+
+// handleRequest only lets spectators chat, they have nothing to move.
+func (room *Room) handleRequest(req *proto.Request, currentClient *client) {
+	if currentClient.spectator {
+		if req.GetChat() != nil {
+			room.handleChatRequest(req, currentClient)
+		}
+		return
+	}
+
+	switch req.GetAction().(type) {
+	case *proto.Request_Move:
+		room.handleMoveRequest(req, currentClient)
+	case *proto.Request_Laser:
+		room.handleLaserRequest(req, currentClient)
+	case *proto.Request_Chat:
+		room.handleChatRequest(req, currentClient)
+	case *proto.Request_Vote:
+		room.handleVoteRequest(req, currentClient)
+	}
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	proto "github.com/nikit34/multiplayer_rpg/proto"
+)
+
+// mapVoteCandidates is how many maps of the rotation players can vote for
+// while a round is over.
+const mapVoteCandidates = 3
+
+// mapVote picks the map of the next round among the next maps of the
+// rotation. Nobody voting keeps the rotation order.
+type mapVote struct {
+	candidates []backend.MapChoice
+	// votes maps the players that voted to the index of their candidate.
+	votes map[uuid.UUID]int
+}
+
+func (vote *mapVote) tally() []int {
+	tally := make([]int, len(vote.candidates))
+	for _, choice := range vote.votes {
+		tally[choice]++
+	}
+	return tally
+}
+
+// winner is the candidate with the most votes, ties go to the one that is
+// first in the rotation.
+func (vote *mapVote) winner() backend.MapChoice {
+	tally := vote.tally()
+	winner := 0
+	for i := range tally {
+		if tally[i] > tally[winner] {
+			winner = i
+		}
+	}
+	return vote.candidates[winner]
+}
+
+func (vote *mapVote) response() *proto.Response {
+	tally := vote.tally()
+	candidates := make([]*proto.MapCandidate, 0, len(vote.candidates))
+	for i, candidate := range vote.candidates {
+		candidates = append(candidates, &proto.MapCandidate{
+			Map:   candidate.Map,
+			Mode:  proto.GetProtoGameMode(candidate.Mode),
+			Votes: int32(tally[i]),
+		})
+	}
+	return &proto.Response{
+		Action: &proto.Response_MapVote{
+			MapVote: &proto.MapVote{
+				Candidates: candidates,
+			},
+		},
+	}
+}
+
+// parseRotation reads the entries of a rotation, "map" or "map:mode", the
+// mode defaults to mode.
+func parseRotation(rotation []string, mode backend.GameMode) ([]backend.MapChoice, error) {
+	choices := make([]backend.MapChoice, 0, len(rotation))
+	for _, entry := range rotation {
+		choice, err := backend.ParseMapChoice(entry, mode)
+		if err != nil {
+			return nil, err
+		}
+		choices = append(choices, choice)
+	}
+	return choices, nil
+}
+
+// SetRotation sets the maps the default room plays one after another, as
+// "map" or "map:mode". Players vote between the next maps when a round is
+// over.
+func (s *GameServer) SetRotation(rotation []string) error {
+	if _, err := parseRotation(rotation, backend.ModeDeathmatch); err != nil {
+		return err
+	}
+	room, ok := s.getRoom(uuid.Nil)
+	if !ok {
+		return errors.New("the server has no default room")
+	}
+
+	room.settingsMu.Lock()
+	room.settings.Rotation = append([]string{}, rotation...)
+	room.settingsMu.Unlock()
+	return nil
+}
+
+// SetDefaultRoomBots adds or removes bots of the default room until it has
+// count of them.
+func (s *GameServer) SetDefaultRoomBots(count int) error {
+	room, ok := s.getRoom(uuid.Nil)
+	if !ok {
+		return errors.New("the server has no default room")
+	}
+	return room.setBots(count)
+}
+
+// voteCandidates returns the maps that follow current in rotation, or the
+// first ones if current is not part of it.
+func voteCandidates(rotation []backend.MapChoice, current backend.MapChoice) []backend.MapChoice {
+	start := 0
+	for i, choice := range rotation {
+		if choice == current {
+			start = i + 1
+			break
+		}
+	}
+
+	candidates := []backend.MapChoice{}
+	for i := 0; i < len(rotation) && len(candidates) < mapVoteCandidates; i++ {
+		choice := rotation[(start+i)%len(rotation)]
+		duplicate := false
+		for _, candidate := range candidates {
+			duplicate = duplicate || candidate == choice
+		}
+		if !duplicate {
+			candidates = append(candidates, choice)
+		}
+	}
+	return candidates
+}
+
+// startMapVote queues the next map of the rotation once a round is over and
+// lets the players vote for another one while they wait.
+func (room *Room) startMapVote() {
+	room.settingsMu.Lock()
+	rotation, err := parseRotation(room.settings.Rotation, room.settings.Mode)
+	if err != nil || len(rotation) == 0 {
+		room.settingsMu.Unlock()
+		if err != nil {
+			room.logger.Warn("unable to rotate the map", "error", err)
+		}
+		return
+	}
+	current := backend.MapChoice{Map: room.settings.Map, Mode: room.settings.Mode}
+	vote := &mapVote{
+		candidates: voteCandidates(rotation, current),
+		votes:      make(map[uuid.UUID]int),
+	}
+	room.vote = vote
+	room.queueMap(vote.winner())
+	resp := vote.response()
+	room.settingsMu.Unlock()
+
+	// A single candidate leaves nothing to vote for.
+	if len(vote.candidates) > 1 {
+		room.broadcast(resp)
+	}
+}
+
+// queueMap must be called with settingsMu held.
+func (room *Room) queueMap(choice backend.MapChoice) {
+	gameMap, err := backend.GetMap(choice.Map)
+	if err != nil {
+		room.logger.Warn("unable to queue the map", "map", choice.Map, "error", err)
+		return
+	}
+	// The next round may have started already.
+	if room.game.QueueMap(gameMap, choice.Mode) {
+		room.queuedMap = &choice
+	}
+}
+
+func (room *Room) handleVoteRequest(req *proto.Request, currentClient *client) {
+	choice := int(req.GetVote().Choice)
+
+	room.settingsMu.Lock()
+	vote := room.vote
+	if vote == nil || len(vote.candidates) < 2 {
+		room.settingsMu.Unlock()
+		room.sendSystemMessage(currentClient.playerID, "there is no map vote right now")
+		return
+	}
+	if choice < 0 || choice >= len(vote.candidates) {
+		room.settingsMu.Unlock()
+		room.sendSystemMessage(currentClient.playerID, "there is no such map to vote for")
+		return
+	}
+	vote.votes[currentClient.playerID] = choice
+	room.queueMap(vote.winner())
+	resp := vote.response()
+	room.settingsMu.Unlock()
+
+	room.broadcast(resp)
+}
+
+// sendMapVote tells a player that joins while a round is over about the
+// vote.
+func (room *Room) sendMapVote(playerID uuid.UUID) {
+	room.settingsMu.RLock()
+	vote := room.vote
+	var resp *proto.Response
+	if vote != nil && len(vote.candidates) > 1 {
+		resp = vote.response()
+	}
+	room.settingsMu.RUnlock()
+
+	if resp != nil {
+		room.sendToPlayers(resp, playerID)
+	}
+}
+
+// applyQueuedMap ends the vote once the next round started, updates the
+// settings if the game switched to the queued map, and lets the bots find
+// their way on it.
+func (room *Room) applyQueuedMap() {
+	room.settingsMu.Lock()
+	room.vote = nil
+	choice := room.queuedMap
+	room.queuedMap = nil
+	if choice != nil {
+		room.settings.Map = choice.Map
+		room.settings.Mode = choice.Mode
+	}
+	room.settingsMu.Unlock()
+
+	if choice == nil {
+		return
+	}
+	room.bots.MapChanged()
+	room.logger.Info("rotated map", "map", choice.Map, "mode", choice.Mode)
+}
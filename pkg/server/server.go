@@ -2,380 +2,943 @@ package server
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
-	"log"
-	"math/rand"
+	"fmt"
+	"net"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
-	"unicode/utf8"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/uuid"
-	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 
+	"github.com/nikit34/multiplayer_rpg/pkg/auth"
 	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
+	"github.com/nikit34/multiplayer_rpg/pkg/rating"
+	"github.com/nikit34/multiplayer_rpg/pkg/tlsconfig"
 	proto "github.com/nikit34/multiplayer_rpg/proto"
 )
 
 type client struct {
 	streamServer proto.Game_StreamServer
-	lastMessage time.Time
-	done chan error
-	playerID uuid.UUID
-	id uuid.UUID
+	lastMessage  time.Time
+	done         chan error
+	playerID     uuid.UUID
+	id           uuid.UUID
+	chatTimes    []time.Time
+	name         string
+	spectator    bool
+	// address is where the client connected from, it is updated when the
+	// stream starts.
+	address string
+	// host is the host of the address the client joined from, it counts
+	// against the connection limit of the host and never changes.
+	host string
+	// latency is the last round trip time of a ping in nanoseconds, it is
+	// read and written atomically.
+	latency int64
+	logger  *logging.Logger
+	// requestLogger samples the requests of the client.
+	requestLogger *logging.Logger
+	// limiter is replaced whenever a stream starts.
+	limiter *requestLimiter
 }
 
+// stop ends the client's stream with err. Only the first error is kept, so
+// stop never blocks even after the stream has already finished.
+func (c *client) stop(err error) {
+	select {
+	case c.done <- err:
+	default:
+	}
+}
+
+func (c *client) setLatency(latency time.Duration) {
+	atomic.StoreInt64(&c.latency, int64(latency))
+}
+
+func (c *client) getLatency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.latency))
+}
+
+// logSampleRate is how many of the messages a client sends or receives share
+// one debug record.
+const logSampleRate = 100
+
+var logger = logging.New()
+
+// GameServer is the room manager, it hands out tokens and routes every
+// stream to the room the token was issued for.
 type GameServer struct {
 	proto.UnimplementedGameServer
-	game    *backend.Game
-	clients map[uuid.UUID]*client
-	mu      sync.RWMutex
+	rooms         map[uuid.UUID]*Room
+	players       map[uuid.UUID]*Room
+	defaultRoomID uuid.UUID
+	maxRooms      int
+	mu            sync.RWMutex
+	// password and rules are guarded by mu, they can change while serving.
 	password string
+	rules    backend.Rules
+	// maxPlayers and clientTimeout are read and written atomically.
+	maxPlayers    int32
+	clientTimeout int64
+	accounts      *auth.Accounts
+	tokens        *auth.Tokens
+	chatFilter    *ChatFilter
+	ratings       *rating.Ratings
+	matchmaker    *Matchmaker
+	replayDir     string
+	clock         backend.Clock
+	rng           *backend.RNG
+	bans          *Bans
+	limits        *connectLimits
+	// health is guarded by its own lock, rooms report to it while s.mu is
+	// held.
+	health   *health.Server
+	healthMu sync.RWMutex
+	// muted players can not chat, it is kept here so that reconnecting does
+	// not lift it.
+	muted map[uuid.UUID]bool
+	// shutdown is closed once Shutdown was called.
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+func NewGameServer(password string, accounts *auth.Accounts, tokens *auth.Tokens, maxRooms int) *GameServer {
+	server := &GameServer{
+		rooms:         make(map[uuid.UUID]*Room),
+		players:       make(map[uuid.UUID]*Room),
+		maxRooms:      maxRooms,
+		password:      password,
+		rules:         backend.DefaultRules(),
+		maxPlayers:    maxClients,
+		clientTimeout: int64(clientTimeout),
+		accounts:      accounts,
+		tokens:        tokens,
+		clock:         backend.RealClock,
+		rng:           backend.NewRNG(time.Now().UnixNano()),
+		limits:        newConnectLimits(),
+		muted:         make(map[uuid.UUID]bool),
+		shutdown:      make(chan struct{}),
+	}
+	server.ratings, _ = rating.NewRatings("")
+	server.bans, _ = NewBans("")
+	server.matchmaker = newMatchmaker(server)
+	server.watchRooms()
+	go server.matchmaker.run()
+	return server
 }
 
-func (s *GameServer) broadcast(resp *proto.Response) {
+// SetRatings sets where the skill ratings are kept. Ratings are only kept on
+// servers with accounts, the player IDs of guests are their own pick.
+func (s *GameServer) SetRatings(ratings *rating.Ratings) {
 	s.mu.Lock()
-	for id, currentClient := range s.clients {
-		if currentClient.streamServer == nil {
-			continue
-		}
-		if err := currentClient.streamServer.Send(resp); err != nil {
-			log.Printf("%s - broadcast error %v", id, err)
-			currentClient.done <- errors.New("failed to broadcast message")
-			continue
-		}
-		log.Printf("%s - broadcasted %+v", resp, id)
+	defer s.mu.Unlock()
+
+	s.ratings = ratings
+}
+
+func (s *GameServer) getRatings() *rating.Ratings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.ratings
+}
+
+// getRating is the skill rating of a player, guests are all rated the same.
+func (s *GameServer) getRating(playerID uuid.UUID) float64 {
+	if !s.HasAccounts() {
+		return rating.InitialRating
 	}
-	s.mu.Unlock()
+	return s.getRatings().Get(playerID)
+}
+
+// SetClock sets the clock the games of rooms added afterwards run on. Timeouts
+// and matchmaking keep using the wall clock.
+func (s *GameServer) SetClock(clock backend.Clock) {
+	s.clock = clock
+}
+
+// SetRNG seeds the rooms added afterwards, every room gets its own RNG drawn
+// from rng so that the same seed starts the same games.
+func (s *GameServer) SetRNG(rng *backend.RNG) {
+	s.rng = rng
+}
+
+// SetReplayDir makes every room record its rounds to replay files in dir. It
+// only applies to rooms added afterwards.
+func (s *GameServer) SetReplayDir(dir string) {
+	s.replayDir = dir
 }
 
-func (s *GameServer) handleMoveChange(change backend.MoveChange) {
-	resp := proto.Response{
-		Action: &proto.Response_UpdateEntity{
-			UpdateEntity: &proto.UpdateEntity{
-				Entity: proto.GetProtoEntity(change.Entity),
-			},
-		},
+// AddRoom starts a new room. The first persistent room becomes the default
+// room for players that do not pick one.
+func (s *GameServer) AddRoom(settings RoomSettings) (*Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkShutdown(); err != nil {
+		return nil, err
+	}
+	if len(s.rooms) >= s.maxRooms {
+		return nil, errors.New("the server has reached its room limit")
 	}
-	s.broadcast(&resp)
+
+	room, err := newRoom(s, settings)
+	if err != nil {
+		return nil, err
+	}
+	s.rooms[room.id] = room
+	openRooms.Add(1)
+	s.setHealth(RoomHealthService(room.id), true)
+	if settings.Persistent && s.defaultRoomID == uuid.Nil {
+		s.defaultRoomID = room.id
+	}
+
+	room.logger.Info("created room", "name", settings.Name, "map", settings.Map, "mode", settings.Mode)
+	return room, nil
 }
 
-func (s *GameServer) handleAddEntityChange(change backend.AddEntityChange) {
-	resp := proto.Response{
-		Action: &proto.Response_AddEntity{
-			AddEntity: &proto.AddEntity{
-				Entity: proto.GetProtoEntity(change.Entity),
-			},
-		},
+func (s *GameServer) removeRoom(roomID uuid.UUID) {
+	s.mu.Lock()
+	if _, ok := s.rooms[roomID]; ok {
+		delete(s.rooms, roomID)
+		openRooms.Add(-1)
+	}
+	isDefault := roomID == s.defaultRoomID
+	s.mu.Unlock()
+
+	s.setHealth(RoomHealthService(roomID), false)
+	// Players that do not pick a room have nowhere to go.
+	if isDefault {
+		s.setHealth("", false)
+		s.setHealth(gameService, false)
 	}
-	s.broadcast(&resp)
 }
 
-func (s *GameServer) handleRemoveEntityChange(change backend.RemoveEntityChange) {
-	resp := proto.Response{
-		Action: &proto.Response_RemoveEntity{
-			RemoveEntity: &proto.RemoveEntity{
-				Id: change.Entity.ID().String(),
-			},
-		},
+// getRoom falls back to the default room for an empty room ID.
+func (s *GameServer) getRoom(roomID uuid.UUID) (*Room, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if roomID == uuid.Nil {
+		roomID = s.defaultRoomID
 	}
-	s.broadcast(&resp)
+	room, ok := s.rooms[roomID]
+	return room, ok
 }
 
-func (s *GameServer) handlePlayerRespawnChange(change backend.PlayerRespawnChange) {
-	resp := proto.Response{
-		Action: &proto.Response_PlayerRespawn{
-			PlayerRespawn: &proto.PlayerRespawn{
-				Player:     proto.GetProtoPlayer(change.Player),
-				KilledById: change.KilledByID.String(),
-			},
-		},
+// DefaultRoomInfo describes the default room the way ListRooms does, false
+// if the server has none.
+func (s *GameServer) DefaultRoomInfo() (*proto.RoomInfo, bool) {
+	room, ok := s.getRoom(uuid.Nil)
+	if !ok {
+		return nil, false
 	}
-	s.broadcast(&resp)
+	return room.info(), true
+}
+
+// HasPassword tells if players need the server password to connect.
+func (s *GameServer) HasPassword() bool {
+	return s.getPassword() != ""
 }
 
-func (s *GameServer) handleRoundOverChange(change backend.RoundOverChange) {
-	s.game.Mu.RLock()
-	defer s.game.Mu.RUnlock()
+// HasAccounts tells if players need an account to connect.
+func (s *GameServer) HasAccounts() bool {
+	return s.accounts != nil
+}
 
-	timestamp, err := ptypes.TimestampProto(s.game.NewRoundAt)
-	if err != nil {
-		log.Fatalf("unable to parse new round timestamp %v", s.game.NewRoundAt)
+func (s *GameServer) getRooms() []*Room {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rooms := make([]*Room, 0, len(s.rooms))
+	for _, room := range s.rooms {
+		rooms = append(rooms, room)
 	}
-	resp := proto.Response{
-		Action: &proto.Response_RoundOver{
-			RoundOver: &proto.RoundOver{
-				RoundWinnerId: s.game.RoundWinner.String(),
-				NewRoundAt:    timestamp,
-			},
-		},
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].created.Before(rooms[j].created)
+	})
+	return rooms
+}
+
+// claimPlayer records which room a player is in, so the same player can not
+// join twice. It never touches room state, so a stalled room can not block
+// players joining other rooms.
+func (s *GameServer) claimPlayer(playerID uuid.UUID, room *Room) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.players[playerID]; ok {
+		return false
 	}
-	s.broadcast(&resp)
+	s.players[playerID] = room
+	return true
 }
 
-func (s *GameServer) handleRoundStartChange(change backend.RoundStartChange) {
-	players := []*proto.Player{}
-	s.game.Mu.RLock()
-	for _, entity := range s.game.Entities {
-		player, ok := entity.(*backend.Player)
-		if !ok {
-			continue
+func (s *GameServer) releasePlayers(room *Room, playerIDs ...uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, playerID := range playerIDs {
+		if s.players[playerID] == room {
+			delete(s.players, playerID)
 		}
-		players = append(players, proto.GetProtoPlayer(player))
 	}
-	s.game.Mu.RUnlock()
+}
 
-	resp := proto.Response{
-		Action: &proto.Response_RoundStart{
-			RoundStart: &proto.RoundStart{
-				Players: players,
-			},
-		},
-	}
-	s.broadcast(&resp)
+func (s *GameServer) isPlaying(playerID uuid.UUID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.players[playerID]
+	return ok
 }
 
-func (s *GameServer) watchChanges() {
+// watchRooms closes rooms that were created but nobody joined.
+func (s *GameServer) watchRooms() {
+	ticker := time.NewTicker(roomIdleTimeout / 2)
+
 	go func() {
 		for {
-			change := <-s.game.ChangeChannel
-			switch change_type := change.(type) {
-			case backend.MoveChange:
-				s.handleMoveChange(change_type)
-			case backend.AddEntityChange:
-				s.handleAddEntityChange(change_type)
-			case backend.RemoveEntityChange:
-				s.handleRemoveEntityChange(change_type)
-			case backend.PlayerRespawnChange:
-				s.handlePlayerRespawnChange(change_type)
-			case backend.RoundOverChange:
-				s.handleRoundOverChange(change_type)
-			case backend.RoundStartChange:
-				s.handleRoundStartChange(change_type)
+			<-ticker.C
+			for _, room := range s.getRooms() {
+				if time.Since(room.created) > roomIdleTimeout {
+					room.closeIfEmpty()
+				}
 			}
 		}
 	}()
 }
 
-func (s *GameServer) watchTimeout() {
-	timeoutTicker := time.NewTicker(1 * time.Minute)
+// SetBans replaces the in-memory ban list, it must be called before serving.
+// A ban list with a file is reloaded when the file changes.
+func (s *GameServer) SetBans(bans *Bans) {
+	s.bans = bans
+	if bans.path != "" {
+		s.watchBans()
+	}
+}
+
+// SetConnectLimits caps the connections of a single host and its connect
+// attempts per minute, zero means no limit.
+func (s *GameServer) SetConnectLimits(maxConnections int, maxAttemptsPerMinute int) {
+	s.limits.set(maxConnections, maxAttemptsPerMinute)
+}
+
+// SetPassword changes the server password for the players that connect
+// afterwards.
+func (s *GameServer) SetPassword(password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.password = password
+}
+
+func (s *GameServer) getPassword() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.password
+}
+
+// SetRules sets the rules of rooms added afterwards. Open rooms switch to them
+// when their next round starts.
+func (s *GameServer) SetRules(rules backend.Rules) {
+	s.mu.Lock()
+	s.rules = rules
+	s.mu.Unlock()
+
+	for _, room := range s.getRooms() {
+		room.game.QueueRules(rules)
+	}
+}
+
+// SetMaxPlayers limits the players of every room, players that are already
+// in a room stay. It can not be raised above maxClients.
+func (s *GameServer) SetMaxPlayers(maxPlayers int) {
+	if maxPlayers > maxClients {
+		maxPlayers = maxClients
+	}
+	atomic.StoreInt32(&s.maxPlayers, int32(maxPlayers))
+}
+
+func (s *GameServer) getMaxPlayers() int32 {
+	return atomic.LoadInt32(&s.maxPlayers)
+}
+
+// getMaxRoomBots leaves a room at least one place for a player.
+func (s *GameServer) getMaxRoomBots() int {
+	return int(s.getMaxPlayers()) - 1
+}
+
+// SetClientTimeout sets how long clients can stay silent before they are
+// dropped.
+func (s *GameServer) SetClientTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&s.clientTimeout, int64(timeout))
+}
+
+func (s *GameServer) getClientTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&s.clientTimeout))
+}
+
+func (s *GameServer) Bans() *Bans {
+	return s.bans
+}
+
+// watchBans reloads the ban file when it was edited and disconnects the
+// players it bans.
+func (s *GameServer) watchBans() {
+	ticker := time.NewTicker(banReloadFrequency)
 
-	go func () {
+	go func() {
 		for {
-			for _, client := range s.clients {
-				if time.Since(client.lastMessage).Minutes() > clientTimeout {
-					client.done <- errors.New("you have been timed out")
-					return
-				}
+			<-ticker.C
+			changed, err := s.bans.Reload()
+			if err != nil {
+				logger.Warn("can not reload bans", "error", err)
+				continue
+			}
+			if changed {
+				kicked := s.kickBanned()
+				logger.Info("reloaded bans", "bans", len(s.bans.List()), "kicked", kicked)
 			}
-			<- timeoutTicker.C
 		}
-	} ()
+	}()
 }
 
-func NewGameServer(game *backend.Game, password string) *GameServer {
-	server := &GameServer{
-		game:    game,
-		clients: make(map[uuid.UUID]*client),
-		password: password,
+// kickBanned disconnects the clients that are banned and returns how many
+// there were.
+func (s *GameServer) kickBanned() int {
+	kicked := 0
+	for _, current := range s.limits.list() {
+		ban, ok := s.bans.Find(current.client.name, net.ParseIP(current.client.host))
+		if !ok {
+			continue
+		}
+		current.room.kick(current.client.playerID, status.Error(codes.PermissionDenied, ban.Message()))
+		kicked++
 	}
-	server.watchChanges()
-	server.watchTimeout()
-	return server
+	return kicked
 }
 
-func (s *GameServer) removeClient(id uuid.UUID) {
+func (s *GameServer) setMuted(playerID uuid.UUID, muted bool) {
 	s.mu.Lock()
-	delete(s.clients, id)
-	s.mu.Unlock()
+	defer s.mu.Unlock()
+
+	if muted {
+		s.muted[playerID] = true
+	} else {
+		delete(s.muted, playerID)
+	}
 }
 
-func (s *GameServer) removePlayer(playerID uuid.UUID) {
-	s.game.Mu.Lock()
-	s.game.RemoveEntity(playerID)
-	s.game.Mu.Unlock()
+func (s *GameServer) isMuted(playerID uuid.UUID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	resp := proto.Response{
-		Action: &proto.Response_RemoveEntity{
-			RemoveEntity: &proto.RemoveEntity{
-				Id: playerID.String(),
-			},
-		},
-	}
-	s.broadcast(&resp)
+	return s.muted[playerID]
 }
 
-func (s *GameServer) Connect(ctx context.Context, req *proto.ConnectRequest) (*proto.ConnectResponse, error) {
-	if len(s.clients) >= maxClients {
-		return nil, errors.New("The server is full")
+// peerAddress is the address a call comes from, or empty if it is unknown.
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
 	}
+	return p.Addr.String()
+}
 
-	playerID, err := uuid.Parse(req.Id)
+// addressHost is the host of a host:port address, or the whole address if it
+// has no port.
+func addressHost(address string) string {
+	host, _, err := net.SplitHostPort(address)
 	if err != nil {
-		return nil, err
+		return address
 	}
+	return host
+}
 
-	if req.Password != s.password {
-		return nil, errors.New("invalid password provided")
+// addressIP is the IP of a host:port address, nil for other addresses like
+// in-process connections.
+func addressIP(address string) net.IP {
+	return net.ParseIP(addressHost(address))
+}
+
+// checkBanned turns away banned accounts and addresses. name is the account
+// name, or the name the player typed on servers without accounts, so there an
+// account ban only keeps out players who use that name.
+func (s *GameServer) checkBanned(ctx context.Context, name string) error {
+	ban, ok := s.bans.Find(name, addressIP(peerAddress(ctx)))
+	if !ok {
+		return nil
 	}
+	return status.Error(codes.PermissionDenied, ban.Message())
+}
 
-	s.game.Mu.RLock()
-	if s.game.GetEntity(playerID) != nil {
-		return nil, errors.New("duplicate player ID provided")
+// checkLimits counts a connect attempt and turns it away if its host tries
+// too often or already has too many connections. Otherwise a connection of
+// the host is reserved until the returned function is called, which has to
+// happen once the client joined its room or failed to. Trusted hosts run many
+// bots, so they are not limited.
+func (s *GameServer) checkLimits(ctx context.Context) (func(), error) {
+	host := addressHost(peerAddress(ctx))
+	if host == "" || tlsconfig.IsTrustedPeer(ctx) {
+		return func() {}, nil
 	}
-	s.game.Mu.RUnlock()
 
-	re := regexp.MustCompile("^[a-zA-Z0-9]+$")
-	if !re.MatchString(req.Name) {
-		return nil, errors.New("invalid name provided")
+	if !s.limits.attempt(host, time.Now()) {
+		return nil, status.Error(codes.ResourceExhausted, "too many connection attempts, try again in a minute")
+	}
+	if !s.limits.reserve(host) {
+		return nil, status.Errorf(codes.ResourceExhausted, "too many connections from your address, the limit is %d", s.limits.getMaxConnections())
 	}
-	icon, _ := utf8.DecodeLastRuneInString(strings.ToUpper(req.Name))
+	return func() {
+		s.limits.release(host)
+	}, nil
+}
 
-	spawnPoints := s.game.GetMapByType()[backend.MapTypeSpawn]
-	rand.Seed(time.Now().Unix())
-	i := rand.Int() % len(spawnPoints)
-	startCoordinate := spawnPoints[i]
+// findClient looks for a connected client by player ID or name.
+func (s *GameServer) findClient(player string) (*Room, *client, bool) {
+	found, ok := s.limits.find(player)
+	if !ok {
+		return nil, nil, false
+	}
+	return found.room, found.client, true
+}
 
-	player := &backend.Player{
-		Name: req.Name,
-		Icon: icon,
-		IdentifierBase: backend.IdentifierBase{UUID: playerID},
-		CurrentPosition: startCoordinate,
+// findRoom looks for a room by ID or name, empty is the default room.
+func (s *GameServer) findRoom(name string) (*Room, bool) {
+	if name == "" {
+		return s.getRoom(uuid.Nil)
+	}
+	for _, room := range s.getRooms() {
+		if room.id.String() == name || strings.EqualFold(room.settings.Name, name) {
+			return room, true
+		}
 	}
+	return nil, false
+}
 
-	s.game.Mu.Lock()
-	s.game.AddEntity(player)
-	s.game.Mu.Unlock()
+// checkPassword compares in constant time so the time of a guess tells
+// nothing about the password. Trusted hosts need none.
+func (s *GameServer) checkPassword(ctx context.Context, password string) error {
+	matches := subtle.ConstantTimeCompare([]byte(password), []byte(s.getPassword())) == 1
+	if !matches && !tlsconfig.IsTrustedPeer(ctx) {
+		return errors.New("invalid password provided")
+	}
+	return nil
+}
 
-	s.game.Mu.RLock()
-	entities := make([]*proto.Entity, 0)
-	for _, entity := range s.game.Entities {
-		protoEntity := proto.GetProtoEntity(entity)
-		if protoEntity != nil {
-			entities = append(entities, protoEntity)
+func (s *GameServer) ListRooms(ctx context.Context, req *proto.ListRoomsRequest) (*proto.ListRoomsResponse, error) {
+	rooms := make([]*proto.RoomInfo, 0)
+	for _, room := range s.getRooms() {
+		if room.settings.Unlisted {
+			continue
 		}
+		rooms = append(rooms, room.info())
 	}
-	s.game.Mu.RUnlock()
 
-	resp := proto.Response{
-		Action: &proto.Response_AddEntity{
-			AddEntity: &proto.AddEntity{
-				Entity: proto.GetProtoEntity(player),
-			},
-		},
+	return &proto.ListRoomsResponse{
+		Rooms: rooms,
+		Maps:  backend.MapNames(),
+	}, nil
+}
+
+func (s *GameServer) CreateRoom(ctx context.Context, req *proto.CreateRoomRequest) (*proto.CreateRoomResponse, error) {
+	if err := s.checkPassword(ctx, req.ServerPassword); err != nil {
+		return nil, err
 	}
 
-	s.broadcast(&resp)
+	if !validRoomName.MatchString(req.Name) {
+		return nil, errors.New("invalid room name provided")
+	}
 
-	s.mu.Lock()
-	token := uuid.New()
-	s.clients[token] = &client{
-		id: token,
-		playerID: playerID,
-		done: make(chan error),
-		lastMessage: time.Now(),
+	if req.Bots < 0 || int(req.Bots) > s.getMaxRoomBots() {
+		return nil, fmt.Errorf("a room can have at most %d bots", s.getMaxRoomBots())
+	}
+
+	mapName := req.Map
+	if mapName == "" {
+		mapName = defaultMap
+	}
+
+	room, err := s.AddRoom(RoomSettings{
+		Name:     req.Name,
+		Mode:     proto.GetBackendGameMode(req.Mode),
+		Map:      mapName,
+		Password: req.Password,
+		Bots:     int(req.Bots),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.CreateRoomResponse{
+		Room: room.info(),
+	}, nil
+}
+
+func (s *GameServer) Connect(ctx context.Context, req *proto.ConnectRequest) (*proto.ConnectResponse, error) {
+	if err := s.checkShutdown(); err != nil {
+		return nil, err
+	}
+	release, err := s.checkLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Once joined the client counts as a connection of its host itself.
+	defer release()
+	if err := s.checkPassword(ctx, req.Password); err != nil {
+		return nil, err
+	}
+
+	var roomID uuid.UUID
+	if req.RoomId != "" {
+		id, err := uuid.Parse(req.RoomId)
+		if err != nil {
+			return nil, err
+		}
+		roomID = id
+	}
+	room, ok := s.getRoom(roomID)
+	if !ok {
+		return nil, errors.New("room does not exist")
+	}
+	if room.settings.Password != "" && req.RoomPassword != room.settings.Password {
+		return nil, errors.New("invalid room password provided")
+	}
+
+	playerID, name, err := s.authenticate(req.Id, req.Name, req.AccountPassword)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkBanned(ctx, name); err != nil {
+		return nil, err
+	}
+
+	if room.settings.Invited != nil && !room.settings.Invited[playerID] {
+		return nil, errors.New("the room is reserved for a matched group")
+	}
+
+	if !s.claimPlayer(playerID, room) {
+		return nil, errors.New("duplicate player ID provided")
+	}
+
+	if _, err := room.join(playerID, name, req.Spectator, peerAddress(ctx)); err != nil {
+		s.releasePlayers(room, playerID)
+		return nil, err
+	}
+
+	token, claims, err := s.tokens.Issue(playerID, name, room.id, req.Spectator)
+	if err != nil {
+		s.leaveRoom(room, playerID)
+		return nil, err
+	}
+	expiresTimestamp, err := ptypes.TimestampProto(claims.Expires())
+	if err != nil {
+		s.leaveRoom(room, playerID)
+		return nil, err
 	}
-	s.mu.Unlock()
 
 	return &proto.ConnectResponse{
-		Token:    token.String(),
-		Entities: entities,
+		Token:          token,
+		Entities:       room.getEntities(),
+		PlayerId:       playerID.String(),
+		TokenExpiresAt: expiresTimestamp,
+		RoomId:         room.id.String(),
+		Map:            room.getMapRows(),
+		Mode:           proto.GetProtoGameMode(room.getMode()),
+		Rules:          proto.GetProtoRules(room.getRules()),
 	}, nil
 }
 
-func (s *GameServer) handleMoveRequest(req *proto.Request, currentClient *client) {
-	move := req.GetMove()
+// authenticate logs into an account when the server has accounts, guests
+// pick their own player ID and are only let in when it has none.
+func (s *GameServer) authenticate(id string, name string, accountPassword string) (uuid.UUID, string, error) {
+	var playerID uuid.UUID
+	if s.accounts != nil {
+		if accountPassword == "" {
+			return playerID, "", status.Error(codes.Unauthenticated, auth.ErrAccountRequired.Error())
+		}
+		account, err := s.accounts.Login(name, accountPassword)
+		if err != nil {
+			return playerID, "", err
+		}
+		playerID = account.ID
+		name = account.Name
+	} else {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return playerID, "", err
+		}
+		playerID = parsed
+	}
 
-	s.game.ActionChannel <- backend.MoveAction{
-		ID:        currentClient.playerID,
-		Direction: proto.GetBackendDirection(move.Direction),
-		Created: time.Now(),
+	if !validName.MatchString(name) {
+		return playerID, "", errors.New("invalid name provided")
 	}
+	return playerID, name, nil
 }
 
-func (s *GameServer) handleLaserRequest(req *proto.Request, currentClient *client) {
-	laser := req.GetLaser()
-	id, err := uuid.Parse(laser.Id)
+// Matchmake queues the player until a group is found and streams the queue
+// status meanwhile. The last status holds the room to Connect to.
+func (s *GameServer) Matchmake(req *proto.MatchmakeRequest, srv proto.Game_MatchmakeServer) error {
+	ctx := srv.Context()
+	if err := s.checkShutdown(); err != nil {
+		return err
+	}
+	// A queued player holds no connection, the room is joined with Connect.
+	release, err := s.checkLimits(ctx)
 	if err != nil {
-		currentClient.done <- errors.New("invalid laser ID provided")
-		return
+		return err
+	}
+	release()
+	if err := s.checkPassword(ctx, req.Password); err != nil {
+		return err
 	}
 
-	s.game.Mu.RLock()
-	if s.game.GetEntity(id) != nil {
-		currentClient.done <- errors.New("duplicate laser ID provided")
-		return
+	playerID, name, err := s.authenticate(req.Id, req.Name, req.AccountPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.checkBanned(ctx, name); err != nil {
+		return err
+	}
+	if s.isPlaying(playerID) {
+		return errors.New("player is already in a room")
+	}
+
+	entry, err := s.matchmaker.enqueue(playerID, name, proto.GetBackendGameMode(req.Mode), s.getRating(playerID))
+	if err != nil {
+		return err
+	}
+	defer s.matchmaker.dequeue(entry)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.shutdown:
+			return ErrShuttingDown
+		case status := <-entry.status:
+			if err := srv.Send(status); err != nil {
+				return err
+			}
+			if status.State == proto.QueueState_MATCHED {
+				return nil
+			}
+		}
+	}
+}
+
+func (s *GameServer) Register(ctx context.Context, req *proto.RegisterRequest) (*proto.RegisterResponse, error) {
+	if err := s.checkShutdown(); err != nil {
+		return nil, err
+	}
+	if s.accounts == nil {
+		return nil, errors.New("registration is disabled on this server")
+	}
+
+	if err := s.checkPassword(ctx, req.ServerPassword); err != nil {
+		return nil, err
 	}
-	s.game.Mu.RUnlock()
 
-	s.game.ActionChannel <- backend.LaserAction{
-		OwnerID:   currentClient.playerID,
-		ID:        id,
-		Direction: proto.GetBackendDirection(laser.Direction),
-		Created: time.Now(),
+	if !validName.MatchString(req.Name) {
+		return nil, errors.New("invalid name provided")
 	}
+
+	account, err := s.accounts.Register(req.Name, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("registered account", "player", account.ID, "name", account.Name)
+	return &proto.RegisterResponse{
+		PlayerId: account.ID.String(),
+	}, nil
 }
 
 const (
-	clientTimeout = 15
-	maxClients = 8
+	// maxClients is the most players a room can have, the spawn points of
+	// the maps are made for it.
+	maxClients         = 8
+	maxSpectators      = 8
+	defaultMap         = "default"
+	roomIdleTimeout    = 5 * time.Minute
+	pingFrequency      = 5 * time.Second
+	banReloadFrequency = 5 * time.Second
 )
 
-func (s *GameServer) getClientFromContext(ctx context.Context) (*client, error) {
-	headers, _ := metadata.FromIncomingContext(ctx)
+// Clients that have not sent anything for clientTimeout are dropped unless
+// the server sets another timeout, rooms check every timeoutCheckFrequency.
+// They are variables so tests can shorten them.
+var (
+	clientTimeout         = 15 * time.Minute
+	timeoutCheckFrequency = time.Minute
+)
 
-	tokenRaw := headers["authorization"]
-	if len(tokenRaw) == 0 {
-		return nil, errors.New("no token provided")
+var validName = regexp.MustCompile("^[a-zA-Z0-9]+$")
+
+var validRoomName = regexp.MustCompile("^[a-zA-Z0-9][a-zA-Z0-9 ]{0,23}$")
+
+// PublicMethods are the calls that hand out tokens, only describe the server
+// or lead to Connect, so they are the only ones the auth interceptors let
+// through unauthenticated.
+var PublicMethods = []string{
+	"/proto.Game/Connect",
+	"/proto.Game/Register",
+	"/proto.Game/ListRooms",
+	"/proto.Game/CreateRoom",
+	"/proto.Game/Matchmake",
+}
+
+// getClientFromContext relies on the auth interceptor having verified the
+// token. A valid token without a client means the server restarted since the
+// token was issued, so the player is restored from the token claims, into the
+// default room if their room is gone.
+func (s *GameServer) getClientFromContext(ctx context.Context) (*Room, *client, *auth.Claims, error) {
+	claims, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, nil, nil, errors.New("no token provided")
 	}
 
-	token, err := uuid.Parse(tokenRaw[0])
+	room, ok := s.getRoom(claims.RoomID)
+	if !ok {
+		room, ok = s.getRoom(uuid.Nil)
+		if !ok {
+			return nil, nil, nil, errors.New("room does not exist")
+		}
+	}
+
+	currentClient, ok := room.getClient(claims.PlayerID)
+	if ok {
+		return room, currentClient, claims, nil
+	}
+
+	if !s.claimPlayer(claims.PlayerID, room) {
+		return nil, nil, nil, errors.New("player is already in another room")
+	}
+	currentClient, err := room.join(claims.PlayerID, claims.Name, claims.Spectator, peerAddress(ctx))
 	if err != nil {
-		return nil, errors.New("cannot parse token")
+		s.releasePlayers(room, claims.PlayerID)
+		return nil, nil, nil, err
 	}
+	return room, currentClient, claims, nil
+}
 
-	s.mu.RLock()
-	currentClient, ok := s.clients[token]
-	s.mu.RUnlock()
+func (s *GameServer) leaveRoom(room *Room, playerID uuid.UUID) {
+	room.leave(playerID)
+	s.releasePlayers(room, playerID)
+	room.closeIfEmpty()
+}
 
+func (s *GameServer) RefreshToken(ctx context.Context, req *proto.RefreshTokenRequest) (*proto.RefreshTokenResponse, error) {
+	claims, ok := auth.FromContext(ctx)
 	if !ok {
-		return nil, errors.New("token not recognized")
+		return nil, errors.New("no token provided")
+	}
+
+	token, refreshed, err := s.tokens.Refresh(claims)
+	if err != nil {
+		return nil, err
+	}
+	expiresTimestamp, err := ptypes.TimestampProto(refreshed.Expires())
+	if err != nil {
+		return nil, err
 	}
-	return currentClient, nil
+
+	return &proto.RefreshTokenResponse{
+		Token:          token,
+		TokenExpiresAt: expiresTimestamp,
+	}, nil
 }
 
 func (s *GameServer) Stream(srv proto.Game_StreamServer) error {
 	ctx := srv.Context()
-	currentClient, err := s.getClientFromContext(ctx)
+	if err := s.checkShutdown(); err != nil {
+		return err
+	}
+	// A banned player may still hold a valid token.
+	if claims, ok := auth.FromContext(ctx); ok {
+		if err := s.checkBanned(ctx, claims.Name); err != nil {
+			return err
+		}
+	}
+	room, currentClient, claims, err := s.getClientFromContext(ctx)
 	if err != nil {
 		return err
 	}
+
+	room.mu.Lock()
 	if currentClient.streamServer != nil {
+		room.mu.Unlock()
 		return errors.New("stream already active")
 	}
 	currentClient.streamServer = srv
+	currentClient.address = peerAddress(ctx)
+	currentClient.limiter = newRequestLimiter(room.getRules)
+	room.mu.Unlock()
+
+	connectedClients.Add(1)
+	defer connectedClients.Add(-1)
 
-	log.Println("start new server")
+	currentClient.logger.Info("stream started")
+	room.sendMapVote(currentClient.playerID)
 
 	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				currentClient.logger.Error("request handler panicked", "panic", r)
+				currentClient.stop(errors.New("failed to handle request"))
+			}
+		}()
+
 		for {
 			req, err := srv.Recv()
 			if err != nil {
-				log.Printf("receive error %v", err)
-				currentClient.done <- errors.New("failed to receive request")
+				currentClient.logger.Debug("receive failed", "error", err)
+				currentClient.stop(errors.New("failed to receive request"))
+				return
+			}
+			requestType := messageType(req.GetAction())
+			currentClient.requestLogger.Debug("request", "type", requestType)
+
+			switch currentClient.limiter.check(requestType, time.Now()) {
+			case floodDrop:
+				requestsDropped.Inc(requestType)
+				continue
+			case floodWarn:
+				requestsDropped.Inc(requestType)
+				currentClient.logger.Warn("client is flooding", "type", requestType)
+				room.sendSystemMessage(currentClient.playerID, "you are sending too many requests, slow down or you will be disconnected")
+				continue
+			case floodDisconnect:
+				requestsDropped.Inc(requestType)
+				floodDisconnects.Inc()
+				currentClient.logger.Warn("disconnecting flooding client", "type", requestType)
+				currentClient.stop(status.Error(codes.ResourceExhausted, "disconnected for sending too many requests"))
 				return
 			}
-			log.Printf("got message %+v", req)
-			currentClient.lastMessage = time.Now()
 
-			switch req.GetAction().(type) {
-			case *proto.Request_Move:
-				s.handleMoveRequest(req, currentClient)
-			case *proto.Request_Laser:
-				s.handleLaserRequest(req, currentClient)
+			// Pongs are answered automatically, so they do not count as
+			// activity for the timeout.
+			if pong := req.GetPong(); pong != nil {
+				currentClient.setLatency(time.Since(pong.PingSentAt.AsTime()))
+				continue
 			}
+			currentClient.lastMessage = time.Now()
+
+			room.handleRequest(req, currentClient)
 		}
 	}()
 
@@ -387,11 +950,12 @@ func (s *GameServer) Stream(srv proto.Game_StreamServer) error {
 	case doneError = <-currentClient.done:
 	}
 
-	log.Printf(`stream done with error "%v"`, doneError)
-	log.Printf("%s - removing client", currentClient.id)
+	currentClient.logger.Info("stream done", "error", doneError)
 
-	s.removeClient(currentClient.id)
-	s.removePlayer(currentClient.playerID)
+	s.leaveRoom(room, currentClient.playerID)
+	if err := s.tokens.Revoke(claims); err != nil {
+		currentClient.logger.Error("failed to save token revocation", "error", err)
+	}
 
 	return doneError
 }
@@ -0,0 +1,1280 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/nikit34/multiplayer_rpg/pkg/auth"
+	"github.com/nikit34/multiplayer_rpg/pkg/backend"
+	gameclient "github.com/nikit34/multiplayer_rpg/pkg/client"
+	"github.com/nikit34/multiplayer_rpg/pkg/frontend"
+	"github.com/nikit34/multiplayer_rpg/pkg/logging"
+	"github.com/nikit34/multiplayer_rpg/pkg/metrics"
+	proto "github.com/nikit34/multiplayer_rpg/proto"
+)
+
+const (
+	testPassword   = "secret"
+	testAdminToken = "admin secret"
+	testMap        = "test"
+	// convergeTimeout is how long clients may take to catch up with the
+	// server.
+	convergeTimeout = 5 * time.Second
+)
+
+func TestMain(m *testing.M) {
+	// Every message is logged, which drowns the test output.
+	logging.Configure(ioutil.Discard, logging.LevelError, logging.FormatText)
+
+	// Every spawn point is on one row, so players can always line up a shot.
+	backend.Maps[testMap] = backend.ParseMap([]string{
+		"███████████",
+		"█         █",
+		"█ S  S  S █",
+		"█         █",
+		"███████████",
+	})
+	os.Exit(m.Run())
+}
+
+type testServer struct {
+	t          *testing.T
+	server     *GameServer
+	room       *Room
+	grpcClient proto.GameClient
+	admin      proto.AdminClient
+	health     healthpb.HealthClient
+}
+
+// newTestServer serves a GameServer with one room over an in-memory
+// connection, the same way cmd/server does over TCP.
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	return newAccountTestServer(t, nil)
+}
+
+// newAccountTestServer is newTestServer for a server with accounts.
+func newAccountTestServer(t *testing.T, accounts *auth.Accounts) *testServer {
+	t.Helper()
+
+	revocations, err := auth.NewRevocations("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := auth.NewTokens(auth.NewSecret(), "test", time.Hour, revocations)
+	publicMethods := append(append([]string{}, PublicMethods...), AdminMethods...)
+	publicMethods = append(publicMethods, HealthMethods...)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			tokens.UnaryServerInterceptor(publicMethods...),
+			UnaryAdminInterceptor(testAdminToken),
+		),
+		grpc.StreamInterceptor(tokens.StreamServerInterceptor(publicMethods...)),
+	)
+	gameServer := NewGameServer(testPassword, accounts, tokens, 4)
+	gameServer.SetRNG(backend.NewRNG(1))
+	room, err := gameServer.AddRoom(RoomSettings{
+		Name:       "Main",
+		Map:        testMap,
+		Persistent: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	proto.RegisterGameServer(grpcServer, gameServer)
+	proto.RegisterAdminServer(grpcServer, NewAdminServer(gameServer))
+	healthServer := health.NewServer()
+	gameServer.SetHealth(healthServer)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	listener := bufconn.Listen(1 << 20)
+	go grpcServer.Serve(listener)
+
+	conn, err := grpc.Dial(
+		"bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		grpcServer.Stop()
+		room.close(nil)
+	})
+
+	return &testServer{
+		t:          t,
+		server:     gameServer,
+		room:       room,
+		grpcClient: proto.NewGameClient(conn),
+		admin:      proto.NewAdminClient(conn),
+		health:     healthpb.NewHealthClient(conn),
+	}
+}
+
+func adminContext(token string) context.Context {
+	header := metadata.New(map[string]string{"authorization": token})
+	return metadata.NewOutgoingContext(context.Background(), header)
+}
+
+// connect joins the room with a client whose view is never started, so it
+// runs without a terminal.
+func (ts *testServer) connect(name string) *gameclient.GameClient {
+	ts.t.Helper()
+
+	game := backend.NewGame()
+	game.IsAuthoritative = false
+	view := frontend.NewView(game)
+	game.Start()
+	ts.t.Cleanup(game.Stop)
+
+	gameClient := gameclient.NewGameClient(game, view)
+	err := gameClient.Connect(ts.grpcClient, gameclient.ConnectOptions{
+		PlayerID:   uuid.New(),
+		PlayerName: name,
+		Password:   testPassword,
+	})
+	if err != nil {
+		ts.t.Fatalf("%s can not connect: %v", name, err)
+	}
+	gameClient.Start()
+
+	// Broadcasts only reach clients with a stream, so wait for it before the
+	// next player joins.
+	eventually(ts.t, fmt.Sprintf("stream of %s", name), func() error {
+		ts.room.mu.RLock()
+		defer ts.room.mu.RUnlock()
+
+		currentClient, ok := ts.room.clients[gameClient.CurrentPlayer]
+		if !ok || currentClient.streamServer == nil {
+			return fmt.Errorf("no stream yet")
+		}
+		return nil
+	})
+	return gameClient
+}
+
+// connectRaw only calls Connect, it is used to check the errors of a join.
+func (ts *testServer) connectRaw(id uuid.UUID, name string, password string) (*proto.ConnectResponse, error) {
+	return ts.grpcClient.Connect(context.Background(), &proto.ConnectRequest{
+		Id:       id.String(),
+		Name:     name,
+		Password: password,
+	})
+}
+
+// stream opens a game stream with the token of a Connect response.
+func (ts *testServer) stream(resp *proto.ConnectResponse) proto.Game_StreamClient {
+	ts.t.Helper()
+
+	header := metadata.New(map[string]string{"authorization": resp.Token})
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(context.Background(), header))
+	ts.t.Cleanup(cancel)
+
+	stream, err := ts.grpcClient.Stream(ctx)
+	if err != nil {
+		ts.t.Fatal(err)
+	}
+	return stream
+}
+
+// waitForStream waits until the server handles the stream of a player, so
+// nothing sent to the player is skipped.
+func (ts *testServer) waitForStream(resp *proto.ConnectResponse) {
+	ts.t.Helper()
+
+	eventually(ts.t, "stream of "+resp.PlayerId, func() error {
+		ts.room.mu.RLock()
+		defer ts.room.mu.RUnlock()
+
+		currentClient, ok := ts.room.clients[uuid.MustParse(resp.PlayerId)]
+		if !ok || currentClient.streamServer == nil {
+			return fmt.Errorf("no stream yet")
+		}
+		return nil
+	})
+}
+
+func eventually(t *testing.T, what string, check func() error) {
+	t.Helper()
+
+	deadline := time.Now().Add(convergeTimeout)
+	for {
+		err := check()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s: %v", what, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// gameState is what all clients have to agree on, lasers are in flight too
+// briefly to compare.
+type gameState struct {
+	positions map[uuid.UUID]backend.Coordinate
+	scores    map[uuid.UUID]int
+}
+
+func getGameState(game *backend.Game) gameState {
+	game.Mu.RLock()
+	defer game.Mu.RUnlock()
+
+	state := gameState{
+		positions: make(map[uuid.UUID]backend.Coordinate),
+		scores:    make(map[uuid.UUID]int),
+	}
+	for _, entity := range game.Entities {
+		if player, ok := entity.(*backend.Player); ok {
+			state.positions[player.ID()] = player.Position()
+		}
+	}
+	for id, score := range game.Score {
+		if score != 0 {
+			state.scores[id] = score
+		}
+	}
+	return state
+}
+
+func (state gameState) diff(other gameState) error {
+	if len(state.positions) != len(other.positions) {
+		return fmt.Errorf("%d players instead of %d", len(other.positions), len(state.positions))
+	}
+	for id, position := range state.positions {
+		if other.positions[id] != position {
+			return fmt.Errorf("player %s is at %v instead of %v", id, other.positions[id], position)
+		}
+	}
+	if len(state.scores) != len(other.scores) {
+		return fmt.Errorf("scores %v instead of %v", other.scores, state.scores)
+	}
+	for id, score := range state.scores {
+		if other.scores[id] != score {
+			return fmt.Errorf("scores %v instead of %v", other.scores, state.scores)
+		}
+	}
+	return nil
+}
+
+func (ts *testServer) converge(clients ...*gameclient.GameClient) gameState {
+	ts.t.Helper()
+
+	var want gameState
+	eventually(ts.t, "clients converge", func() error {
+		want = getGameState(ts.room.game)
+		for _, gameClient := range clients {
+			if err := want.diff(getGameState(gameClient.Game)); err != nil {
+				return fmt.Errorf("client %s: %v", gameClient.CurrentPlayer, err)
+			}
+		}
+		return nil
+	})
+	return want
+}
+
+func move(gameClient *gameclient.GameClient, direction backend.Direction) {
+	gameClient.Game.SendAction(backend.MoveAction{
+		ID:        gameClient.CurrentPlayer,
+		Direction: direction,
+		Created:   time.Now(),
+	})
+	// Stay clear of the move throttle on both sides.
+	time.Sleep(150 * time.Millisecond)
+}
+
+func TestClientsConverge(t *testing.T) {
+	ts := newTestServer(t)
+
+	alice := ts.connect("Alice")
+	bob := ts.connect("Bob")
+	carol := ts.connect("Carol")
+	clients := []*gameclient.GameClient{alice, bob, carol}
+	state := ts.converge(clients...)
+	if len(state.positions) != 3 {
+		t.Fatalf("%d players in the game, want 3", len(state.positions))
+	}
+
+	for _, direction := range []backend.Direction{
+		backend.DirectionUp,
+		backend.DirectionRight,
+		backend.DirectionDown,
+		backend.DirectionDown,
+		backend.DirectionLeft,
+	} {
+		for _, gameClient := range clients {
+			move(gameClient, direction)
+		}
+	}
+	ts.converge(clients...)
+
+	// Put Alice and Bob on the spawn row next to each other and let Alice
+	// shoot Bob.
+	state = ts.converge(clients...)
+	for state.positions[alice.CurrentPlayer].Y > 0 {
+		move(alice, backend.DirectionUp)
+		state = ts.converge(clients...)
+	}
+	for state.positions[bob.CurrentPlayer].Y > 0 {
+		move(bob, backend.DirectionUp)
+		state = ts.converge(clients...)
+	}
+	for state.positions[alice.CurrentPlayer].Y < 0 {
+		move(alice, backend.DirectionDown)
+		state = ts.converge(clients...)
+	}
+	for state.positions[bob.CurrentPlayer].Y < 0 {
+		move(bob, backend.DirectionDown)
+		state = ts.converge(clients...)
+	}
+	// Carol could stand in the line of fire.
+	if state.positions[carol.CurrentPlayer].Y == 0 {
+		move(carol, backend.DirectionUp)
+		state = ts.converge(clients...)
+	}
+	if state.positions[alice.CurrentPlayer] == state.positions[bob.CurrentPlayer] {
+		move(alice, backend.DirectionRight)
+		state = ts.converge(clients...)
+	}
+
+	direction := backend.DirectionRight
+	if state.positions[bob.CurrentPlayer].X < state.positions[alice.CurrentPlayer].X {
+		direction = backend.DirectionLeft
+	}
+	alice.Game.SendAction(backend.LaserAction{
+		ID:        uuid.New(),
+		OwnerID:   alice.CurrentPlayer,
+		Direction: direction,
+		Created:   time.Now(),
+	})
+
+	eventually(t, "Alice scores", func() error {
+		state = ts.converge(clients...)
+		if state.scores[alice.CurrentPlayer] != 1 {
+			return fmt.Errorf("scores %v", state.scores)
+		}
+		return nil
+	})
+}
+
+func TestConnectErrors(t *testing.T) {
+	ts := newTestServer(t)
+
+	_, err := ts.connectRaw(uuid.New(), "Alice", "wrong")
+	assertError(t, err, "invalid password provided")
+
+	_, err = ts.connectRaw(uuid.New(), "Al ice", testPassword)
+	assertError(t, err, "invalid name provided")
+
+	id := uuid.New()
+	if _, err := ts.connectRaw(id, "Alice", testPassword); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ts.connectRaw(id, "Alice", testPassword)
+	assertError(t, err, "duplicate player ID provided")
+
+	for i := 1; i < maxClients; i++ {
+		if _, err := ts.connectRaw(uuid.New(), fmt.Sprintf("Bob%d", i), testPassword); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = ts.connectRaw(uuid.New(), "Carol", testPassword)
+	assertError(t, err, ErrRoomFull.Error())
+}
+
+func TestAccountConnect(t *testing.T) {
+	accounts, err := auth.NewAccounts(filepath.Join(t.TempDir(), "accounts.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := newAccountTestServer(t, accounts)
+
+	_, err = ts.connectRaw(uuid.New(), "Alice", testPassword)
+	assertError(t, err, auth.ErrAccountRequired.Error())
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Fatalf("got code %v, want %v", code, codes.Unauthenticated)
+	}
+
+	registered, err := ts.grpcClient.Register(context.Background(), &proto.RegisterRequest{
+		Name:           "Alice",
+		Password:       "alice password",
+		ServerPassword: testPassword,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	connect := func(password string) (*proto.ConnectResponse, error) {
+		return ts.grpcClient.Connect(context.Background(), &proto.ConnectRequest{
+			Id:              uuid.New().String(),
+			Name:            "alice",
+			Password:        testPassword,
+			AccountPassword: password,
+		})
+	}
+	_, err = connect("wrong password")
+	assertError(t, err, auth.ErrInvalidCredentials.Error())
+
+	// The account decides the player ID and the name.
+	resp, err := connect("alice password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.PlayerId != registered.PlayerId {
+		t.Errorf("got player ID %s, want the account's %s", resp.PlayerId, registered.PlayerId)
+	}
+}
+
+func TestConnectLimits(t *testing.T) {
+	ts := newTestServer(t)
+	ts.server.SetConnectLimits(2, 4)
+
+	for _, name := range []string{"Alice", "Bob"} {
+		if _, err := ts.connectRaw(uuid.New(), name, testPassword); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := ts.connectRaw(uuid.New(), "Carol", testPassword)
+	assertError(t, err, "too many connections from your address")
+	if code := status.Code(err); code != codes.ResourceExhausted {
+		t.Fatalf("got code %v, want %v", code, codes.ResourceExhausted)
+	}
+
+	// The refused attempt still counts, the fourth is the last one allowed.
+	_, err = ts.connectRaw(uuid.New(), "Carol", testPassword)
+	assertError(t, err, "too many connections from your address")
+	_, err = ts.connectRaw(uuid.New(), "Carol", testPassword)
+	assertError(t, err, "too many connection attempts")
+	if code := status.Code(err); code != codes.ResourceExhausted {
+		t.Fatalf("got code %v, want %v", code, codes.ResourceExhausted)
+	}
+}
+
+func TestConnectLimitsConcurrent(t *testing.T) {
+	ts := newTestServer(t)
+	ts.server.SetConnectLimits(2, 0)
+
+	// A failed connect gives its connection back.
+	for i := 0; i < 3; i++ {
+		_, err := ts.connectRaw(uuid.New(), "Alice", "wrong")
+		assertError(t, err, "invalid password provided")
+	}
+
+	const attempts = 8
+	results := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		go func(i int) {
+			_, err := ts.connectRaw(uuid.New(), fmt.Sprintf("Bob%d", i), testPassword)
+			results <- err
+		}(i)
+	}
+	connected := 0
+	for i := 0; i < attempts; i++ {
+		err := <-results
+		if err == nil {
+			connected++
+			continue
+		}
+		assertError(t, err, "too many connections from your address")
+	}
+	if connected != 2 {
+		t.Fatalf("got %d connections, want the limit of 2", connected)
+	}
+}
+
+func TestReserveConnection(t *testing.T) {
+	limits := newConnectLimits()
+	limits.set(2, 0)
+
+	// Reservations count like connected clients until they are released.
+	if !limits.reserve("10.0.0.1") || !limits.reserve("10.0.0.1") {
+		t.Fatal("could not reserve the connections within the limit")
+	}
+	if limits.reserve("10.0.0.1") {
+		t.Fatal("reserved a connection over the limit")
+	}
+	if !limits.reserve("10.0.0.2") {
+		t.Fatal("the limit of one host applies to another")
+	}
+
+	// A client that joined is counted instead of its reservation.
+	joined := &client{playerID: uuid.New(), host: "10.0.0.1"}
+	limits.connect(nil, joined)
+	limits.release("10.0.0.1")
+	if limits.reserve("10.0.0.1") {
+		t.Fatal("reserved the connection of a joined client")
+	}
+
+	// A client that failed to join gives its connection back.
+	limits.release("10.0.0.1")
+	if !limits.reserve("10.0.0.1") {
+		t.Fatal("a released reservation still counts")
+	}
+	limits.release("10.0.0.1")
+	limits.disconnect(joined)
+	if !limits.reserve("10.0.0.1") || !limits.reserve("10.0.0.1") {
+		t.Fatal("the connections of a client that left still count")
+	}
+}
+
+func TestConnectIgnoresBusyRooms(t *testing.T) {
+	ts := newTestServer(t)
+	ts.server.SetConnectLimits(2, 0)
+	other, err := ts.server.AddRoom(RoomSettings{Name: "Other", Map: testMap})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A room stuck sending to a slow client holds its lock.
+	other.mu.Lock()
+	connected := make(chan error, 1)
+	go func() {
+		_, err := ts.connectRaw(uuid.New(), "Alice", testPassword)
+		connected <- err
+	}()
+	select {
+	case err := <-connected:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("connect waited for another room")
+	}
+	other.mu.Unlock()
+}
+
+func TestBannedConnect(t *testing.T) {
+	ts := newTestServer(t)
+
+	err := ts.server.Bans().Add(Ban{Account: "alice", Reason: "cheating", Created: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ts.connectRaw(uuid.New(), "Alice", testPassword)
+	assertError(t, err, "you are banned from this server: cheating")
+	if code := status.Code(err); code != codes.PermissionDenied {
+		t.Fatalf("got code %v, want %v", code, codes.PermissionDenied)
+	}
+
+	err = ts.server.Bans().Add(Ban{Account: "Bob", Created: time.Now(), Expires: time.Now().Add(-time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.connectRaw(uuid.New(), "Bob", testPassword); err != nil {
+		t.Fatalf("expired ban still applies: %v", err)
+	}
+}
+
+func TestFloodDisconnect(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, err := ts.connectRaw(uuid.New(), "Alice", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := ts.stream(resp)
+
+	move := &proto.Request{
+		Action: &proto.Request_Move{
+			Move: &proto.Move{Direction: proto.Direction_LEFT},
+		},
+	}
+	go func() {
+		for i := 0; i < 2*floodDisconnectDrops; i++ {
+			if err := stream.Send(move); err != nil {
+				return
+			}
+		}
+	}()
+
+	warned := false
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			assertError(t, err, "disconnected for sending too many requests")
+			if code := status.Code(err); code != codes.ResourceExhausted {
+				t.Fatalf("got code %v, want %v", code, codes.ResourceExhausted)
+			}
+			break
+		}
+		if message := resp.GetChatMessage(); message != nil && strings.Contains(message.Text, "too many requests") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Fatal("the client was not warned before being disconnected")
+	}
+}
+
+func TestRequestLimitFollowsRules(t *testing.T) {
+	rules := backend.DefaultRules()
+	limiter := newRequestLimiter(func() backend.Rules {
+		return rules
+	})
+	// allowed counts the lasers let through in a second of steady firing
+	// after the burst is used up. The steps are exact in floating point, so
+	// the tokens add up to whole ones.
+	allowed := func(start time.Time) int {
+		for limiter.check("Laser", start) == floodAllow {
+		}
+		n := 0
+		for i := 1; i <= 64; i++ {
+			if limiter.check("Laser", start.Add(time.Duration(i)*time.Second/64)) == floodAllow {
+				n++
+			}
+		}
+		return n
+	}
+
+	start := time.Now()
+	want := int(throttleSlack / rules.LaserThrottle.Seconds())
+	if n := allowed(start); n != want {
+		t.Errorf("allowed %d lasers a second with the default rules, want %d", n, want)
+	}
+
+	rules.LaserThrottle = 100 * time.Millisecond
+	want = int(throttleSlack / rules.LaserThrottle.Seconds())
+	if n := allowed(start.Add(time.Minute)); n != want {
+		t.Errorf("allowed %d lasers a second after the throttle changed, want %d", n, want)
+	}
+
+	rules.LaserThrottle = 0
+	if n := allowed(start.Add(2 * time.Minute)); n != maxActionRate {
+		t.Errorf("allowed %d lasers a second without a throttle, want %d", n, maxActionRate)
+	}
+}
+
+func TestInvalidLaserID(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, err := ts.connectRaw(uuid.New(), "Alice", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := ts.stream(resp)
+	err = stream.Send(&proto.Request{
+		Action: &proto.Request_Laser{
+			Laser: &proto.Laser{Id: "not a uuid"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = receiveUntilError(stream)
+	assertError(t, err, "invalid laser ID provided")
+}
+
+func laserRequest(id uuid.UUID, direction proto.Direction) *proto.Request {
+	return &proto.Request{
+		Action: &proto.Request_Laser{
+			Laser: &proto.Laser{Id: id.String(), Direction: direction},
+		},
+	}
+}
+
+// receiveLaserAck skips the responses before the next laser ack.
+func receiveLaserAck(t *testing.T, stream proto.Game_StreamClient) *proto.LaserAck {
+	t.Helper()
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ack := resp.GetLaserAck(); ack != nil {
+			return ack
+		}
+	}
+}
+
+func TestLaserAck(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, err := ts.connectRaw(uuid.New(), "Alice", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := ts.stream(resp)
+	playerID := uuid.MustParse(resp.PlayerId)
+	player := ts.room.getPlayer(playerID)
+
+	clientID := uuid.New()
+	if err := stream.Send(laserRequest(clientID, proto.Direction_RIGHT)); err != nil {
+		t.Fatal(err)
+	}
+	ack := receiveLaserAck(t, stream)
+	if ack.ClientId != clientID.String() || ack.Laser == nil {
+		t.Fatalf("got ack %+v, want the laser for %s", ack, clientID)
+	}
+	if ack.Laser.Id == clientID.String() {
+		t.Fatal("the server kept the laser ID of the client")
+	}
+	if ack.Laser.OwnerId != resp.PlayerId {
+		t.Fatalf("got owner %s, want %s", ack.Laser.OwnerId, resp.PlayerId)
+	}
+	want := player.Position()
+	want.X++
+	if got := proto.GetBackendCoordinate(ack.Laser.InitialPosition); got != want {
+		t.Fatalf("got initial position %v, want %v", got, want)
+	}
+}
+
+func TestLaserRejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction proto.Direction
+		// prepare runs after the player connected.
+		prepare func(ts *testServer, stream proto.Game_StreamClient, playerID uuid.UUID)
+		want    string
+	}{
+		{
+			name:      "invalid direction",
+			direction: proto.Direction_STOP,
+			want:      "invalid laser direction",
+		},
+		{
+			name:      "round over",
+			direction: proto.Direction_RIGHT,
+			prepare: func(ts *testServer, stream proto.Game_StreamClient, playerID uuid.UUID) {
+				ts.room.game.EndRound()
+			},
+			want: "the round is over",
+		},
+		{
+			name:      "throttled",
+			direction: proto.Direction_RIGHT,
+			prepare: func(ts *testServer, stream proto.Game_StreamClient, playerID uuid.UUID) {
+				if err := stream.Send(laserRequest(uuid.New(), proto.Direction_LEFT)); err != nil {
+					ts.t.Fatal(err)
+				}
+				if ack := receiveLaserAck(ts.t, stream); ack.Laser == nil {
+					ts.t.Fatalf("first laser was rejected: %s", ack.RejectedReason)
+				}
+			},
+			want: "you are shooting too quickly",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ts := newTestServer(t)
+
+			resp, err := ts.connectRaw(uuid.New(), "Alice", testPassword)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stream := ts.stream(resp)
+			if test.prepare != nil {
+				test.prepare(ts, stream, uuid.MustParse(resp.PlayerId))
+			}
+
+			clientID := uuid.New()
+			if err := stream.Send(laserRequest(clientID, test.direction)); err != nil {
+				t.Fatal(err)
+			}
+			ack := receiveLaserAck(t, stream)
+			if ack.ClientId != clientID.String() {
+				t.Fatalf("got ack for %s, want %s", ack.ClientId, clientID)
+			}
+			if ack.Laser != nil || ack.RejectedReason != test.want {
+				t.Fatalf("got ack %+v, want rejection %q", ack, test.want)
+			}
+		})
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, err := ts.connectRaw(uuid.New(), "Alice", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := ts.stream(resp)
+	ts.waitForStream(resp)
+
+	shutdownDone := make(chan struct{})
+	go func() {
+		ts.server.Shutdown(100 * time.Millisecond)
+		close(shutdownDone)
+	}()
+
+	warned := false
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			assertError(t, err, "the server is shutting down")
+			if code := status.Code(err); code != codes.Unavailable {
+				t.Fatalf("got code %v, want %v", code, codes.Unavailable)
+			}
+			break
+		}
+		if message := resp.GetChatMessage(); message != nil && strings.Contains(message.Text, "shutting down in") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Fatal("the player was not told about the shutdown")
+	}
+
+	_, err = ts.connectRaw(uuid.New(), "Bob", testPassword)
+	if code := status.Code(err); code != codes.Unavailable {
+		t.Fatalf("got code %v connecting during the shutdown, want %v", code, codes.Unavailable)
+	}
+	<-shutdownDone
+	if rooms := ts.server.getRooms(); len(rooms) != 0 {
+		t.Fatalf("got %d rooms after the shutdown, want none", len(rooms))
+	}
+}
+
+func TestHealth(t *testing.T) {
+	ts := newTestServer(t)
+	checkHealth := func(service string, want healthpb.HealthCheckResponse_ServingStatus) error {
+		resp, err := ts.health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return err
+		}
+		if resp.Status != want {
+			return fmt.Errorf("got status %s for %q, want %s", resp.Status, service, want)
+		}
+		return nil
+	}
+	assertHealth := func(service string, want healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		if err := checkHealth(service, want); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assertHealth("", healthpb.HealthCheckResponse_SERVING)
+	assertHealth("proto.Game", healthpb.HealthCheckResponse_SERVING)
+	assertHealth(RoomHealthService(ts.room.id), healthpb.HealthCheckResponse_SERVING)
+
+	room, err := ts.server.AddRoom(RoomSettings{Name: "Other", Map: testMap})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHealth(RoomHealthService(room.id), healthpb.HealthCheckResponse_SERVING)
+	room.close(nil)
+	assertHealth(RoomHealthService(room.id), healthpb.HealthCheckResponse_NOT_SERVING)
+	assertHealth("", healthpb.HealthCheckResponse_SERVING)
+
+	// The server drains while a player is connected.
+	resp, err := ts.connectRaw(uuid.New(), "Alice", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.stream(resp)
+	shutdownDone := make(chan struct{})
+	go func() {
+		ts.server.Shutdown(time.Second)
+		close(shutdownDone)
+	}()
+	eventually(t, "server draining", func() error {
+		return checkHealth("", healthpb.HealthCheckResponse_NOT_SERVING)
+	})
+	assertHealth(RoomHealthService(ts.room.id), healthpb.HealthCheckResponse_NOT_SERVING)
+	<-shutdownDone
+}
+
+func TestStreamTimeout(t *testing.T) {
+	defer func(timeout time.Duration, frequency time.Duration) {
+		clientTimeout = timeout
+		timeoutCheckFrequency = frequency
+	}(clientTimeout, timeoutCheckFrequency)
+	clientTimeout = 50 * time.Millisecond
+	timeoutCheckFrequency = 10 * time.Millisecond
+
+	ts := newTestServer(t)
+
+	resp, err := ts.connectRaw(uuid.New(), "Alice", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := ts.stream(resp)
+
+	_, err = receiveUntilError(stream)
+	assertError(t, err, "you have been timed out")
+
+	if ts.room.hasPlayer(uuid.MustParse(resp.PlayerId)) {
+		t.Error("the timed out player is still in the game")
+	}
+}
+
+func TestAdmin(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := adminContext(testAdminToken)
+
+	_, err := ts.admin.ListPlayers(adminContext("wrong"), &proto.ListPlayersRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("got %v with a wrong admin token, want Unauthenticated", err)
+	}
+
+	alice := ts.connect("Alice")
+	if _, err := ts.admin.AddBot(ctx, &proto.AddBotRequest{Name: "Robo"}); err != nil {
+		t.Fatal(err)
+	}
+	ts.converge(alice)
+	players, err := ts.admin.ListPlayers(ctx, &proto.ListPlayersRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, player := range players.Players {
+		names = append(names, fmt.Sprintf("%s bot=%v", player.Name, player.Bot))
+	}
+	if got := strings.Join(names, ", "); got != "Alice bot=false, Robo bot=true" {
+		t.Fatalf("players %s", got)
+	}
+
+	if _, err := ts.admin.RemoveBot(ctx, &proto.RemoveBotRequest{Name: "Robo"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ts.admin.RemoveBot(ctx, &proto.RemoveBotRequest{Name: "Alice"})
+	assertError(t, err, "the room has no bot named")
+	ts.converge(alice)
+
+	if _, err := ts.admin.ChangeMap(ctx, &proto.ChangeMapRequest{Mode: "tdm"}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "Alice plays team deathmatch", func() error {
+		alice.Game.Mu.RLock()
+		defer alice.Game.Mu.RUnlock()
+		if alice.Game.Mode != backend.ModeTeamDeathmatch {
+			return fmt.Errorf("mode %v", alice.Game.Mode)
+		}
+		return nil
+	})
+
+	resp, err := ts.connectRaw(uuid.New(), "Bob", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob := ts.stream(resp)
+	ts.waitForStream(resp)
+	if _, err := ts.admin.Kick(ctx, &proto.KickRequest{Player: "bob", Reason: "spam"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = receiveUntilError(bob)
+	assertError(t, err, "you have been kicked: spam")
+
+	if _, err := ts.admin.Ban(ctx, &proto.BanRequest{Account: "Carol", Reason: "cheating"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ts.connectRaw(uuid.New(), "carol", testPassword)
+	assertError(t, err, "you are banned from this server: cheating")
+	if _, err := ts.admin.Unban(ctx, &proto.UnbanRequest{Account: "Carol"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.connectRaw(uuid.New(), "Carol", testPassword); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMutedChat(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, err := ts.connectRaw(uuid.New(), "Alice", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := ts.stream(resp)
+	if _, err := ts.admin.Mute(adminContext(testAdminToken), &proto.MuteRequest{Player: resp.PlayerId, Muted: true}); err != nil {
+		t.Fatal(err)
+	}
+	err = stream.Send(&proto.Request{
+		Action: &proto.Request_Chat{
+			Chat: &proto.Chat{Text: "hello"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		message := resp.GetChatMessage()
+		if message == nil {
+			continue
+		}
+		if message.Channel != proto.ChatChannel_SYSTEM || message.Text != "you have been muted" {
+			t.Fatalf("got %v, want the muted notice", message)
+		}
+		return
+	}
+}
+
+func TestWhisper(t *testing.T) {
+	ts := newTestServer(t)
+	if _, err := ts.room.addBot("Bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	aliceResp, err := ts.connectRaw(uuid.New(), "Alice", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := ts.stream(aliceResp)
+	carolResp, err := ts.grpcClient.Connect(context.Background(), &proto.ConnectRequest{
+		Id:        uuid.New().String(),
+		Name:      "Carol",
+		Password:  testPassword,
+		Spectator: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	carol := ts.stream(carolResp)
+	ts.waitForStream(aliceResp)
+	ts.waitForStream(carolResp)
+
+	whisper := func(recipient string, text string) {
+		err := alice.Send(&proto.Request{
+			Action: &proto.Request_Chat{
+				Chat: &proto.Chat{Channel: proto.ChatChannel_WHISPER, Recipient: recipient, Text: text},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	nextMessage := func(stream proto.Game_StreamClient) *proto.ChatMessage {
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if message := resp.GetChatMessage(); message != nil {
+				return message
+			}
+		}
+	}
+
+	// Spectators have no entity but can be whispered to.
+	whisper("carol", "hello")
+	message := nextMessage(carol)
+	if message.Channel != proto.ChatChannel_WHISPER || message.Text != "hello" || message.SenderName != "Alice" || message.RecipientName != "Carol" {
+		t.Fatalf("Carol got %v, want the whisper of Alice", message)
+	}
+	if message := nextMessage(alice); message.Channel != proto.ChatChannel_WHISPER || message.Text != "hello" {
+		t.Fatalf("Alice got %v, want a copy of the whisper", message)
+	}
+
+	whisper("Bob", "hello")
+	if message := nextMessage(alice); message.Channel != proto.ChatChannel_SYSTEM || message.Text != "Bob is a bot and cannot read whispers" {
+		t.Fatalf("got %v, want the notice that Bob is a bot", message)
+	}
+	whisper("Dave", "hello")
+	if message := nextMessage(alice); message.Channel != proto.ChatChannel_SYSTEM || message.Text != "no player named Dave" {
+		t.Fatalf("got %v, want the notice that there is no Dave", message)
+	}
+}
+
+func TestMapRotation(t *testing.T) {
+	ts := newTestServer(t)
+	if err := ts.server.SetRotation([]string{testMap, "arena"}); err != nil {
+		t.Fatal(err)
+	}
+	rules := backend.DefaultRules()
+	rules.NewRoundWait = 200 * time.Millisecond
+	ts.room.game.Mu.Lock()
+	ts.room.game.SetRules(rules)
+	ts.room.game.Mu.Unlock()
+	// The next round is played by the new rules.
+	rules.RoundOverScore = 5
+	ts.server.SetRules(rules)
+
+	resp, err := ts.connectRaw(uuid.New(), "Alice", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := proto.GetBackendRules(resp.Rules).RoundOverScore; got != 10 {
+		t.Fatalf("got round over score %d on connect, want 10", got)
+	}
+	stream := ts.stream(resp)
+	ts.room.game.EndRound()
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		roundStart := resp.GetRoundStart()
+		if roundStart == nil {
+			continue
+		}
+		if !reflect.DeepEqual(roundStart.Map, backend.MapRows(backend.MapArena)) {
+			t.Fatalf("got map %v, want arena", roundStart.Map)
+		}
+		if got := proto.GetBackendRules(roundStart.Rules); got != rules {
+			t.Fatalf("got rules %+v, want %+v", got, rules)
+		}
+		break
+	}
+	if got := ts.room.info().Map; got != "arena" {
+		t.Fatalf("got room map %q, want arena", got)
+	}
+}
+
+func TestMapVote(t *testing.T) {
+	ts := newTestServer(t)
+	if err := ts.server.SetRotation([]string{testMap, "arena", "arena:tdm"}); err != nil {
+		t.Fatal(err)
+	}
+	rules := backend.DefaultRules()
+	rules.NewRoundWait = 500 * time.Millisecond
+	ts.room.game.Mu.Lock()
+	ts.room.game.SetRules(rules)
+	ts.room.game.Mu.Unlock()
+
+	resp, err := ts.connectRaw(uuid.New(), "Alice", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := ts.stream(resp)
+	ts.room.game.EndRound()
+
+	mapVote := receiveMapVote(t, stream)
+	var got []string
+	for _, candidate := range mapVote.Candidates {
+		got = append(got, fmt.Sprintf("%s %s %d", candidate.Map, candidate.Mode, candidate.Votes))
+	}
+	want := []string{"arena DEATHMATCH 0", "arena TEAM_DEATHMATCH 0", "test DEATHMATCH 0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got candidates %v, want %v", got, want)
+	}
+
+	vote := func(choice int32) {
+		err := stream.Send(&proto.Request{
+			Action: &proto.Request_Vote{Vote: &proto.Vote{Choice: choice}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	vote(1)
+	if votes := receiveMapVote(t, stream).Candidates[1].Votes; votes != 1 {
+		t.Fatalf("got %d votes for the second map, want 1", votes)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		roundStart := resp.GetRoundStart()
+		if roundStart == nil {
+			continue
+		}
+		if !reflect.DeepEqual(roundStart.Map, backend.MapRows(backend.MapArena)) || roundStart.Mode != proto.GameMode_TEAM_DEATHMATCH {
+			t.Fatalf("got mode %s on %v, want team deathmatch on arena", roundStart.Mode, roundStart.Map)
+		}
+		break
+	}
+
+	// The vote is over once the round started.
+	vote(0)
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if message := resp.GetChatMessage(); message != nil {
+			if message.Text != "there is no map vote right now" {
+				t.Fatalf("got message %q, want the vote to be over", message.Text)
+			}
+			break
+		}
+	}
+}
+
+func receiveMapVote(t *testing.T, stream proto.Game_StreamClient) *proto.MapVote {
+	t.Helper()
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mapVote := resp.GetMapVote(); mapVote != nil {
+			return mapVote
+		}
+	}
+}
+
+func receiveUntilError(stream proto.Game_StreamClient) (*proto.Response, error) {
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return resp, err
+		}
+	}
+}
+
+func assertError(t *testing.T, err error, want string) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatalf("got no error, want %q", want)
+	}
+	if got := status.Convert(err).Message(); !strings.Contains(got, want) {
+		t.Fatalf("got error %q, want %q", got, want)
+	}
+}
+
+func TestCountError(t *testing.T) {
+	method := "/test/CountError"
+	countError(method, nil)
+	countError(method, context.Canceled)
+	countError(method, status.Error(codes.Canceled, "client left"))
+	countError(method, context.DeadlineExceeded)
+	countError(method, ErrShuttingDown)
+
+	recorder := httptest.NewRecorder()
+	metrics.Default.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	got := make([]string, 0)
+	for _, line := range strings.Split(recorder.Body.String(), "\n") {
+		if strings.Contains(line, method) {
+			got = append(got, line)
+		}
+	}
+	want := []string{
+		`tshooter_grpc_errors_total{method="/test/CountError",code="DeadlineExceeded"} 1`,
+		`tshooter_grpc_errors_total{method="/test/CountError",code="Unavailable"} 1`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n  %s\nwant\n  %s", strings.Join(got, "\n  "), strings.Join(want, "\n  "))
+	}
+}
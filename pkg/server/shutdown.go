@@ -0,0 +1,90 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	proto "github.com/nikit34/multiplayer_rpg/proto"
+)
+
+// ErrShuttingDown is returned to new connections once the server started to
+// shut down, and ends the streams of the players still connected when it
+// stops.
+var ErrShuttingDown = status.Error(codes.Unavailable, "the server is shutting down")
+
+const shutdownCheckFrequency = time.Second
+
+func (s *GameServer) isShuttingDown() bool {
+	select {
+	case <-s.shutdown:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkShutdown turns away new connections while the server shuts down.
+func (s *GameServer) checkShutdown() error {
+	if s.isShuttingDown() {
+		return ErrShuttingDown
+	}
+	return nil
+}
+
+func (s *GameServer) countClients() int {
+	return s.limits.total()
+}
+
+// Shutdown stops taking new players, tells the connected ones the server
+// stops in grace, and closes every room once grace passed or everybody left.
+// Closing a room saves the replay of the round being played. Shutdown blocks
+// until the rooms are closed, and only the first call does anything.
+func (s *GameServer) Shutdown(grace time.Duration) {
+	started := false
+	s.shutdownOnce.Do(func() {
+		started = true
+		close(s.shutdown)
+	})
+	if !started {
+		return
+	}
+	// Probes see the server draining right away.
+	if healthServer := s.getHealth(); healthServer != nil {
+		healthServer.Shutdown()
+	}
+
+	text := fmt.Sprintf("the server is shutting down in %d seconds", int(grace.Round(time.Second).Seconds()))
+	for _, room := range s.getRooms() {
+		room.broadcast(newChatResponse(&proto.ChatMessage{
+			Channel:    proto.ChatChannel_SYSTEM,
+			SenderName: "server",
+			Text:       text,
+		}))
+	}
+	logger.Info("shutting down", "grace", grace, "clients", s.countClients())
+
+	ticker := time.NewTicker(shutdownCheckFrequency)
+	defer ticker.Stop()
+	deadline := time.After(grace)
+wait:
+	for s.countClients() > 0 {
+		select {
+		case <-deadline:
+			break wait
+		case <-ticker.C:
+		}
+	}
+
+	for _, room := range s.getRooms() {
+		room.mu.RLock()
+		for _, currentClient := range room.clients {
+			currentClient.stop(ErrShuttingDown)
+		}
+		room.mu.RUnlock()
+		room.close(nil)
+	}
+	logger.Info("closed all rooms")
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/nikit34/multiplayer_rpg/pkg/backend"
 )
@@ -40,6 +41,49 @@ func GetProtoDirection(direction backend.Direction) Direction {
 	return protoDirection
 }
 
+func GetBackendGameMode(protoMode GameMode) backend.GameMode {
+	mode := backend.ModeDeathmatch
+	switch protoMode {
+	case GameMode_TEAM_DEATHMATCH:
+		mode = backend.ModeTeamDeathmatch
+	}
+	return mode
+}
+
+func GetProtoGameMode(mode backend.GameMode) GameMode {
+	protoMode := GameMode_DEATHMATCH
+	switch mode {
+	case backend.ModeTeamDeathmatch:
+		protoMode = GameMode_TEAM_DEATHMATCH
+	}
+	return protoMode
+}
+
+// GetBackendRules returns the default rules for servers that do not send
+// their rules.
+func GetBackendRules(protoRules *Rules) backend.Rules {
+	rules := backend.DefaultRules()
+	if protoRules == nil {
+		return rules
+	}
+	rules.RoundOverScore = int(protoRules.RoundOverScore)
+	rules.NewRoundWait = protoRules.NewRoundWait.AsDuration()
+	rules.MoveThrottle = protoRules.MoveThrottle.AsDuration()
+	rules.LaserThrottle = protoRules.LaserThrottle.AsDuration()
+	rules.LaserSpeed = protoRules.LaserSpeed.AsDuration()
+	return rules
+}
+
+func GetProtoRules(rules backend.Rules) *Rules {
+	return &Rules{
+		RoundOverScore: int32(rules.RoundOverScore),
+		NewRoundWait:   durationpb.New(rules.NewRoundWait),
+		MoveThrottle:   durationpb.New(rules.MoveThrottle),
+		LaserThrottle:  durationpb.New(rules.LaserThrottle),
+		LaserSpeed:     durationpb.New(rules.LaserSpeed),
+	}
+}
+
 func GetBackendCoordinate(protoCoordinate *Coordinate) backend.Coordinate {
 	return backend.Coordinate{
 		X: int(protoCoordinate.X),
@@ -65,6 +109,7 @@ func GetBackendPlayer(protoPlayer *Player) *backend.Player {
 		IdentifierBase: backend.IdentifierBase{UUID: entityID},
 		Name:           protoPlayer.Name,
 		Icon:           icon,
+		Team:           int(protoPlayer.Team),
 	}
 	player.Move(GetBackendCoordinate(protoPlayer.Position))
 	return player
@@ -112,6 +157,7 @@ func GetProtoPlayer(player *backend.Player) *Player {
 		Name:     player.Name,
 		Position: GetProtoCoordinate(player.Position()),
 		Icon: string(player.Icon),
+		Team:     int32(player.Team),
 	}
 }
 
@@ -10,6 +10,7 @@ import (
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -21,6 +22,98 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type GameMode int32
+
+const (
+	GameMode_DEATHMATCH      GameMode = 0
+	GameMode_TEAM_DEATHMATCH GameMode = 1
+)
+
+// Enum value maps for GameMode.
+var (
+	GameMode_name = map[int32]string{
+		0: "DEATHMATCH",
+		1: "TEAM_DEATHMATCH",
+	}
+	GameMode_value = map[string]int32{
+		"DEATHMATCH":      0,
+		"TEAM_DEATHMATCH": 1,
+	}
+)
+
+func (x GameMode) Enum() *GameMode {
+	p := new(GameMode)
+	*p = x
+	return p
+}
+
+func (x GameMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GameMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_main_proto_enumTypes[0].Descriptor()
+}
+
+func (GameMode) Type() protoreflect.EnumType {
+	return &file_main_proto_enumTypes[0]
+}
+
+func (x GameMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GameMode.Descriptor instead.
+func (GameMode) EnumDescriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{0}
+}
+
+type QueueState int32
+
+const (
+	QueueState_WAITING QueueState = 0
+	QueueState_MATCHED QueueState = 1
+)
+
+// Enum value maps for QueueState.
+var (
+	QueueState_name = map[int32]string{
+		0: "WAITING",
+		1: "MATCHED",
+	}
+	QueueState_value = map[string]int32{
+		"WAITING": 0,
+		"MATCHED": 1,
+	}
+)
+
+func (x QueueState) Enum() *QueueState {
+	p := new(QueueState)
+	*p = x
+	return p
+}
+
+func (x QueueState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (QueueState) Descriptor() protoreflect.EnumDescriptor {
+	return file_main_proto_enumTypes[1].Descriptor()
+}
+
+func (QueueState) Type() protoreflect.EnumType {
+	return &file_main_proto_enumTypes[1]
+}
+
+func (x QueueState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use QueueState.Descriptor instead.
+func (QueueState) EnumDescriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{1}
+}
+
 type Direction int32
 
 const (
@@ -60,11 +153,11 @@ func (x Direction) String() string {
 }
 
 func (Direction) Descriptor() protoreflect.EnumDescriptor {
-	return file_main_proto_enumTypes[0].Descriptor()
+	return file_main_proto_enumTypes[2].Descriptor()
 }
 
 func (Direction) Type() protoreflect.EnumType {
-	return &file_main_proto_enumTypes[0]
+	return &file_main_proto_enumTypes[2]
 }
 
 func (x Direction) Number() protoreflect.EnumNumber {
@@ -73,7 +166,59 @@ func (x Direction) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Direction.Descriptor instead.
 func (Direction) EnumDescriptor() ([]byte, []int) {
-	return file_main_proto_rawDescGZIP(), []int{0}
+	return file_main_proto_rawDescGZIP(), []int{2}
+}
+
+type ChatChannel int32
+
+const (
+	ChatChannel_GLOBAL  ChatChannel = 0
+	ChatChannel_TEAM    ChatChannel = 1
+	ChatChannel_WHISPER ChatChannel = 2
+	ChatChannel_SYSTEM  ChatChannel = 3
+)
+
+// Enum value maps for ChatChannel.
+var (
+	ChatChannel_name = map[int32]string{
+		0: "GLOBAL",
+		1: "TEAM",
+		2: "WHISPER",
+		3: "SYSTEM",
+	}
+	ChatChannel_value = map[string]int32{
+		"GLOBAL":  0,
+		"TEAM":    1,
+		"WHISPER": 2,
+		"SYSTEM":  3,
+	}
+)
+
+func (x ChatChannel) Enum() *ChatChannel {
+	p := new(ChatChannel)
+	*p = x
+	return p
+}
+
+func (x ChatChannel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChatChannel) Descriptor() protoreflect.EnumDescriptor {
+	return file_main_proto_enumTypes[3].Descriptor()
+}
+
+func (ChatChannel) Type() protoreflect.EnumType {
+	return &file_main_proto_enumTypes[3]
+}
+
+func (x ChatChannel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChatChannel.Descriptor instead.
+func (ChatChannel) EnumDescriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{3}
 }
 
 type ConnectRequest struct {
@@ -81,9 +226,13 @@ type ConnectRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Password string `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Id              string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name            string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Password        string `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	AccountPassword string `protobuf:"bytes,4,opt,name=accountPassword,proto3" json:"accountPassword,omitempty"`
+	RoomId          string `protobuf:"bytes,5,opt,name=roomId,proto3" json:"roomId,omitempty"`
+	RoomPassword    string `protobuf:"bytes,6,opt,name=roomPassword,proto3" json:"roomPassword,omitempty"`
+	Spectator       bool   `protobuf:"varint,7,opt,name=spectator,proto3" json:"spectator,omitempty"`
 }
 
 func (x *ConnectRequest) Reset() {
@@ -139,13 +288,47 @@ func (x *ConnectRequest) GetPassword() string {
 	return ""
 }
 
+func (x *ConnectRequest) GetAccountPassword() string {
+	if x != nil {
+		return x.AccountPassword
+	}
+	return ""
+}
+
+func (x *ConnectRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ConnectRequest) GetRoomPassword() string {
+	if x != nil {
+		return x.RoomPassword
+	}
+	return ""
+}
+
+func (x *ConnectRequest) GetSpectator() bool {
+	if x != nil {
+		return x.Spectator
+	}
+	return false
+}
+
 type ConnectResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Token    string    `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	Entities []*Entity `protobuf:"bytes,2,rep,name=entities,proto3" json:"entities,omitempty"`
+	Token          string               `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Entities       []*Entity            `protobuf:"bytes,2,rep,name=entities,proto3" json:"entities,omitempty"`
+	PlayerId       string               `protobuf:"bytes,3,opt,name=playerId,proto3" json:"playerId,omitempty"`
+	TokenExpiresAt *timestamp.Timestamp `protobuf:"bytes,4,opt,name=tokenExpiresAt,proto3" json:"tokenExpiresAt,omitempty"`
+	RoomId         string               `protobuf:"bytes,5,opt,name=roomId,proto3" json:"roomId,omitempty"`
+	Map            []string             `protobuf:"bytes,6,rep,name=map,proto3" json:"map,omitempty"`
+	Mode           GameMode             `protobuf:"varint,7,opt,name=mode,proto3,enum=proto.GameMode" json:"mode,omitempty"`
+	Rules          *Rules               `protobuf:"bytes,8,opt,name=rules,proto3" json:"rules,omitempty"`
 }
 
 func (x *ConnectResponse) Reset() {
@@ -194,16 +377,60 @@ func (x *ConnectResponse) GetEntities() []*Entity {
 	return nil
 }
 
-type Move struct {
+func (x *ConnectResponse) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *ConnectResponse) GetTokenExpiresAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.TokenExpiresAt
+	}
+	return nil
+}
+
+func (x *ConnectResponse) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ConnectResponse) GetMap() []string {
+	if x != nil {
+		return x.Map
+	}
+	return nil
+}
+
+func (x *ConnectResponse) GetMode() GameMode {
+	if x != nil {
+		return x.Mode
+	}
+	return GameMode_DEATHMATCH
+}
+
+func (x *ConnectResponse) GetRules() *Rules {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type RegisterRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Direction Direction `protobuf:"varint,1,opt,name=direction,proto3,enum=proto.Direction" json:"direction,omitempty"`
+	Name           string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Password       string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	ServerPassword string `protobuf:"bytes,3,opt,name=serverPassword,proto3" json:"serverPassword,omitempty"`
 }
 
-func (x *Move) Reset() {
-	*x = Move{}
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_main_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -211,13 +438,13 @@ func (x *Move) Reset() {
 	}
 }
 
-func (x *Move) String() string {
+func (x *RegisterRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Move) ProtoMessage() {}
+func (*RegisterRequest) ProtoMessage() {}
 
-func (x *Move) ProtoReflect() protoreflect.Message {
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_main_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -229,32 +456,42 @@ func (x *Move) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Move.ProtoReflect.Descriptor instead.
-func (*Move) Descriptor() ([]byte, []int) {
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
 	return file_main_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *Move) GetDirection() Direction {
+func (x *RegisterRequest) GetName() string {
 	if x != nil {
-		return x.Direction
+		return x.Name
 	}
-	return Direction_UP
+	return ""
 }
 
-type Laser struct {
+func (x *RegisterRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetServerPassword() string {
+	if x != nil {
+		return x.ServerPassword
+	}
+	return ""
+}
+
+type RegisterResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id              string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Direction       Direction            `protobuf:"varint,2,opt,name=direction,proto3,enum=proto.Direction" json:"direction,omitempty"`
-	StartTime       *timestamp.Timestamp `protobuf:"bytes,3,opt,name=startTime,proto3" json:"startTime,omitempty"`
-	InitialPosition *Coordinate          `protobuf:"bytes,4,opt,name=initialPosition,proto3" json:"initialPosition,omitempty"`
-	OwnerId         string               `protobuf:"bytes,5,opt,name=ownerId,proto3" json:"ownerId,omitempty"`
+	PlayerId string `protobuf:"bytes,1,opt,name=playerId,proto3" json:"playerId,omitempty"`
 }
 
-func (x *Laser) Reset() {
-	*x = Laser{}
+func (x *RegisterResponse) Reset() {
+	*x = RegisterResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_main_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -262,13 +499,13 @@ func (x *Laser) Reset() {
 	}
 }
 
-func (x *Laser) String() string {
+func (x *RegisterResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Laser) ProtoMessage() {}
+func (*RegisterResponse) ProtoMessage() {}
 
-func (x *Laser) ProtoReflect() protoreflect.Message {
+func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_main_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -280,74 +517,82 @@ func (x *Laser) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Laser.ProtoReflect.Descriptor instead.
-func (*Laser) Descriptor() ([]byte, []int) {
+// Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
+func (*RegisterResponse) Descriptor() ([]byte, []int) {
 	return file_main_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *Laser) GetId() string {
+func (x *RegisterResponse) GetPlayerId() string {
 	if x != nil {
-		return x.Id
+		return x.PlayerId
 	}
 	return ""
 }
 
-func (x *Laser) GetDirection() Direction {
-	if x != nil {
-		return x.Direction
-	}
-	return Direction_UP
+type RefreshTokenRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 }
 
-func (x *Laser) GetStartTime() *timestamp.Timestamp {
-	if x != nil {
-		return x.StartTime
+func (x *RefreshTokenRequest) Reset() {
+	*x = RefreshTokenRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *Laser) GetInitialPosition() *Coordinate {
-	if x != nil {
-		return x.InitialPosition
-	}
-	return nil
+func (x *RefreshTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Laser) GetOwnerId() string {
-	if x != nil {
-		return x.OwnerId
+func (*RefreshTokenRequest) ProtoMessage() {}
+
+func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-type Request struct {
+// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{4}
+}
+
+type RefreshTokenResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Types that are assignable to Action:
-	//	*Request_Move
-	//	*Request_Laser
-	Action isRequest_Action `protobuf_oneof:"action"`
+	Token          string               `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	TokenExpiresAt *timestamp.Timestamp `protobuf:"bytes,2,opt,name=tokenExpiresAt,proto3" json:"tokenExpiresAt,omitempty"`
 }
 
-func (x *Request) Reset() {
-	*x = Request{}
+func (x *RefreshTokenResponse) Reset() {
+	*x = RefreshTokenResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_main_proto_msgTypes[4]
+		mi := &file_main_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Request) String() string {
+func (x *RefreshTokenResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Request) ProtoMessage() {}
+func (*RefreshTokenResponse) ProtoMessage() {}
 
-func (x *Request) ProtoReflect() protoreflect.Message {
-	mi := &file_main_proto_msgTypes[4]
+func (x *RefreshTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -358,74 +603,59 @@ func (x *Request) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Request.ProtoReflect.Descriptor instead.
-func (*Request) Descriptor() ([]byte, []int) {
-	return file_main_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use RefreshTokenResponse.ProtoReflect.Descriptor instead.
+func (*RefreshTokenResponse) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{5}
 }
 
-func (m *Request) GetAction() isRequest_Action {
-	if m != nil {
-		return m.Action
+func (x *RefreshTokenResponse) GetToken() string {
+	if x != nil {
+		return x.Token
 	}
-	return nil
+	return ""
 }
 
-func (x *Request) GetMove() *Move {
-	if x, ok := x.GetAction().(*Request_Move); ok {
-		return x.Move
+func (x *RefreshTokenResponse) GetTokenExpiresAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.TokenExpiresAt
 	}
 	return nil
 }
 
-func (x *Request) GetLaser() *Laser {
-	if x, ok := x.GetAction().(*Request_Laser); ok {
-		return x.Laser
-	}
-	return nil
-}
+type RoomInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-type isRequest_Action interface {
-	isRequest_Action()
+	Id            string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Mode          GameMode `protobuf:"varint,3,opt,name=mode,proto3,enum=proto.GameMode" json:"mode,omitempty"`
+	Map           string   `protobuf:"bytes,4,opt,name=map,proto3" json:"map,omitempty"`
+	Players       int32    `protobuf:"varint,5,opt,name=players,proto3" json:"players,omitempty"`
+	MaxPlayers    int32    `protobuf:"varint,6,opt,name=maxPlayers,proto3" json:"maxPlayers,omitempty"`
+	HasPassword   bool     `protobuf:"varint,7,opt,name=hasPassword,proto3" json:"hasPassword,omitempty"`
+	Bots          int32    `protobuf:"varint,8,opt,name=bots,proto3" json:"bots,omitempty"`
+	Spectators    int32    `protobuf:"varint,9,opt,name=spectators,proto3" json:"spectators,omitempty"`
+	MaxSpectators int32    `protobuf:"varint,10,opt,name=maxSpectators,proto3" json:"maxSpectators,omitempty"`
 }
 
-type Request_Move struct {
-	Move *Move `protobuf:"bytes,1,opt,name=move,proto3,oneof"`
+func (x *RoomInfo) Reset() {
+	*x = RoomInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-type Request_Laser struct {
-	Laser *Laser `protobuf:"bytes,2,opt,name=laser,proto3,oneof"`
-}
-
-func (*Request_Move) isRequest_Action() {}
-
-func (*Request_Laser) isRequest_Action() {}
-
-type Coordinate struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	X int32 `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
-	Y int32 `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
-}
-
-func (x *Coordinate) Reset() {
-	*x = Coordinate{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_main_proto_msgTypes[5]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *Coordinate) String() string {
+func (x *RoomInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Coordinate) ProtoMessage() {}
+func (*RoomInfo) ProtoMessage() {}
 
-func (x *Coordinate) ProtoReflect() protoreflect.Message {
-	mi := &file_main_proto_msgTypes[5]
+func (x *RoomInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -436,109 +666,89 @@ func (x *Coordinate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Coordinate.ProtoReflect.Descriptor instead.
-func (*Coordinate) Descriptor() ([]byte, []int) {
-	return file_main_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use RoomInfo.ProtoReflect.Descriptor instead.
+func (*RoomInfo) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Coordinate) GetX() int32 {
+func (x *RoomInfo) GetId() string {
 	if x != nil {
-		return x.X
+		return x.Id
 	}
-	return 0
+	return ""
 }
 
-func (x *Coordinate) GetY() int32 {
+func (x *RoomInfo) GetName() string {
 	if x != nil {
-		return x.Y
+		return x.Name
 	}
-	return 0
-}
-
-type Player struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Id       string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name     string      `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Position *Coordinate `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
-	Icon     string      `protobuf:"bytes,4,opt,name=icon,proto3" json:"icon,omitempty"`
+	return ""
 }
 
-func (x *Player) Reset() {
-	*x = Player{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_main_proto_msgTypes[6]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *RoomInfo) GetMode() GameMode {
+	if x != nil {
+		return x.Mode
 	}
+	return GameMode_DEATHMATCH
 }
 
-func (x *Player) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *RoomInfo) GetMap() string {
+	if x != nil {
+		return x.Map
+	}
+	return ""
 }
 
-func (*Player) ProtoMessage() {}
-
-func (x *Player) ProtoReflect() protoreflect.Message {
-	mi := &file_main_proto_msgTypes[6]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *RoomInfo) GetPlayers() int32 {
+	if x != nil {
+		return x.Players
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use Player.ProtoReflect.Descriptor instead.
-func (*Player) Descriptor() ([]byte, []int) {
-	return file_main_proto_rawDescGZIP(), []int{6}
+func (x *RoomInfo) GetMaxPlayers() int32 {
+	if x != nil {
+		return x.MaxPlayers
+	}
+	return 0
 }
 
-func (x *Player) GetId() string {
+func (x *RoomInfo) GetHasPassword() bool {
 	if x != nil {
-		return x.Id
+		return x.HasPassword
 	}
-	return ""
+	return false
 }
 
-func (x *Player) GetName() string {
+func (x *RoomInfo) GetBots() int32 {
 	if x != nil {
-		return x.Name
+		return x.Bots
 	}
-	return ""
+	return 0
 }
 
-func (x *Player) GetPosition() *Coordinate {
+func (x *RoomInfo) GetSpectators() int32 {
 	if x != nil {
-		return x.Position
+		return x.Spectators
 	}
-	return nil
+	return 0
 }
 
-func (x *Player) GetIcon() string {
+func (x *RoomInfo) GetMaxSpectators() int32 {
 	if x != nil {
-		return x.Icon
+		return x.MaxSpectators
 	}
-	return ""
+	return 0
 }
 
-type Entity struct {
+type ListRoomsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// Types that are assignable to Entity:
-	//	*Entity_Player
-	//	*Entity_Laser
-	Entity isEntity_Entity `protobuf_oneof:"entity"`
 }
 
-func (x *Entity) Reset() {
-	*x = Entity{}
+func (x *ListRoomsRequest) Reset() {
+	*x = ListRoomsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_main_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -546,13 +756,13 @@ func (x *Entity) Reset() {
 	}
 }
 
-func (x *Entity) String() string {
+func (x *ListRoomsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Entity) ProtoMessage() {}
+func (*ListRoomsRequest) ProtoMessage() {}
 
-func (x *Entity) ProtoReflect() protoreflect.Message {
+func (x *ListRoomsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_main_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -564,58 +774,22 @@ func (x *Entity) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Entity.ProtoReflect.Descriptor instead.
-func (*Entity) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListRoomsRequest.ProtoReflect.Descriptor instead.
+func (*ListRoomsRequest) Descriptor() ([]byte, []int) {
 	return file_main_proto_rawDescGZIP(), []int{7}
 }
 
-func (m *Entity) GetEntity() isEntity_Entity {
-	if m != nil {
-		return m.Entity
-	}
-	return nil
-}
-
-func (x *Entity) GetPlayer() *Player {
-	if x, ok := x.GetEntity().(*Entity_Player); ok {
-		return x.Player
-	}
-	return nil
-}
-
-func (x *Entity) GetLaser() *Laser {
-	if x, ok := x.GetEntity().(*Entity_Laser); ok {
-		return x.Laser
-	}
-	return nil
-}
-
-type isEntity_Entity interface {
-	isEntity_Entity()
-}
-
-type Entity_Player struct {
-	Player *Player `protobuf:"bytes,2,opt,name=player,proto3,oneof"`
-}
-
-type Entity_Laser struct {
-	Laser *Laser `protobuf:"bytes,3,opt,name=laser,proto3,oneof"`
-}
-
-func (*Entity_Player) isEntity_Entity() {}
-
-func (*Entity_Laser) isEntity_Entity() {}
-
-type Initialize struct {
+type ListRoomsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Entities []*Entity `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+	Rooms []*RoomInfo `protobuf:"bytes,1,rep,name=rooms,proto3" json:"rooms,omitempty"`
+	Maps  []string    `protobuf:"bytes,2,rep,name=maps,proto3" json:"maps,omitempty"`
 }
 
-func (x *Initialize) Reset() {
-	*x = Initialize{}
+func (x *ListRoomsResponse) Reset() {
+	*x = ListRoomsResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_main_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -623,13 +797,13 @@ func (x *Initialize) Reset() {
 	}
 }
 
-func (x *Initialize) String() string {
+func (x *ListRoomsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Initialize) ProtoMessage() {}
+func (*ListRoomsResponse) ProtoMessage() {}
 
-func (x *Initialize) ProtoReflect() protoreflect.Message {
+func (x *ListRoomsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_main_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -641,28 +815,40 @@ func (x *Initialize) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Initialize.ProtoReflect.Descriptor instead.
-func (*Initialize) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListRoomsResponse.ProtoReflect.Descriptor instead.
+func (*ListRoomsResponse) Descriptor() ([]byte, []int) {
 	return file_main_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Initialize) GetEntities() []*Entity {
+func (x *ListRoomsResponse) GetRooms() []*RoomInfo {
 	if x != nil {
-		return x.Entities
+		return x.Rooms
 	}
 	return nil
 }
 
-type AddEntity struct {
+func (x *ListRoomsResponse) GetMaps() []string {
+	if x != nil {
+		return x.Maps
+	}
+	return nil
+}
+
+type CreateRoomRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Entity *Entity `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
+	Name           string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Mode           GameMode `protobuf:"varint,2,opt,name=mode,proto3,enum=proto.GameMode" json:"mode,omitempty"`
+	Map            string   `protobuf:"bytes,3,opt,name=map,proto3" json:"map,omitempty"`
+	Password       string   `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	Bots           int32    `protobuf:"varint,5,opt,name=bots,proto3" json:"bots,omitempty"`
+	ServerPassword string   `protobuf:"bytes,6,opt,name=serverPassword,proto3" json:"serverPassword,omitempty"`
 }
 
-func (x *AddEntity) Reset() {
-	*x = AddEntity{}
+func (x *CreateRoomRequest) Reset() {
+	*x = CreateRoomRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_main_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -670,13 +856,13 @@ func (x *AddEntity) Reset() {
 	}
 }
 
-func (x *AddEntity) String() string {
+func (x *CreateRoomRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddEntity) ProtoMessage() {}
+func (*CreateRoomRequest) ProtoMessage() {}
 
-func (x *AddEntity) ProtoReflect() protoreflect.Message {
+func (x *CreateRoomRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_main_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -688,28 +874,63 @@ func (x *AddEntity) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddEntity.ProtoReflect.Descriptor instead.
-func (*AddEntity) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateRoomRequest.ProtoReflect.Descriptor instead.
+func (*CreateRoomRequest) Descriptor() ([]byte, []int) {
 	return file_main_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *AddEntity) GetEntity() *Entity {
+func (x *CreateRoomRequest) GetName() string {
 	if x != nil {
-		return x.Entity
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-type UpdateEntity struct {
+func (x *CreateRoomRequest) GetMode() GameMode {
+	if x != nil {
+		return x.Mode
+	}
+	return GameMode_DEATHMATCH
+}
+
+func (x *CreateRoomRequest) GetMap() string {
+	if x != nil {
+		return x.Map
+	}
+	return ""
+}
+
+func (x *CreateRoomRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateRoomRequest) GetBots() int32 {
+	if x != nil {
+		return x.Bots
+	}
+	return 0
+}
+
+func (x *CreateRoomRequest) GetServerPassword() string {
+	if x != nil {
+		return x.ServerPassword
+	}
+	return ""
+}
+
+type CreateRoomResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Entity *Entity `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
+	Room *RoomInfo `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
 }
 
-func (x *UpdateEntity) Reset() {
-	*x = UpdateEntity{}
+func (x *CreateRoomResponse) Reset() {
+	*x = CreateRoomResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_main_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -717,13 +938,13 @@ func (x *UpdateEntity) Reset() {
 	}
 }
 
-func (x *UpdateEntity) String() string {
+func (x *CreateRoomResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateEntity) ProtoMessage() {}
+func (*CreateRoomResponse) ProtoMessage() {}
 
-func (x *UpdateEntity) ProtoReflect() protoreflect.Message {
+func (x *CreateRoomResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_main_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -735,28 +956,32 @@ func (x *UpdateEntity) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateEntity.ProtoReflect.Descriptor instead.
-func (*UpdateEntity) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateRoomResponse.ProtoReflect.Descriptor instead.
+func (*CreateRoomResponse) Descriptor() ([]byte, []int) {
 	return file_main_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *UpdateEntity) GetEntity() *Entity {
+func (x *CreateRoomResponse) GetRoom() *RoomInfo {
 	if x != nil {
-		return x.Entity
+		return x.Room
 	}
 	return nil
 }
 
-type RemoveEntity struct {
+type MatchmakeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Id              string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name            string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Password        string   `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	AccountPassword string   `protobuf:"bytes,4,opt,name=accountPassword,proto3" json:"accountPassword,omitempty"`
+	Mode            GameMode `protobuf:"varint,5,opt,name=mode,proto3,enum=proto.GameMode" json:"mode,omitempty"`
 }
 
-func (x *RemoveEntity) Reset() {
-	*x = RemoveEntity{}
+func (x *MatchmakeRequest) Reset() {
+	*x = MatchmakeRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_main_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -764,13 +989,13 @@ func (x *RemoveEntity) Reset() {
 	}
 }
 
-func (x *RemoveEntity) String() string {
+func (x *MatchmakeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveEntity) ProtoMessage() {}
+func (*MatchmakeRequest) ProtoMessage() {}
 
-func (x *RemoveEntity) ProtoReflect() protoreflect.Message {
+func (x *MatchmakeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_main_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -782,29 +1007,63 @@ func (x *RemoveEntity) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveEntity.ProtoReflect.Descriptor instead.
-func (*RemoveEntity) Descriptor() ([]byte, []int) {
+// Deprecated: Use MatchmakeRequest.ProtoReflect.Descriptor instead.
+func (*MatchmakeRequest) Descriptor() ([]byte, []int) {
 	return file_main_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *RemoveEntity) GetId() string {
+func (x *MatchmakeRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-type PlayerRespawn struct {
+func (x *MatchmakeRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MatchmakeRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *MatchmakeRequest) GetAccountPassword() string {
+	if x != nil {
+		return x.AccountPassword
+	}
+	return ""
+}
+
+func (x *MatchmakeRequest) GetMode() GameMode {
+	if x != nil {
+		return x.Mode
+	}
+	return GameMode_DEATHMATCH
+}
+
+type QueueStatus struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Player     *Player `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
-	KilledById string  `protobuf:"bytes,2,opt,name=killedById,proto3" json:"killedById,omitempty"`
+	State                QueueState `protobuf:"varint,1,opt,name=state,proto3,enum=proto.QueueState" json:"state,omitempty"`
+	Position             int32      `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	PlayersInQueue       int32      `protobuf:"varint,3,opt,name=playersInQueue,proto3" json:"playersInQueue,omitempty"`
+	Rating               int32      `protobuf:"varint,4,opt,name=rating,proto3" json:"rating,omitempty"`
+	RatingRange          int32      `protobuf:"varint,5,opt,name=ratingRange,proto3" json:"ratingRange,omitempty"`
+	WaitedSeconds        int32      `protobuf:"varint,6,opt,name=waitedSeconds,proto3" json:"waitedSeconds,omitempty"`
+	EstimatedWaitSeconds int32      `protobuf:"varint,7,opt,name=estimatedWaitSeconds,proto3" json:"estimatedWaitSeconds,omitempty"`
+	RoomId               string     `protobuf:"bytes,8,opt,name=roomId,proto3" json:"roomId,omitempty"`
 }
 
-func (x *PlayerRespawn) Reset() {
-	*x = PlayerRespawn{}
+func (x *QueueStatus) Reset() {
+	*x = QueueStatus{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_main_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -812,14 +1071,1186 @@ func (x *PlayerRespawn) Reset() {
 	}
 }
 
-func (x *PlayerRespawn) String() string {
+func (x *QueueStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueStatus) ProtoMessage() {}
+
+func (x *QueueStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueStatus.ProtoReflect.Descriptor instead.
+func (*QueueStatus) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *QueueStatus) GetState() QueueState {
+	if x != nil {
+		return x.State
+	}
+	return QueueState_WAITING
+}
+
+func (x *QueueStatus) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetPlayersInQueue() int32 {
+	if x != nil {
+		return x.PlayersInQueue
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetRatingRange() int32 {
+	if x != nil {
+		return x.RatingRange
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetWaitedSeconds() int32 {
+	if x != nil {
+		return x.WaitedSeconds
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetEstimatedWaitSeconds() int32 {
+	if x != nil {
+		return x.EstimatedWaitSeconds
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+type Move struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Direction Direction `protobuf:"varint,1,opt,name=direction,proto3,enum=proto.Direction" json:"direction,omitempty"`
+}
+
+func (x *Move) Reset() {
+	*x = Move{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Move) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Move) ProtoMessage() {}
+
+func (x *Move) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Move.ProtoReflect.Descriptor instead.
+func (*Move) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Move) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_UP
+}
+
+type Laser struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Direction       Direction            `protobuf:"varint,2,opt,name=direction,proto3,enum=proto.Direction" json:"direction,omitempty"`
+	StartTime       *timestamp.Timestamp `protobuf:"bytes,3,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	InitialPosition *Coordinate          `protobuf:"bytes,4,opt,name=initialPosition,proto3" json:"initialPosition,omitempty"`
+	OwnerId         string               `protobuf:"bytes,5,opt,name=ownerId,proto3" json:"ownerId,omitempty"`
+}
+
+func (x *Laser) Reset() {
+	*x = Laser{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Laser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Laser) ProtoMessage() {}
+
+func (x *Laser) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Laser.ProtoReflect.Descriptor instead.
+func (*Laser) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Laser) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Laser) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_UP
+}
+
+func (x *Laser) GetStartTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *Laser) GetInitialPosition() *Coordinate {
+	if x != nil {
+		return x.InitialPosition
+	}
+	return nil
+}
+
+func (x *Laser) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+type Chat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel   ChatChannel `protobuf:"varint,1,opt,name=channel,proto3,enum=proto.ChatChannel" json:"channel,omitempty"`
+	Text      string      `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Recipient string      `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+}
+
+func (x *Chat) Reset() {
+	*x = Chat{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Chat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chat) ProtoMessage() {}
+
+func (x *Chat) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chat.ProtoReflect.Descriptor instead.
+func (*Chat) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Chat) GetChannel() ChatChannel {
+	if x != nil {
+		return x.Channel
+	}
+	return ChatChannel_GLOBAL
+}
+
+func (x *Chat) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Chat) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+type Pong struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PingSentAt *timestamp.Timestamp `protobuf:"bytes,1,opt,name=pingSentAt,proto3" json:"pingSentAt,omitempty"`
+}
+
+func (x *Pong) Reset() {
+	*x = Pong{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Pong) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pong) ProtoMessage() {}
+
+func (x *Pong) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pong.ProtoReflect.Descriptor instead.
+func (*Pong) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Pong) GetPingSentAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.PingSentAt
+	}
+	return nil
+}
+
+// Vote picks the map of the next round while a round is over, choice is the
+// index of a candidate of the last MapVote.
+type Vote struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Choice int32 `protobuf:"varint,1,opt,name=choice,proto3" json:"choice,omitempty"`
+}
+
+func (x *Vote) Reset() {
+	*x = Vote{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Vote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vote) ProtoMessage() {}
+
+func (x *Vote) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vote.ProtoReflect.Descriptor instead.
+func (*Vote) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Vote) GetChoice() int32 {
+	if x != nil {
+		return x.Choice
+	}
+	return 0
+}
+
+type Request struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Action:
+	//	*Request_Move
+	//	*Request_Laser
+	//	*Request_Chat
+	//	*Request_Pong
+	//	*Request_Vote
+	Action isRequest_Action `protobuf_oneof:"action"`
+}
+
+func (x *Request) Reset() {
+	*x = Request{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Request) ProtoMessage() {}
+
+func (x *Request) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Request.ProtoReflect.Descriptor instead.
+func (*Request) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{18}
+}
+
+func (m *Request) GetAction() isRequest_Action {
+	if m != nil {
+		return m.Action
+	}
+	return nil
+}
+
+func (x *Request) GetMove() *Move {
+	if x, ok := x.GetAction().(*Request_Move); ok {
+		return x.Move
+	}
+	return nil
+}
+
+func (x *Request) GetLaser() *Laser {
+	if x, ok := x.GetAction().(*Request_Laser); ok {
+		return x.Laser
+	}
+	return nil
+}
+
+func (x *Request) GetChat() *Chat {
+	if x, ok := x.GetAction().(*Request_Chat); ok {
+		return x.Chat
+	}
+	return nil
+}
+
+func (x *Request) GetPong() *Pong {
+	if x, ok := x.GetAction().(*Request_Pong); ok {
+		return x.Pong
+	}
+	return nil
+}
+
+func (x *Request) GetVote() *Vote {
+	if x, ok := x.GetAction().(*Request_Vote); ok {
+		return x.Vote
+	}
+	return nil
+}
+
+type isRequest_Action interface {
+	isRequest_Action()
+}
+
+type Request_Move struct {
+	Move *Move `protobuf:"bytes,1,opt,name=move,proto3,oneof"`
+}
+
+type Request_Laser struct {
+	Laser *Laser `protobuf:"bytes,2,opt,name=laser,proto3,oneof"`
+}
+
+type Request_Chat struct {
+	Chat *Chat `protobuf:"bytes,3,opt,name=chat,proto3,oneof"`
+}
+
+type Request_Pong struct {
+	Pong *Pong `protobuf:"bytes,4,opt,name=pong,proto3,oneof"`
+}
+
+type Request_Vote struct {
+	Vote *Vote `protobuf:"bytes,5,opt,name=vote,proto3,oneof"`
+}
+
+func (*Request_Move) isRequest_Action() {}
+
+func (*Request_Laser) isRequest_Action() {}
+
+func (*Request_Chat) isRequest_Action() {}
+
+func (*Request_Pong) isRequest_Action() {}
+
+func (*Request_Vote) isRequest_Action() {}
+
+type Coordinate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X int32 `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y int32 `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+}
+
+func (x *Coordinate) Reset() {
+	*x = Coordinate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Coordinate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coordinate) ProtoMessage() {}
+
+func (x *Coordinate) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coordinate.ProtoReflect.Descriptor instead.
+func (*Coordinate) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Coordinate) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Coordinate) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type Player struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string      `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Position *Coordinate `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	Icon     string      `protobuf:"bytes,4,opt,name=icon,proto3" json:"icon,omitempty"`
+	Team     int32       `protobuf:"varint,5,opt,name=team,proto3" json:"team,omitempty"`
+}
+
+func (x *Player) Reset() {
+	*x = Player{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Player) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Player) ProtoMessage() {}
+
+func (x *Player) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Player.ProtoReflect.Descriptor instead.
+func (*Player) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Player) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Player) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Player) GetPosition() *Coordinate {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *Player) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *Player) GetTeam() int32 {
+	if x != nil {
+		return x.Team
+	}
+	return 0
+}
+
+type Entity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Entity:
+	//	*Entity_Player
+	//	*Entity_Laser
+	Entity isEntity_Entity `protobuf_oneof:"entity"`
+}
+
+func (x *Entity) Reset() {
+	*x = Entity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Entity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entity) ProtoMessage() {}
+
+func (x *Entity) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entity.ProtoReflect.Descriptor instead.
+func (*Entity) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{21}
+}
+
+func (m *Entity) GetEntity() isEntity_Entity {
+	if m != nil {
+		return m.Entity
+	}
+	return nil
+}
+
+func (x *Entity) GetPlayer() *Player {
+	if x, ok := x.GetEntity().(*Entity_Player); ok {
+		return x.Player
+	}
+	return nil
+}
+
+func (x *Entity) GetLaser() *Laser {
+	if x, ok := x.GetEntity().(*Entity_Laser); ok {
+		return x.Laser
+	}
+	return nil
+}
+
+type isEntity_Entity interface {
+	isEntity_Entity()
+}
+
+type Entity_Player struct {
+	Player *Player `protobuf:"bytes,2,opt,name=player,proto3,oneof"`
+}
+
+type Entity_Laser struct {
+	Laser *Laser `protobuf:"bytes,3,opt,name=laser,proto3,oneof"`
+}
+
+func (*Entity_Player) isEntity_Entity() {}
+
+func (*Entity_Laser) isEntity_Entity() {}
+
+type Initialize struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entities []*Entity `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+}
+
+func (x *Initialize) Reset() {
+	*x = Initialize{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Initialize) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Initialize) ProtoMessage() {}
+
+func (x *Initialize) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Initialize.ProtoReflect.Descriptor instead.
+func (*Initialize) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Initialize) GetEntities() []*Entity {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+type AddEntity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entity *Entity `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
+}
+
+func (x *AddEntity) Reset() {
+	*x = AddEntity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddEntity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddEntity) ProtoMessage() {}
+
+func (x *AddEntity) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddEntity.ProtoReflect.Descriptor instead.
+func (*AddEntity) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AddEntity) GetEntity() *Entity {
+	if x != nil {
+		return x.Entity
+	}
+	return nil
+}
+
+type UpdateEntity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entity *Entity `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
+}
+
+func (x *UpdateEntity) Reset() {
+	*x = UpdateEntity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateEntity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEntity) ProtoMessage() {}
+
+func (x *UpdateEntity) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEntity.ProtoReflect.Descriptor instead.
+func (*UpdateEntity) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *UpdateEntity) GetEntity() *Entity {
+	if x != nil {
+		return x.Entity
+	}
+	return nil
+}
+
+type RemoveEntity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *RemoveEntity) Reset() {
+	*x = RemoveEntity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoveEntity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveEntity) ProtoMessage() {}
+
+func (x *RemoveEntity) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveEntity.ProtoReflect.Descriptor instead.
+func (*RemoveEntity) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RemoveEntity) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type PlayerRespawn struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Player     *Player `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+	KilledById string  `protobuf:"bytes,2,opt,name=killedById,proto3" json:"killedById,omitempty"`
+}
+
+func (x *PlayerRespawn) Reset() {
+	*x = PlayerRespawn{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayerRespawn) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
 func (*PlayerRespawn) ProtoMessage() {}
 
-func (x *PlayerRespawn) ProtoReflect() protoreflect.Message {
-	mi := &file_main_proto_msgTypes[12]
+func (x *PlayerRespawn) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerRespawn.ProtoReflect.Descriptor instead.
+func (*PlayerRespawn) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PlayerRespawn) GetPlayer() *Player {
+	if x != nil {
+		return x.Player
+	}
+	return nil
+}
+
+func (x *PlayerRespawn) GetKilledById() string {
+	if x != nil {
+		return x.KilledById
+	}
+	return ""
+}
+
+type RoundOver struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoundWinnerId string               `protobuf:"bytes,1,opt,name=roundWinnerId,proto3" json:"roundWinnerId,omitempty"`
+	NewRoundAt    *timestamp.Timestamp `protobuf:"bytes,2,opt,name=newRoundAt,proto3" json:"newRoundAt,omitempty"`
+}
+
+func (x *RoundOver) Reset() {
+	*x = RoundOver{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoundOver) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoundOver) ProtoMessage() {}
+
+func (x *RoundOver) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoundOver.ProtoReflect.Descriptor instead.
+func (*RoundOver) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RoundOver) GetRoundWinnerId() string {
+	if x != nil {
+		return x.RoundWinnerId
+	}
+	return ""
+}
+
+func (x *RoundOver) GetNewRoundAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.NewRoundAt
+	}
+	return nil
+}
+
+// Rules are the gameplay tuning of a room, clients need them to predict
+// moves and lasers.
+type Rules struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoundOverScore int32                `protobuf:"varint,1,opt,name=roundOverScore,proto3" json:"roundOverScore,omitempty"`
+	NewRoundWait   *durationpb.Duration `protobuf:"bytes,2,opt,name=newRoundWait,proto3" json:"newRoundWait,omitempty"`
+	MoveThrottle   *durationpb.Duration `protobuf:"bytes,3,opt,name=moveThrottle,proto3" json:"moveThrottle,omitempty"`
+	LaserThrottle  *durationpb.Duration `protobuf:"bytes,4,opt,name=laserThrottle,proto3" json:"laserThrottle,omitempty"`
+	LaserSpeed     *durationpb.Duration `protobuf:"bytes,5,opt,name=laserSpeed,proto3" json:"laserSpeed,omitempty"`
+}
+
+func (x *Rules) Reset() {
+	*x = Rules{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Rules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rules) ProtoMessage() {}
+
+func (x *Rules) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rules.ProtoReflect.Descriptor instead.
+func (*Rules) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Rules) GetRoundOverScore() int32 {
+	if x != nil {
+		return x.RoundOverScore
+	}
+	return 0
+}
+
+func (x *Rules) GetNewRoundWait() *durationpb.Duration {
+	if x != nil {
+		return x.NewRoundWait
+	}
+	return nil
+}
+
+func (x *Rules) GetMoveThrottle() *durationpb.Duration {
+	if x != nil {
+		return x.MoveThrottle
+	}
+	return nil
+}
+
+func (x *Rules) GetLaserThrottle() *durationpb.Duration {
+	if x != nil {
+		return x.LaserThrottle
+	}
+	return nil
+}
+
+func (x *Rules) GetLaserSpeed() *durationpb.Duration {
+	if x != nil {
+		return x.LaserSpeed
+	}
+	return nil
+}
+
+type RoundStart struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Players []*Player `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	Map     []string  `protobuf:"bytes,2,rep,name=map,proto3" json:"map,omitempty"`
+	Mode    GameMode  `protobuf:"varint,3,opt,name=mode,proto3,enum=proto.GameMode" json:"mode,omitempty"`
+	Rules   *Rules    `protobuf:"bytes,4,opt,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *RoundStart) Reset() {
+	*x = RoundStart{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoundStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoundStart) ProtoMessage() {}
+
+func (x *RoundStart) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoundStart.ProtoReflect.Descriptor instead.
+func (*RoundStart) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RoundStart) GetPlayers() []*Player {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *RoundStart) GetMap() []string {
+	if x != nil {
+		return x.Map
+	}
+	return nil
+}
+
+func (x *RoundStart) GetMode() GameMode {
+	if x != nil {
+		return x.Mode
+	}
+	return GameMode_DEATHMATCH
+}
+
+func (x *RoundStart) GetRules() *Rules {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type ChatMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel       ChatChannel          `protobuf:"varint,1,opt,name=channel,proto3,enum=proto.ChatChannel" json:"channel,omitempty"`
+	SenderId      string               `protobuf:"bytes,2,opt,name=senderId,proto3" json:"senderId,omitempty"`
+	SenderName    string               `protobuf:"bytes,3,opt,name=senderName,proto3" json:"senderName,omitempty"`
+	RecipientName string               `protobuf:"bytes,4,opt,name=recipientName,proto3" json:"recipientName,omitempty"`
+	Text          string               `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	SentAt        *timestamp.Timestamp `protobuf:"bytes,6,opt,name=sentAt,proto3" json:"sentAt,omitempty"`
+}
+
+func (x *ChatMessage) Reset() {
+	*x = ChatMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessage) ProtoMessage() {}
+
+func (x *ChatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -830,51 +2261,180 @@ func (x *PlayerRespawn) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PlayerRespawn.ProtoReflect.Descriptor instead.
-func (*PlayerRespawn) Descriptor() ([]byte, []int) {
-	return file_main_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
+func (*ChatMessage) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *PlayerRespawn) GetPlayer() *Player {
+func (x *ChatMessage) GetChannel() ChatChannel {
+	if x != nil {
+		return x.Channel
+	}
+	return ChatChannel_GLOBAL
+}
+
+func (x *ChatMessage) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetSenderName() string {
+	if x != nil {
+		return x.SenderName
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetRecipientName() string {
+	if x != nil {
+		return x.RecipientName
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetSentAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+type PlayerJoined struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Player *Player `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+}
+
+func (x *PlayerJoined) Reset() {
+	*x = PlayerJoined{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayerJoined) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerJoined) ProtoMessage() {}
+
+func (x *PlayerJoined) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerJoined.ProtoReflect.Descriptor instead.
+func (*PlayerJoined) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *PlayerJoined) GetPlayer() *Player {
 	if x != nil {
 		return x.Player
 	}
 	return nil
 }
 
-func (x *PlayerRespawn) GetKilledById() string {
+type PlayerLeft struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *PlayerLeft) Reset() {
+	*x = PlayerLeft{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayerLeft) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerLeft) ProtoMessage() {}
+
+func (x *PlayerLeft) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerLeft.ProtoReflect.Descriptor instead.
+func (*PlayerLeft) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *PlayerLeft) GetId() string {
 	if x != nil {
-		return x.KilledById
+		return x.Id
 	}
 	return ""
 }
 
-type RoundOver struct {
+func (x *PlayerLeft) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type Ping struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RoundWinnerId string               `protobuf:"bytes,1,opt,name=roundWinnerId,proto3" json:"roundWinnerId,omitempty"`
-	NewRoundAt    *timestamp.Timestamp `protobuf:"bytes,2,opt,name=newRoundAt,proto3" json:"newRoundAt,omitempty"`
+	SentAt *timestamp.Timestamp `protobuf:"bytes,1,opt,name=sentAt,proto3" json:"sentAt,omitempty"`
 }
 
-func (x *RoundOver) Reset() {
-	*x = RoundOver{}
+func (x *Ping) Reset() {
+	*x = Ping{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_main_proto_msgTypes[13]
+		mi := &file_main_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RoundOver) String() string {
+func (x *Ping) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RoundOver) ProtoMessage() {}
+func (*Ping) ProtoMessage() {}
 
-func (x *RoundOver) ProtoReflect() protoreflect.Message {
-	mi := &file_main_proto_msgTypes[13]
+func (x *Ping) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -885,50 +2445,111 @@ func (x *RoundOver) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RoundOver.ProtoReflect.Descriptor instead.
-func (*RoundOver) Descriptor() ([]byte, []int) {
-	return file_main_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use Ping.ProtoReflect.Descriptor instead.
+func (*Ping) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *RoundOver) GetRoundWinnerId() string {
+func (x *Ping) GetSentAt() *timestamp.Timestamp {
 	if x != nil {
-		return x.RoundWinnerId
+		return x.SentAt
+	}
+	return nil
+}
+
+// LaserAck answers a laser request of the owner. The server picks the laser
+// ID, so clientId is the ID the client proposed for its predicted laser.
+type LaserAck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ClientId string `protobuf:"bytes,1,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	// laser is the laser the server spawned, unset if the shot was rejected.
+	Laser          *Laser `protobuf:"bytes,2,opt,name=laser,proto3" json:"laser,omitempty"`
+	RejectedReason string `protobuf:"bytes,3,opt,name=rejectedReason,proto3" json:"rejectedReason,omitempty"`
+}
+
+func (x *LaserAck) Reset() {
+	*x = LaserAck{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LaserAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LaserAck) ProtoMessage() {}
+
+func (x *LaserAck) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LaserAck.ProtoReflect.Descriptor instead.
+func (*LaserAck) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *LaserAck) GetClientId() string {
+	if x != nil {
+		return x.ClientId
 	}
 	return ""
 }
 
-func (x *RoundOver) GetNewRoundAt() *timestamp.Timestamp {
+func (x *LaserAck) GetLaser() *Laser {
 	if x != nil {
-		return x.NewRoundAt
+		return x.Laser
 	}
 	return nil
 }
 
-type RoundStart struct {
+func (x *LaserAck) GetRejectedReason() string {
+	if x != nil {
+		return x.RejectedReason
+	}
+	return ""
+}
+
+type MapCandidate struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Players []*Player `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	Map   string   `protobuf:"bytes,1,opt,name=map,proto3" json:"map,omitempty"`
+	Mode  GameMode `protobuf:"varint,2,opt,name=mode,proto3,enum=proto.GameMode" json:"mode,omitempty"`
+	Votes int32    `protobuf:"varint,3,opt,name=votes,proto3" json:"votes,omitempty"`
 }
 
-func (x *RoundStart) Reset() {
-	*x = RoundStart{}
+func (x *MapCandidate) Reset() {
+	*x = MapCandidate{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_main_proto_msgTypes[14]
+		mi := &file_main_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RoundStart) String() string {
+func (x *MapCandidate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RoundStart) ProtoMessage() {}
+func (*MapCandidate) ProtoMessage() {}
 
-func (x *RoundStart) ProtoReflect() protoreflect.Message {
-	mi := &file_main_proto_msgTypes[14]
+func (x *MapCandidate) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -939,14 +2560,77 @@ func (x *RoundStart) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RoundStart.ProtoReflect.Descriptor instead.
-func (*RoundStart) Descriptor() ([]byte, []int) {
-	return file_main_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use MapCandidate.ProtoReflect.Descriptor instead.
+func (*MapCandidate) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *RoundStart) GetPlayers() []*Player {
+func (x *MapCandidate) GetMap() string {
 	if x != nil {
-		return x.Players
+		return x.Map
+	}
+	return ""
+}
+
+func (x *MapCandidate) GetMode() GameMode {
+	if x != nil {
+		return x.Mode
+	}
+	return GameMode_DEATHMATCH
+}
+
+func (x *MapCandidate) GetVotes() int32 {
+	if x != nil {
+		return x.Votes
+	}
+	return 0
+}
+
+// MapVote lists the maps players can vote for between rounds with the votes
+// so far. It is sent when a round is over and after every vote.
+type MapVote struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Candidates []*MapCandidate `protobuf:"bytes,1,rep,name=candidates,proto3" json:"candidates,omitempty"`
+}
+
+func (x *MapVote) Reset() {
+	*x = MapVote{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_main_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MapVote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapVote) ProtoMessage() {}
+
+func (x *MapVote) ProtoReflect() protoreflect.Message {
+	mi := &file_main_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapVote.ProtoReflect.Descriptor instead.
+func (*MapVote) Descriptor() ([]byte, []int) {
+	return file_main_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *MapVote) GetCandidates() []*MapCandidate {
+	if x != nil {
+		return x.Candidates
 	}
 	return nil
 }
@@ -963,13 +2647,19 @@ type Response struct {
 	//	*Response_PlayerRespawn
 	//	*Response_RoundOver
 	//	*Response_RoundStart
+	//	*Response_ChatMessage
+	//	*Response_PlayerJoined
+	//	*Response_PlayerLeft
+	//	*Response_Ping
+	//	*Response_LaserAck
+	//	*Response_MapVote
 	Action isResponse_Action `protobuf_oneof:"action"`
 }
 
 func (x *Response) Reset() {
 	*x = Response{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_main_proto_msgTypes[15]
+		mi := &file_main_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -982,7 +2672,7 @@ func (x *Response) String() string {
 func (*Response) ProtoMessage() {}
 
 func (x *Response) ProtoReflect() protoreflect.Message {
-	mi := &file_main_proto_msgTypes[15]
+	mi := &file_main_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -995,7 +2685,7 @@ func (x *Response) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Response.ProtoReflect.Descriptor instead.
 func (*Response) Descriptor() ([]byte, []int) {
-	return file_main_proto_rawDescGZIP(), []int{15}
+	return file_main_proto_rawDescGZIP(), []int{37}
 }
 
 func (m *Response) GetAction() isResponse_Action {
@@ -1047,6 +2737,48 @@ func (x *Response) GetRoundStart() *RoundStart {
 	return nil
 }
 
+func (x *Response) GetChatMessage() *ChatMessage {
+	if x, ok := x.GetAction().(*Response_ChatMessage); ok {
+		return x.ChatMessage
+	}
+	return nil
+}
+
+func (x *Response) GetPlayerJoined() *PlayerJoined {
+	if x, ok := x.GetAction().(*Response_PlayerJoined); ok {
+		return x.PlayerJoined
+	}
+	return nil
+}
+
+func (x *Response) GetPlayerLeft() *PlayerLeft {
+	if x, ok := x.GetAction().(*Response_PlayerLeft); ok {
+		return x.PlayerLeft
+	}
+	return nil
+}
+
+func (x *Response) GetPing() *Ping {
+	if x, ok := x.GetAction().(*Response_Ping); ok {
+		return x.Ping
+	}
+	return nil
+}
+
+func (x *Response) GetLaserAck() *LaserAck {
+	if x, ok := x.GetAction().(*Response_LaserAck); ok {
+		return x.LaserAck
+	}
+	return nil
+}
+
+func (x *Response) GetMapVote() *MapVote {
+	if x, ok := x.GetAction().(*Response_MapVote); ok {
+		return x.MapVote
+	}
+	return nil
+}
+
 type isResponse_Action interface {
 	isResponse_Action()
 }
@@ -1075,6 +2807,30 @@ type Response_RoundStart struct {
 	RoundStart *RoundStart `protobuf:"bytes,6,opt,name=roundStart,proto3,oneof"`
 }
 
+type Response_ChatMessage struct {
+	ChatMessage *ChatMessage `protobuf:"bytes,7,opt,name=chatMessage,proto3,oneof"`
+}
+
+type Response_PlayerJoined struct {
+	PlayerJoined *PlayerJoined `protobuf:"bytes,8,opt,name=playerJoined,proto3,oneof"`
+}
+
+type Response_PlayerLeft struct {
+	PlayerLeft *PlayerLeft `protobuf:"bytes,9,opt,name=playerLeft,proto3,oneof"`
+}
+
+type Response_Ping struct {
+	Ping *Ping `protobuf:"bytes,10,opt,name=ping,proto3,oneof"`
+}
+
+type Response_LaserAck struct {
+	LaserAck *LaserAck `protobuf:"bytes,11,opt,name=laserAck,proto3,oneof"`
+}
+
+type Response_MapVote struct {
+	MapVote *MapVote `protobuf:"bytes,12,opt,name=mapVote,proto3,oneof"`
+}
+
 func (*Response_AddEntity) isResponse_Action() {}
 
 func (*Response_UpdateEntity) isResponse_Action() {}
@@ -1087,23 +2843,145 @@ func (*Response_RoundOver) isResponse_Action() {}
 
 func (*Response_RoundStart) isResponse_Action() {}
 
+func (*Response_ChatMessage) isResponse_Action() {}
+
+func (*Response_PlayerJoined) isResponse_Action() {}
+
+func (*Response_PlayerLeft) isResponse_Action() {}
+
+func (*Response_Ping) isResponse_Action() {}
+
+func (*Response_LaserAck) isResponse_Action() {}
+
+func (*Response_MapVote) isResponse_Action() {}
+
 var File_main_proto protoreflect.FileDescriptor
 
 var file_main_proto_rawDesc = []byte{
 	0x0a, 0x0a, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x50, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd4, 0x01, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x72, 0x6f, 0x6f,
+	0x6d, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x72, 0x6f, 0x6f, 0x6d, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x1c, 0x0a,
+	0x09, 0x73, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x09, 0x73, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72, 0x22, 0xa5, 0x02, 0x0a, 0x0f,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x29, 0x0a, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x12, 0x42, 0x0a, 0x0e,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0e, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x70, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x61, 0x70, 0x12, 0x23, 0x0a, 0x04, 0x6d, 0x6f,
+	0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x47, 0x61, 0x6d, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12,
+	0x22, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x05, 0x72, 0x75,
+	0x6c, 0x65, 0x73, 0x22, 0x69, 0x0a, 0x0f, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61,
-	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61,
-	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x52, 0x0a, 0x0f, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
-	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b,
-	0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12,
-	0x29, 0x0a, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
-	0x52, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x36, 0x0a, 0x04, 0x4d, 0x6f,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x2e,
+	0x0a, 0x10, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x22, 0x15,
+	0x0a, 0x13, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x70, 0x0a, 0x14, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x12, 0x42, 0x0a, 0x0e, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x45, 0x78, 0x70, 0x69,
+	0x72, 0x65, 0x73, 0x41, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0e, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x45, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x22, 0x9b, 0x02, 0x0a, 0x08, 0x52, 0x6f, 0x6f, 0x6d,
+	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47,
+	0x61, 0x6d, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x6d, 0x61, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x61, 0x70, 0x12,
+	0x18, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x61, 0x78,
+	0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6d,
+	0x61, 0x78, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x68, 0x61, 0x73,
+	0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b,
+	0x68, 0x61, 0x73, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x62,
+	0x6f, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x62, 0x6f, 0x74, 0x73, 0x12,
+	0x1e, 0x0a, 0x0a, 0x73, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x12,
+	0x24, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x53, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72, 0x73,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x6d, 0x61, 0x78, 0x53, 0x70, 0x65, 0x63, 0x74,
+	0x61, 0x74, 0x6f, 0x72, 0x73, 0x22, 0x12, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x6f, 0x6f,
+	0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4e, 0x0a, 0x11, 0x4c, 0x69, 0x73,
+	0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25,
+	0x0a, 0x05, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05,
+	0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x61, 0x70, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x61, 0x70, 0x73, 0x22, 0xb6, 0x01, 0x0a, 0x11, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x4d, 0x6f,
+	0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x70, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x61, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x74, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x62, 0x6f, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x22, 0x39, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x04, 0x72, 0x6f, 0x6f, 0x6d,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52,
+	0x6f, 0x6f, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x72, 0x6f, 0x6f, 0x6d, 0x22, 0xa1, 0x01,
+	0x0a, 0x10, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x6d, 0x61, 0x6b, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x61, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x23, 0x0a, 0x04,
+	0x6d, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x22, 0xa6, 0x02, 0x0a, 0x0b, 0x51, 0x75, 0x65, 0x75, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x27, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x73, 0x49, 0x6e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e,
+	0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x49, 0x6e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x72, 0x61, 0x74,
+	0x69, 0x6e, 0x67, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x77, 0x61, 0x69, 0x74,
+	0x65, 0x64, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0d, 0x77, 0x61, 0x69, 0x74, 0x65, 0x64, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x32,
+	0x0a, 0x14, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x57, 0x61, 0x69, 0x74, 0x53,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x14, 0x65, 0x73,
+	0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x57, 0x61, 0x69, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x22, 0x36, 0x0a, 0x04, 0x4d, 0x6f,
 	0x76, 0x65, 0x12, 0x2e, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
 	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x69,
 	0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69,
@@ -1120,92 +2998,228 @@ var file_main_proto_rawDesc = []byte{
 	0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61,
 	0x74, 0x65, 0x52, 0x0f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x50, 0x6f, 0x73, 0x69, 0x74,
 	0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x22, 0x5c, 0x0a,
-	0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x04, 0x6d, 0x6f, 0x76, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d,
-	0x6f, 0x76, 0x65, 0x48, 0x00, 0x52, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x24, 0x0a, 0x05, 0x6c,
-	0x61, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x4c, 0x61, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x05, 0x6c, 0x61, 0x73, 0x65,
-	0x72, 0x42, 0x08, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x28, 0x0a, 0x0a, 0x43,
-	0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x01, 0x79, 0x22, 0x6f, 0x0a, 0x06, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12,
-	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
-	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f,
-	0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x63, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x69, 0x63, 0x6f, 0x6e, 0x22, 0x61, 0x0a, 0x06, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
-	0x12, 0x27, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x48,
-	0x00, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x05, 0x6c, 0x61, 0x73,
-	0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x4c, 0x61, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x05, 0x6c, 0x61, 0x73, 0x65, 0x72, 0x42,
-	0x08, 0x0a, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x37, 0x0a, 0x0a, 0x49, 0x6e, 0x69,
-	0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x12, 0x29, 0x0a, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74,
-	0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69,
-	0x65, 0x73, 0x22, 0x32, 0x0a, 0x09, 0x41, 0x64, 0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12,
-	0x25, 0x0a, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06,
-	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x35, 0x0a, 0x0c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x25, 0x0a, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x1e, 0x0a,
-	0x0c, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x0e, 0x0a,
-	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x56, 0x0a,
-	0x0d, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x61, 0x77, 0x6e, 0x12, 0x25,
-	0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x06, 0x70,
-	0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x6b, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x42,
-	0x79, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6b, 0x69, 0x6c, 0x6c, 0x65,
-	0x64, 0x42, 0x79, 0x49, 0x64, 0x22, 0x6d, 0x0a, 0x09, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x4f, 0x76,
-	0x65, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x57, 0x69, 0x6e, 0x6e, 0x65,
-	0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x6f, 0x75, 0x6e, 0x64,
-	0x57, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x12, 0x3a, 0x0a, 0x0a, 0x6e, 0x65, 0x77, 0x52,
-	0x6f, 0x75, 0x6e, 0x64, 0x41, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x6e, 0x65, 0x77, 0x52, 0x6f, 0x75,
-	0x6e, 0x64, 0x41, 0x74, 0x22, 0x35, 0x0a, 0x0a, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x61,
-	0x72, 0x74, 0x12, 0x27, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6c, 0x61, 0x79,
-	0x65, 0x72, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x22, 0xe1, 0x02, 0x0a, 0x08,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x45,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x00, 0x52,
-	0x09, 0x61, 0x64, 0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x39, 0x0a, 0x0c, 0x75, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x00, 0x52, 0x0c, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x39, 0x0a, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x45,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
-	0x48, 0x00, 0x52, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
-	0x12, 0x3c, 0x0a, 0x0d, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x61, 0x77,
-	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x61, 0x77, 0x6e, 0x48, 0x00, 0x52,
-	0x0d, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x61, 0x77, 0x6e, 0x12, 0x30,
-	0x0a, 0x09, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x4f, 0x76, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x4f,
-	0x76, 0x65, 0x72, 0x48, 0x00, 0x52, 0x09, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x4f, 0x76, 0x65, 0x72,
-	0x12, 0x33, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x6f, 0x75,
-	0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64,
-	0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2a,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x22, 0x66, 0x0a,
+	0x04, 0x43, 0x68, 0x61, 0x74, 0x12, 0x2c, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43,
+	0x68, 0x61, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70,
+	0x69, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x63, 0x69,
+	0x70, 0x69, 0x65, 0x6e, 0x74, 0x22, 0x42, 0x0a, 0x04, 0x50, 0x6f, 0x6e, 0x67, 0x12, 0x3a, 0x0a,
+	0x0a, 0x70, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x6e, 0x74, 0x41, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x70,
+	0x69, 0x6e, 0x67, 0x53, 0x65, 0x6e, 0x74, 0x41, 0x74, 0x22, 0x1e, 0x0a, 0x04, 0x56, 0x6f, 0x74,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x22, 0xc5, 0x01, 0x0a, 0x07, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x6f, 0x76, 0x65,
+	0x48, 0x00, 0x52, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x24, 0x0a, 0x05, 0x6c, 0x61, 0x73, 0x65,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x4c, 0x61, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x05, 0x6c, 0x61, 0x73, 0x65, 0x72, 0x12, 0x21,
+	0x0a, 0x04, 0x63, 0x68, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x48, 0x00, 0x52, 0x04, 0x63, 0x68, 0x61,
+	0x74, 0x12, 0x21, 0x0a, 0x04, 0x70, 0x6f, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6f, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x04,
+	0x70, 0x6f, 0x6e, 0x67, 0x12, 0x21, 0x0a, 0x04, 0x76, 0x6f, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x56, 0x6f, 0x74, 0x65, 0x48,
+	0x00, 0x52, 0x04, 0x76, 0x6f, 0x74, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x22, 0x28, 0x0a, 0x0a, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x12,
+	0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a,
+	0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x79, 0x22, 0x83, 0x01, 0x0a, 0x06,
+	0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x52,
+	0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x63, 0x6f,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x63, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x65, 0x61,
+	0x6d, 0x22, 0x61, 0x0a, 0x06, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x27, 0x0a, 0x06, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x48, 0x00, 0x52, 0x06, 0x70, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x05, 0x6c, 0x61, 0x73, 0x65, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x61, 0x73, 0x65,
+	0x72, 0x48, 0x00, 0x52, 0x05, 0x6c, 0x61, 0x73, 0x65, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x65, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x22, 0x37, 0x0a, 0x0a, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69,
+	0x7a, 0x65, 0x12, 0x29, 0x0a, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x52, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x32, 0x0a,
+	0x09, 0x41, 0x64, 0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x25, 0x0a, 0x06, 0x65, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x22, 0x35, 0x0a, 0x0c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x12, 0x25, 0x0a, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x52, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x1e, 0x0a, 0x0c, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x56, 0x0a, 0x0d, 0x50, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x61, 0x77, 0x6e, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x12, 0x1e, 0x0a, 0x0a, 0x6b, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x42, 0x79, 0x49, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6b, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x42, 0x79, 0x49, 0x64,
+	0x22, 0x6d, 0x0a, 0x09, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x4f, 0x76, 0x65, 0x72, 0x12, 0x24, 0x0a,
+	0x0d, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x57, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x57, 0x69, 0x6e, 0x6e, 0x65,
+	0x72, 0x49, 0x64, 0x12, 0x3a, 0x0a, 0x0a, 0x6e, 0x65, 0x77, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x41,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x0a, 0x6e, 0x65, 0x77, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x74, 0x22,
+	0xa9, 0x02, 0x0a, 0x05, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x72, 0x6f, 0x75,
+	0x6e, 0x64, 0x4f, 0x76, 0x65, 0x72, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0e, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x4f, 0x76, 0x65, 0x72, 0x53, 0x63, 0x6f, 0x72,
+	0x65, 0x12, 0x3d, 0x0a, 0x0c, 0x6e, 0x65, 0x77, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x57, 0x61, 0x69,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x0c, 0x6e, 0x65, 0x77, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x57, 0x61, 0x69, 0x74,
+	0x12, 0x3d, 0x0a, 0x0c, 0x6d, 0x6f, 0x76, 0x65, 0x54, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x0c, 0x6d, 0x6f, 0x76, 0x65, 0x54, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x12,
+	0x3f, 0x0a, 0x0d, 0x6c, 0x61, 0x73, 0x65, 0x72, 0x54, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x0d, 0x6c, 0x61, 0x73, 0x65, 0x72, 0x54, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65,
+	0x12, 0x39, 0x0a, 0x0a, 0x6c, 0x61, 0x73, 0x65, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x0a, 0x6c, 0x61, 0x73, 0x65, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x22, 0x90, 0x01, 0x0a, 0x0a,
+	0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x27, 0x0a, 0x07, 0x70, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x70, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x03, 0x6d, 0x61, 0x70, 0x12, 0x23, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x61, 0x6d, 0x65,
+	0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x72, 0x75,
+	0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0xe5,
+	0x01, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2c,
+	0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1a, 0x0a, 0x08,
+	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x65, 0x63, 0x69,
+	0x70, 0x69, 0x65, 0x6e, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65,
+	0x78, 0x74, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x65, 0x6e, 0x74, 0x41, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x06,
+	0x73, 0x65, 0x6e, 0x74, 0x41, 0x74, 0x22, 0x35, 0x0a, 0x0c, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x22, 0x30, 0x0a,
+	0x0a, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4c, 0x65, 0x66, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22,
+	0x3a, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x65, 0x6e, 0x74, 0x41,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x06, 0x73, 0x65, 0x6e, 0x74, 0x41, 0x74, 0x22, 0x72, 0x0a, 0x08, 0x4c,
+	0x61, 0x73, 0x65, 0x72, 0x41, 0x63, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x05, 0x6c, 0x61, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x61, 0x73, 0x65, 0x72,
+	0x52, 0x05, 0x6c, 0x61, 0x73, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x72, 0x65, 0x6a, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22,
+	0x5b, 0x0a, 0x0c, 0x4d, 0x61, 0x70, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x12,
+	0x10, 0x0a, 0x03, 0x6d, 0x61, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x61,
+	0x70, 0x12, 0x23, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x4d, 0x6f, 0x64, 0x65,
+	0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x22, 0x3e, 0x0a, 0x07,
+	0x4d, 0x61, 0x70, 0x56, 0x6f, 0x74, 0x65, 0x12, 0x33, 0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x64, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x61, 0x70, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x52, 0x0a, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x73, 0x22, 0x87, 0x05, 0x0a,
+	0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x09, 0x61, 0x64, 0x64,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x00,
+	0x52, 0x09, 0x61, 0x64, 0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x39, 0x0a, 0x0c, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x00, 0x52, 0x0c, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x39, 0x0a, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x48, 0x00, 0x52, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x12, 0x3c, 0x0a, 0x0d, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x61,
+	0x77, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x61, 0x77, 0x6e, 0x48, 0x00,
+	0x52, 0x0d, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x61, 0x77, 0x6e, 0x12,
+	0x30, 0x0a, 0x09, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x4f, 0x76, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x6f, 0x75, 0x6e, 0x64,
+	0x4f, 0x76, 0x65, 0x72, 0x48, 0x00, 0x52, 0x09, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x4f, 0x76, 0x65,
+	0x72, 0x12, 0x33, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x6f,
+	0x75, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x72, 0x6f, 0x75, 0x6e,
+	0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x36, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x74, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48,
+	0x00, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x39,
+	0x0a, 0x0c, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0c, 0x70, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x12, 0x33, 0x0a, 0x0a, 0x70, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x4c, 0x65, 0x66, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4c, 0x65, 0x66, 0x74,
+	0x48, 0x00, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4c, 0x65, 0x66, 0x74, 0x12, 0x21,
+	0x0a, 0x04, 0x70, 0x69, 0x6e, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x04, 0x70, 0x69, 0x6e,
+	0x67, 0x12, 0x2d, 0x0a, 0x08, 0x6c, 0x61, 0x73, 0x65, 0x72, 0x41, 0x63, 0x6b, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x61, 0x73, 0x65,
+	0x72, 0x41, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x65, 0x72, 0x41, 0x63, 0x6b,
+	0x12, 0x2a, 0x0a, 0x07, 0x6d, 0x61, 0x70, 0x56, 0x6f, 0x74, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x61, 0x70, 0x56, 0x6f, 0x74,
+	0x65, 0x48, 0x00, 0x52, 0x07, 0x6d, 0x61, 0x70, 0x56, 0x6f, 0x74, 0x65, 0x42, 0x08, 0x0a, 0x06,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2a, 0x2f, 0x0a, 0x08, 0x47, 0x61, 0x6d, 0x65, 0x4d, 0x6f,
+	0x64, 0x65, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x45, 0x41, 0x54, 0x48, 0x4d, 0x41, 0x54, 0x43, 0x48,
+	0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x54, 0x45, 0x41, 0x4d, 0x5f, 0x44, 0x45, 0x41, 0x54, 0x48,
+	0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x01, 0x2a, 0x26, 0x0a, 0x0a, 0x51, 0x75, 0x65, 0x75, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x57, 0x41, 0x49, 0x54, 0x49, 0x4e, 0x47,
+	0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x45, 0x44, 0x10, 0x01, 0x2a,
 	0x3c, 0x0a, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x06, 0x0a, 0x02,
 	0x55, 0x50, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x4f, 0x57, 0x4e, 0x10, 0x01, 0x12, 0x08,
 	0x0a, 0x04, 0x4c, 0x45, 0x46, 0x54, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x49, 0x47, 0x48,
-	0x54, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x54, 0x4f, 0x50, 0x10, 0x04, 0x32, 0x73, 0x0a,
-	0x04, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x3a, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
-	0x12, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x2f, 0x0a, 0x06, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x0e, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01,
-	0x30, 0x01, 0x42, 0x09, 0x5a, 0x07, 0x2e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x54, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x54, 0x4f, 0x50, 0x10, 0x04, 0x2a, 0x3c, 0x0a,
+	0x0b, 0x43, 0x68, 0x61, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x0a, 0x0a, 0x06,
+	0x47, 0x4c, 0x4f, 0x42, 0x41, 0x4c, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x45, 0x41, 0x4d,
+	0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x57, 0x48, 0x49, 0x53, 0x50, 0x45, 0x52, 0x10, 0x02, 0x12,
+	0x0a, 0x0a, 0x06, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x10, 0x03, 0x32, 0xc2, 0x03, 0x0a, 0x04,
+	0x47, 0x61, 0x6d, 0x65, 0x12, 0x3a, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12,
+	0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x3d, 0x0a, 0x08, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x12, 0x16, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x67,
+	0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x49, 0x0a, 0x0c, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12,
+	0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x09, 0x4c, 0x69,
+	0x73, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x73, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x6f, 0x6f,
+	0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0a,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x3c, 0x0a, 0x09, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x6d, 0x61, 0x6b, 0x65, 0x12, 0x17,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x6d, 0x61, 0x6b, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x51, 0x75, 0x65, 0x75, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x00, 0x30, 0x01, 0x12,
+	0x2f, 0x0a, 0x06, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01,
+	0x42, 0x09, 0x5a, 0x07, 0x2e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1220,60 +3234,130 @@ func file_main_proto_rawDescGZIP() []byte {
 	return file_main_proto_rawDescData
 }
 
-var file_main_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_main_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_main_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_main_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_main_proto_goTypes = []interface{}{
-	(Direction)(0),              // 0: proto.Direction
-	(*ConnectRequest)(nil),      // 1: proto.ConnectRequest
-	(*ConnectResponse)(nil),     // 2: proto.ConnectResponse
-	(*Move)(nil),                // 3: proto.Move
-	(*Laser)(nil),               // 4: proto.Laser
-	(*Request)(nil),             // 5: proto.Request
-	(*Coordinate)(nil),          // 6: proto.Coordinate
-	(*Player)(nil),              // 7: proto.Player
-	(*Entity)(nil),              // 8: proto.Entity
-	(*Initialize)(nil),          // 9: proto.Initialize
-	(*AddEntity)(nil),           // 10: proto.AddEntity
-	(*UpdateEntity)(nil),        // 11: proto.UpdateEntity
-	(*RemoveEntity)(nil),        // 12: proto.RemoveEntity
-	(*PlayerRespawn)(nil),       // 13: proto.PlayerRespawn
-	(*RoundOver)(nil),           // 14: proto.RoundOver
-	(*RoundStart)(nil),          // 15: proto.RoundStart
-	(*Response)(nil),            // 16: proto.Response
-	(*timestamp.Timestamp)(nil), // 17: google.protobuf.Timestamp
+	(GameMode)(0),                // 0: proto.GameMode
+	(QueueState)(0),              // 1: proto.QueueState
+	(Direction)(0),               // 2: proto.Direction
+	(ChatChannel)(0),             // 3: proto.ChatChannel
+	(*ConnectRequest)(nil),       // 4: proto.ConnectRequest
+	(*ConnectResponse)(nil),      // 5: proto.ConnectResponse
+	(*RegisterRequest)(nil),      // 6: proto.RegisterRequest
+	(*RegisterResponse)(nil),     // 7: proto.RegisterResponse
+	(*RefreshTokenRequest)(nil),  // 8: proto.RefreshTokenRequest
+	(*RefreshTokenResponse)(nil), // 9: proto.RefreshTokenResponse
+	(*RoomInfo)(nil),             // 10: proto.RoomInfo
+	(*ListRoomsRequest)(nil),     // 11: proto.ListRoomsRequest
+	(*ListRoomsResponse)(nil),    // 12: proto.ListRoomsResponse
+	(*CreateRoomRequest)(nil),    // 13: proto.CreateRoomRequest
+	(*CreateRoomResponse)(nil),   // 14: proto.CreateRoomResponse
+	(*MatchmakeRequest)(nil),     // 15: proto.MatchmakeRequest
+	(*QueueStatus)(nil),          // 16: proto.QueueStatus
+	(*Move)(nil),                 // 17: proto.Move
+	(*Laser)(nil),                // 18: proto.Laser
+	(*Chat)(nil),                 // 19: proto.Chat
+	(*Pong)(nil),                 // 20: proto.Pong
+	(*Vote)(nil),                 // 21: proto.Vote
+	(*Request)(nil),              // 22: proto.Request
+	(*Coordinate)(nil),           // 23: proto.Coordinate
+	(*Player)(nil),               // 24: proto.Player
+	(*Entity)(nil),               // 25: proto.Entity
+	(*Initialize)(nil),           // 26: proto.Initialize
+	(*AddEntity)(nil),            // 27: proto.AddEntity
+	(*UpdateEntity)(nil),         // 28: proto.UpdateEntity
+	(*RemoveEntity)(nil),         // 29: proto.RemoveEntity
+	(*PlayerRespawn)(nil),        // 30: proto.PlayerRespawn
+	(*RoundOver)(nil),            // 31: proto.RoundOver
+	(*Rules)(nil),                // 32: proto.Rules
+	(*RoundStart)(nil),           // 33: proto.RoundStart
+	(*ChatMessage)(nil),          // 34: proto.ChatMessage
+	(*PlayerJoined)(nil),         // 35: proto.PlayerJoined
+	(*PlayerLeft)(nil),           // 36: proto.PlayerLeft
+	(*Ping)(nil),                 // 37: proto.Ping
+	(*LaserAck)(nil),             // 38: proto.LaserAck
+	(*MapCandidate)(nil),         // 39: proto.MapCandidate
+	(*MapVote)(nil),              // 40: proto.MapVote
+	(*Response)(nil),             // 41: proto.Response
+	(*timestamp.Timestamp)(nil),  // 42: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),  // 43: google.protobuf.Duration
 }
 var file_main_proto_depIdxs = []int32{
-	8,  // 0: proto.ConnectResponse.entities:type_name -> proto.Entity
-	0,  // 1: proto.Move.direction:type_name -> proto.Direction
-	0,  // 2: proto.Laser.direction:type_name -> proto.Direction
-	17, // 3: proto.Laser.startTime:type_name -> google.protobuf.Timestamp
-	6,  // 4: proto.Laser.initialPosition:type_name -> proto.Coordinate
-	3,  // 5: proto.Request.move:type_name -> proto.Move
-	4,  // 6: proto.Request.laser:type_name -> proto.Laser
-	6,  // 7: proto.Player.position:type_name -> proto.Coordinate
-	7,  // 8: proto.Entity.player:type_name -> proto.Player
-	4,  // 9: proto.Entity.laser:type_name -> proto.Laser
-	8,  // 10: proto.Initialize.entities:type_name -> proto.Entity
-	8,  // 11: proto.AddEntity.entity:type_name -> proto.Entity
-	8,  // 12: proto.UpdateEntity.entity:type_name -> proto.Entity
-	7,  // 13: proto.PlayerRespawn.player:type_name -> proto.Player
-	17, // 14: proto.RoundOver.newRoundAt:type_name -> google.protobuf.Timestamp
-	7,  // 15: proto.RoundStart.players:type_name -> proto.Player
-	10, // 16: proto.Response.addEntity:type_name -> proto.AddEntity
-	11, // 17: proto.Response.updateEntity:type_name -> proto.UpdateEntity
-	12, // 18: proto.Response.removeEntity:type_name -> proto.RemoveEntity
-	13, // 19: proto.Response.playerRespawn:type_name -> proto.PlayerRespawn
-	14, // 20: proto.Response.roundOver:type_name -> proto.RoundOver
-	15, // 21: proto.Response.roundStart:type_name -> proto.RoundStart
-	1,  // 22: proto.Game.Connect:input_type -> proto.ConnectRequest
-	5,  // 23: proto.Game.Stream:input_type -> proto.Request
-	2,  // 24: proto.Game.Connect:output_type -> proto.ConnectResponse
-	16, // 25: proto.Game.Stream:output_type -> proto.Response
-	24, // [24:26] is the sub-list for method output_type
-	22, // [22:24] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	25, // 0: proto.ConnectResponse.entities:type_name -> proto.Entity
+	42, // 1: proto.ConnectResponse.tokenExpiresAt:type_name -> google.protobuf.Timestamp
+	0,  // 2: proto.ConnectResponse.mode:type_name -> proto.GameMode
+	32, // 3: proto.ConnectResponse.rules:type_name -> proto.Rules
+	42, // 4: proto.RefreshTokenResponse.tokenExpiresAt:type_name -> google.protobuf.Timestamp
+	0,  // 5: proto.RoomInfo.mode:type_name -> proto.GameMode
+	10, // 6: proto.ListRoomsResponse.rooms:type_name -> proto.RoomInfo
+	0,  // 7: proto.CreateRoomRequest.mode:type_name -> proto.GameMode
+	10, // 8: proto.CreateRoomResponse.room:type_name -> proto.RoomInfo
+	0,  // 9: proto.MatchmakeRequest.mode:type_name -> proto.GameMode
+	1,  // 10: proto.QueueStatus.state:type_name -> proto.QueueState
+	2,  // 11: proto.Move.direction:type_name -> proto.Direction
+	2,  // 12: proto.Laser.direction:type_name -> proto.Direction
+	42, // 13: proto.Laser.startTime:type_name -> google.protobuf.Timestamp
+	23, // 14: proto.Laser.initialPosition:type_name -> proto.Coordinate
+	3,  // 15: proto.Chat.channel:type_name -> proto.ChatChannel
+	42, // 16: proto.Pong.pingSentAt:type_name -> google.protobuf.Timestamp
+	17, // 17: proto.Request.move:type_name -> proto.Move
+	18, // 18: proto.Request.laser:type_name -> proto.Laser
+	19, // 19: proto.Request.chat:type_name -> proto.Chat
+	20, // 20: proto.Request.pong:type_name -> proto.Pong
+	21, // 21: proto.Request.vote:type_name -> proto.Vote
+	23, // 22: proto.Player.position:type_name -> proto.Coordinate
+	24, // 23: proto.Entity.player:type_name -> proto.Player
+	18, // 24: proto.Entity.laser:type_name -> proto.Laser
+	25, // 25: proto.Initialize.entities:type_name -> proto.Entity
+	25, // 26: proto.AddEntity.entity:type_name -> proto.Entity
+	25, // 27: proto.UpdateEntity.entity:type_name -> proto.Entity
+	24, // 28: proto.PlayerRespawn.player:type_name -> proto.Player
+	42, // 29: proto.RoundOver.newRoundAt:type_name -> google.protobuf.Timestamp
+	43, // 30: proto.Rules.newRoundWait:type_name -> google.protobuf.Duration
+	43, // 31: proto.Rules.moveThrottle:type_name -> google.protobuf.Duration
+	43, // 32: proto.Rules.laserThrottle:type_name -> google.protobuf.Duration
+	43, // 33: proto.Rules.laserSpeed:type_name -> google.protobuf.Duration
+	24, // 34: proto.RoundStart.players:type_name -> proto.Player
+	0,  // 35: proto.RoundStart.mode:type_name -> proto.GameMode
+	32, // 36: proto.RoundStart.rules:type_name -> proto.Rules
+	3,  // 37: proto.ChatMessage.channel:type_name -> proto.ChatChannel
+	42, // 38: proto.ChatMessage.sentAt:type_name -> google.protobuf.Timestamp
+	24, // 39: proto.PlayerJoined.player:type_name -> proto.Player
+	42, // 40: proto.Ping.sentAt:type_name -> google.protobuf.Timestamp
+	18, // 41: proto.LaserAck.laser:type_name -> proto.Laser
+	0,  // 42: proto.MapCandidate.mode:type_name -> proto.GameMode
+	39, // 43: proto.MapVote.candidates:type_name -> proto.MapCandidate
+	27, // 44: proto.Response.addEntity:type_name -> proto.AddEntity
+	28, // 45: proto.Response.updateEntity:type_name -> proto.UpdateEntity
+	29, // 46: proto.Response.removeEntity:type_name -> proto.RemoveEntity
+	30, // 47: proto.Response.playerRespawn:type_name -> proto.PlayerRespawn
+	31, // 48: proto.Response.roundOver:type_name -> proto.RoundOver
+	33, // 49: proto.Response.roundStart:type_name -> proto.RoundStart
+	34, // 50: proto.Response.chatMessage:type_name -> proto.ChatMessage
+	35, // 51: proto.Response.playerJoined:type_name -> proto.PlayerJoined
+	36, // 52: proto.Response.playerLeft:type_name -> proto.PlayerLeft
+	37, // 53: proto.Response.ping:type_name -> proto.Ping
+	38, // 54: proto.Response.laserAck:type_name -> proto.LaserAck
+	40, // 55: proto.Response.mapVote:type_name -> proto.MapVote
+	4,  // 56: proto.Game.Connect:input_type -> proto.ConnectRequest
+	6,  // 57: proto.Game.Register:input_type -> proto.RegisterRequest
+	8,  // 58: proto.Game.RefreshToken:input_type -> proto.RefreshTokenRequest
+	11, // 59: proto.Game.ListRooms:input_type -> proto.ListRoomsRequest
+	13, // 60: proto.Game.CreateRoom:input_type -> proto.CreateRoomRequest
+	15, // 61: proto.Game.Matchmake:input_type -> proto.MatchmakeRequest
+	22, // 62: proto.Game.Stream:input_type -> proto.Request
+	5,  // 63: proto.Game.Connect:output_type -> proto.ConnectResponse
+	7,  // 64: proto.Game.Register:output_type -> proto.RegisterResponse
+	9,  // 65: proto.Game.RefreshToken:output_type -> proto.RefreshTokenResponse
+	12, // 66: proto.Game.ListRooms:output_type -> proto.ListRoomsResponse
+	14, // 67: proto.Game.CreateRoom:output_type -> proto.CreateRoomResponse
+	16, // 68: proto.Game.Matchmake:output_type -> proto.QueueStatus
+	41, // 69: proto.Game.Stream:output_type -> proto.Response
+	63, // [63:70] is the sub-list for method output_type
+	56, // [56:63] is the sub-list for method input_type
+	56, // [56:56] is the sub-list for extension type_name
+	56, // [56:56] is the sub-list for extension extendee
+	0,  // [0:56] is the sub-list for field type_name
 }
 
 func init() { file_main_proto_init() }
@@ -1307,7 +3391,7 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Move); i {
+			switch v := v.(*RegisterRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1319,7 +3403,7 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Laser); i {
+			switch v := v.(*RegisterResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1331,7 +3415,7 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Request); i {
+			switch v := v.(*RefreshTokenRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1343,7 +3427,7 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Coordinate); i {
+			switch v := v.(*RefreshTokenResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1355,7 +3439,7 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Player); i {
+			switch v := v.(*RoomInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1367,7 +3451,7 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Entity); i {
+			switch v := v.(*ListRoomsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1379,7 +3463,7 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Initialize); i {
+			switch v := v.(*ListRoomsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1391,7 +3475,7 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddEntity); i {
+			switch v := v.(*CreateRoomRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1403,7 +3487,7 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateEntity); i {
+			switch v := v.(*CreateRoomResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1415,7 +3499,7 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveEntity); i {
+			switch v := v.(*MatchmakeRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1427,7 +3511,7 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PlayerRespawn); i {
+			switch v := v.(*QueueStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1439,7 +3523,7 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RoundOver); i {
+			switch v := v.(*Move); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1451,7 +3535,7 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RoundStart); i {
+			switch v := v.(*Laser); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1463,6 +3547,270 @@ func file_main_proto_init() {
 			}
 		}
 		file_main_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Chat); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Pong); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Vote); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Request); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Coordinate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Player); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Entity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Initialize); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddEntity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateEntity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveEntity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayerRespawn); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RoundOver); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Rules); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RoundStart); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayerJoined); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayerLeft); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Ping); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LaserAck); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MapCandidate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MapVote); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_main_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Response); i {
 			case 0:
 				return &v.state
@@ -1475,29 +3823,38 @@ func file_main_proto_init() {
 			}
 		}
 	}
-	file_main_proto_msgTypes[4].OneofWrappers = []interface{}{
+	file_main_proto_msgTypes[18].OneofWrappers = []interface{}{
 		(*Request_Move)(nil),
 		(*Request_Laser)(nil),
+		(*Request_Chat)(nil),
+		(*Request_Pong)(nil),
+		(*Request_Vote)(nil),
 	}
-	file_main_proto_msgTypes[7].OneofWrappers = []interface{}{
+	file_main_proto_msgTypes[21].OneofWrappers = []interface{}{
 		(*Entity_Player)(nil),
 		(*Entity_Laser)(nil),
 	}
-	file_main_proto_msgTypes[15].OneofWrappers = []interface{}{
+	file_main_proto_msgTypes[37].OneofWrappers = []interface{}{
 		(*Response_AddEntity)(nil),
 		(*Response_UpdateEntity)(nil),
 		(*Response_RemoveEntity)(nil),
 		(*Response_PlayerRespawn)(nil),
 		(*Response_RoundOver)(nil),
 		(*Response_RoundStart)(nil),
+		(*Response_ChatMessage)(nil),
+		(*Response_PlayerJoined)(nil),
+		(*Response_PlayerLeft)(nil),
+		(*Response_Ping)(nil),
+		(*Response_LaserAck)(nil),
+		(*Response_MapVote)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_main_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   16,
+			NumEnums:      4,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   1,
 		},